@@ -0,0 +1,8 @@
+package apiv1
+
+import _ "embed" // embedding OpenAPI document
+
+// OpenAPI is an OpenAPI (Swagger 2.0) document of the HTTP API, generated from the Protobuf definition.
+//
+//go:embed product_reviews.swagger.json
+var OpenAPI []byte
@@ -24,12 +24,68 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// ReviewAction defines a change, which has happened to the Review resource.
+type ReviewAction int32
+
+const (
+	ReviewAction_REVIEW_ACTION_UNSPECIFIED ReviewAction = 0
+	ReviewAction_REVIEW_ACTION_CREATED     ReviewAction = 1
+	ReviewAction_REVIEW_ACTION_MODIFIED    ReviewAction = 2
+	ReviewAction_REVIEW_ACTION_DELETED     ReviewAction = 3
+)
+
+// Enum value maps for ReviewAction.
+var (
+	ReviewAction_name = map[int32]string{
+		0: "REVIEW_ACTION_UNSPECIFIED",
+		1: "REVIEW_ACTION_CREATED",
+		2: "REVIEW_ACTION_MODIFIED",
+		3: "REVIEW_ACTION_DELETED",
+	}
+	ReviewAction_value = map[string]int32{
+		"REVIEW_ACTION_UNSPECIFIED": 0,
+		"REVIEW_ACTION_CREATED":     1,
+		"REVIEW_ACTION_MODIFIED":    2,
+		"REVIEW_ACTION_DELETED":     3,
+	}
+)
+
+func (x ReviewAction) Enum() *ReviewAction {
+	p := new(ReviewAction)
+	*p = x
+	return p
+}
+
+func (x ReviewAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReviewAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_product_reviews_proto_enumTypes[0].Descriptor()
+}
+
+func (ReviewAction) Type() protoreflect.EnumType {
+	return &file_api_v1_product_reviews_proto_enumTypes[0]
+}
+
+func (x ReviewAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReviewAction.Descriptor instead.
+func (ReviewAction) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{0}
+}
+
 // Set of messages for Product resource manipulation
 type CreateProductRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Product *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	// Optional key, which makes request idempotent. Retried request with the same key returns originally created Product resource.
+	// It can be also set with the Idempotency-Key header.
+	IdempotencyKey string `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *CreateProductRequest) Reset() {
@@ -69,6 +125,13 @@ func (x *CreateProductRequest) GetProduct() *Product {
 	return nil
 }
 
+func (x *CreateProductRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type CreateProductResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
@@ -377,17 +440,161 @@ func (x *ListProductsResponse) GetProducts() []*Product {
 	return nil
 }
 
-// Set of messages for Review resource manipulation
-type CreateReviewRequest struct {
+type BatchGetProductsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *BatchGetProductsRequest) Reset() {
+	*x = BatchGetProductsRequest{}
+	mi := &file_api_v1_product_reviews_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetProductsRequest) ProtoMessage() {}
+
+func (x *BatchGetProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_reviews_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetProductsRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetProductsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchGetProductsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type BatchGetProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BatchProductResult  `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetProductsResponse) Reset() {
+	*x = BatchGetProductsResponse{}
+	mi := &file_api_v1_product_reviews_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetProductsResponse) ProtoMessage() {}
+
+func (x *BatchGetProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_reviews_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetProductsResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetProductsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchGetProductsResponse) GetResults() []*BatchProductResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// BatchProductResult holds result of a batch operation for a single Product resource.
+type BatchProductResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"` // set when the operation has failed for this Product resource
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchProductResult) Reset() {
+	*x = BatchProductResult{}
+	mi := &file_api_v1_product_reviews_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchProductResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchProductResult) ProtoMessage() {}
+
+func (x *BatchProductResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_reviews_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchProductResult.ProtoReflect.Descriptor instead.
+func (*BatchProductResult) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchProductResult) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *BatchProductResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Set of messages for Review resource manipulation
+type CreateReviewRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Review *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	// Optional key, which makes request idempotent. Retried request with the same key returns originally created Review resource.
+	// It can be also set with the Idempotency-Key header.
+	IdempotencyKey string `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
 func (x *CreateReviewRequest) Reset() {
 	*x = CreateReviewRequest{}
-	mi := &file_api_v1_product_reviews_proto_msgTypes[8]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -399,7 +606,7 @@ func (x *CreateReviewRequest) String() string {
 func (*CreateReviewRequest) ProtoMessage() {}
 
 func (x *CreateReviewRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_product_reviews_proto_msgTypes[8]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -412,7 +619,7 @@ func (x *CreateReviewRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateReviewRequest.ProtoReflect.Descriptor instead.
 func (*CreateReviewRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{8}
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *CreateReviewRequest) GetReview() *Review {
@@ -422,6 +629,13 @@ func (x *CreateReviewRequest) GetReview() *Review {
 	return nil
 }
 
+func (x *CreateReviewRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type CreateReviewResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
@@ -431,7 +645,7 @@ type CreateReviewResponse struct {
 
 func (x *CreateReviewResponse) Reset() {
 	*x = CreateReviewResponse{}
-	mi := &file_api_v1_product_reviews_proto_msgTypes[9]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -443,7 +657,7 @@ func (x *CreateReviewResponse) String() string {
 func (*CreateReviewResponse) ProtoMessage() {}
 
 func (x *CreateReviewResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_product_reviews_proto_msgTypes[9]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -456,7 +670,7 @@ func (x *CreateReviewResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateReviewResponse.ProtoReflect.Descriptor instead.
 func (*CreateReviewResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{9}
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *CreateReviewResponse) GetReview() *Review {
@@ -475,7 +689,7 @@ type EditReviewRequest struct {
 
 func (x *EditReviewRequest) Reset() {
 	*x = EditReviewRequest{}
-	mi := &file_api_v1_product_reviews_proto_msgTypes[10]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -487,7 +701,7 @@ func (x *EditReviewRequest) String() string {
 func (*EditReviewRequest) ProtoMessage() {}
 
 func (x *EditReviewRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_product_reviews_proto_msgTypes[10]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -500,7 +714,7 @@ func (x *EditReviewRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EditReviewRequest.ProtoReflect.Descriptor instead.
 func (*EditReviewRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{10}
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *EditReviewRequest) GetReview() *Review {
@@ -519,7 +733,7 @@ type EditReviewResponse struct {
 
 func (x *EditReviewResponse) Reset() {
 	*x = EditReviewResponse{}
-	mi := &file_api_v1_product_reviews_proto_msgTypes[11]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -531,7 +745,7 @@ func (x *EditReviewResponse) String() string {
 func (*EditReviewResponse) ProtoMessage() {}
 
 func (x *EditReviewResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_product_reviews_proto_msgTypes[11]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -544,7 +758,7 @@ func (x *EditReviewResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EditReviewResponse.ProtoReflect.Descriptor instead.
 func (*EditReviewResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{11}
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *EditReviewResponse) GetReview() *Review {
@@ -563,7 +777,7 @@ type DeleteReviewRequest struct {
 
 func (x *DeleteReviewRequest) Reset() {
 	*x = DeleteReviewRequest{}
-	mi := &file_api_v1_product_reviews_proto_msgTypes[12]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -575,7 +789,7 @@ func (x *DeleteReviewRequest) String() string {
 func (*DeleteReviewRequest) ProtoMessage() {}
 
 func (x *DeleteReviewRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_product_reviews_proto_msgTypes[12]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -588,7 +802,7 @@ func (x *DeleteReviewRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteReviewRequest.ProtoReflect.Descriptor instead.
 func (*DeleteReviewRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{12}
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *DeleteReviewRequest) GetId() string {
@@ -607,7 +821,7 @@ type GetReviewsByProductIDRequest struct {
 
 func (x *GetReviewsByProductIDRequest) Reset() {
 	*x = GetReviewsByProductIDRequest{}
-	mi := &file_api_v1_product_reviews_proto_msgTypes[13]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -619,7 +833,7 @@ func (x *GetReviewsByProductIDRequest) String() string {
 func (*GetReviewsByProductIDRequest) ProtoMessage() {}
 
 func (x *GetReviewsByProductIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_product_reviews_proto_msgTypes[13]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -632,7 +846,7 @@ func (x *GetReviewsByProductIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReviewsByProductIDRequest.ProtoReflect.Descriptor instead.
 func (*GetReviewsByProductIDRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{13}
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetReviewsByProductIDRequest) GetId() string {
@@ -651,7 +865,7 @@ type GetReviewsByProductIDResponse struct {
 
 func (x *GetReviewsByProductIDResponse) Reset() {
 	*x = GetReviewsByProductIDResponse{}
-	mi := &file_api_v1_product_reviews_proto_msgTypes[14]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -663,7 +877,7 @@ func (x *GetReviewsByProductIDResponse) String() string {
 func (*GetReviewsByProductIDResponse) ProtoMessage() {}
 
 func (x *GetReviewsByProductIDResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_product_reviews_proto_msgTypes[14]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -676,7 +890,7 @@ func (x *GetReviewsByProductIDResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReviewsByProductIDResponse.ProtoReflect.Descriptor instead.
 func (*GetReviewsByProductIDResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{14}
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetReviewsByProductIDResponse) GetReviews() []*Review {
@@ -686,6 +900,339 @@ func (x *GetReviewsByProductIDResponse) GetReviews() []*Review {
 	return nil
 }
 
+type BatchCreateReviewsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reviews       []*Review              `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateReviewsRequest) Reset() {
+	*x = BatchCreateReviewsRequest{}
+	mi := &file_api_v1_product_reviews_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateReviewsRequest) ProtoMessage() {}
+
+func (x *BatchCreateReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_reviews_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateReviewsRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *BatchCreateReviewsRequest) GetReviews() []*Review {
+	if x != nil {
+		return x.Reviews
+	}
+	return nil
+}
+
+type BatchCreateReviewsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BatchReviewResult   `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateReviewsResponse) Reset() {
+	*x = BatchCreateReviewsResponse{}
+	mi := &file_api_v1_product_reviews_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateReviewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateReviewsResponse) ProtoMessage() {}
+
+func (x *BatchCreateReviewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_reviews_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateReviewsResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateReviewsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *BatchCreateReviewsResponse) GetResults() []*BatchReviewResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type BatchDeleteReviewsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteReviewsRequest) Reset() {
+	*x = BatchDeleteReviewsRequest{}
+	mi := &file_api_v1_product_reviews_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteReviewsRequest) ProtoMessage() {}
+
+func (x *BatchDeleteReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_reviews_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteReviewsRequest.ProtoReflect.Descriptor instead.
+func (*BatchDeleteReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *BatchDeleteReviewsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type BatchDeleteReviewsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BatchReviewResult   `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteReviewsResponse) Reset() {
+	*x = BatchDeleteReviewsResponse{}
+	mi := &file_api_v1_product_reviews_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteReviewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteReviewsResponse) ProtoMessage() {}
+
+func (x *BatchDeleteReviewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_reviews_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteReviewsResponse.ProtoReflect.Descriptor instead.
+func (*BatchDeleteReviewsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BatchDeleteReviewsResponse) GetResults() []*BatchReviewResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// BatchReviewResult holds result of a batch operation for a single Review resource.
+type BatchReviewResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"` // set when the operation has failed for this Review resource
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchReviewResult) Reset() {
+	*x = BatchReviewResult{}
+	mi := &file_api_v1_product_reviews_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchReviewResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchReviewResult) ProtoMessage() {}
+
+func (x *BatchReviewResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_reviews_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchReviewResult.ProtoReflect.Descriptor instead.
+func (*BatchReviewResult) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BatchReviewResult) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+func (x *BatchReviewResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type WatchProductReviewsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchProductReviewsRequest) Reset() {
+	*x = WatchProductReviewsRequest{}
+	mi := &file_api_v1_product_reviews_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchProductReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchProductReviewsRequest) ProtoMessage() {}
+
+func (x *WatchProductReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_reviews_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchProductReviewsRequest.ProtoReflect.Descriptor instead.
+func (*WatchProductReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *WatchProductReviewsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type WatchProductReviewsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        ReviewAction           `protobuf:"varint,1,opt,name=action,proto3,enum=api.v1.ReviewAction" json:"action,omitempty"`
+	Review        *Review                `protobuf:"bytes,2,opt,name=review,proto3" json:"review,omitempty"`
+	AverageRating float64                `protobuf:"fixed64,3,opt,name=average_rating,json=averageRating,proto3" json:"average_rating,omitempty"` // average rating of the Product after the change
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchProductReviewsResponse) Reset() {
+	*x = WatchProductReviewsResponse{}
+	mi := &file_api_v1_product_reviews_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchProductReviewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchProductReviewsResponse) ProtoMessage() {}
+
+func (x *WatchProductReviewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_product_reviews_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchProductReviewsResponse.ProtoReflect.Descriptor instead.
+func (*WatchProductReviewsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *WatchProductReviewsResponse) GetAction() ReviewAction {
+	if x != nil {
+		return x.Action
+	}
+	return ReviewAction_REVIEW_ACTION_UNSPECIFIED
+}
+
+func (x *WatchProductReviewsResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+func (x *WatchProductReviewsResponse) GetAverageRating() float64 {
+	if x != nil {
+		return x.AverageRating
+	}
+	return 0
+}
+
 // Modelling DB resources below.
 // Product resource definition.
 type Product struct {
@@ -703,7 +1250,7 @@ type Product struct {
 
 func (x *Product) Reset() {
 	*x = Product{}
-	mi := &file_api_v1_product_reviews_proto_msgTypes[15]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -715,7 +1262,7 @@ func (x *Product) String() string {
 func (*Product) ProtoMessage() {}
 
 func (x *Product) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_product_reviews_proto_msgTypes[15]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -728,7 +1275,7 @@ func (x *Product) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Product.ProtoReflect.Descriptor instead.
 func (*Product) Descriptor() ([]byte, []int) {
-	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{15}
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *Product) GetId() string {
@@ -789,7 +1336,7 @@ type Review struct {
 
 func (x *Review) Reset() {
 	*x = Review{}
-	mi := &file_api_v1_product_reviews_proto_msgTypes[16]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -801,7 +1348,7 @@ func (x *Review) String() string {
 func (*Review) ProtoMessage() {}
 
 func (x *Review) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_product_reviews_proto_msgTypes[16]
+	mi := &file_api_v1_product_reviews_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -814,7 +1361,7 @@ func (x *Review) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Review.ProtoReflect.Descriptor instead.
 func (*Review) Descriptor() ([]byte, []int) {
-	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{16}
+	return file_api_v1_product_reviews_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *Review) GetId() string {
@@ -863,9 +1410,10 @@ var File_api_v1_product_reviews_proto protoreflect.FileDescriptor
 
 const file_api_v1_product_reviews_proto_rawDesc = "" +
 	"\n" +
-	"\x1capi/v1/product_reviews.proto\x12\x06api.v1\x1a\x15api/v1/ent/opts.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/api/annotations.proto\"A\n" +
+	"\x1capi/v1/product_reviews.proto\x12\x06api.v1\x1a\x15api/v1/ent/opts.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/api/annotations.proto\"j\n" +
 	"\x14CreateProductRequest\x12)\n" +
-	"\aproduct\x18\x01 \x01(\v2\x0f.api.v1.ProductR\aproduct\"B\n" +
+	"\aproduct\x18\x01 \x01(\v2\x0f.api.v1.ProductR\aproduct\x12'\n" +
+	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\"B\n" +
 	"\x15CreateProductResponse\x12)\n" +
 	"\aproduct\x18\x01 \x01(\v2\x0f.api.v1.ProductR\aproduct\"'\n" +
 	"\x15GetProductByIDRequest\x12\x0e\n" +
@@ -879,9 +1427,17 @@ const file_api_v1_product_reviews_proto_rawDesc = "" +
 	"\x14DeleteProductRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"C\n" +
 	"\x14ListProductsResponse\x12+\n" +
-	"\bproducts\x18\x01 \x03(\v2\x0f.api.v1.ProductR\bproducts\"=\n" +
+	"\bproducts\x18\x01 \x03(\v2\x0f.api.v1.ProductR\bproducts\"+\n" +
+	"\x17BatchGetProductsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"P\n" +
+	"\x18BatchGetProductsResponse\x124\n" +
+	"\aresults\x18\x01 \x03(\v2\x1a.api.v1.BatchProductResultR\aresults\"U\n" +
+	"\x12BatchProductResult\x12)\n" +
+	"\aproduct\x18\x01 \x01(\v2\x0f.api.v1.ProductR\aproduct\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"f\n" +
 	"\x13CreateReviewRequest\x12&\n" +
-	"\x06review\x18\x01 \x01(\v2\x0e.api.v1.ReviewR\x06review\">\n" +
+	"\x06review\x18\x01 \x01(\v2\x0e.api.v1.ReviewR\x06review\x12'\n" +
+	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\">\n" +
 	"\x14CreateReviewResponse\x12&\n" +
 	"\x06review\x18\x01 \x01(\v2\x0e.api.v1.ReviewR\x06review\";\n" +
 	"\x11EditReviewRequest\x12&\n" +
@@ -893,7 +1449,25 @@ const file_api_v1_product_reviews_proto_rawDesc = "" +
 	"\x1cGetReviewsByProductIDRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"I\n" +
 	"\x1dGetReviewsByProductIDResponse\x12(\n" +
-	"\areviews\x18\x01 \x03(\v2\x0e.api.v1.ReviewR\areviews\"\xc4\x01\n" +
+	"\areviews\x18\x01 \x03(\v2\x0e.api.v1.ReviewR\areviews\"E\n" +
+	"\x19BatchCreateReviewsRequest\x12(\n" +
+	"\areviews\x18\x01 \x03(\v2\x0e.api.v1.ReviewR\areviews\"Q\n" +
+	"\x1aBatchCreateReviewsResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.api.v1.BatchReviewResultR\aresults\"-\n" +
+	"\x19BatchDeleteReviewsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"Q\n" +
+	"\x1aBatchDeleteReviewsResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.api.v1.BatchReviewResultR\aresults\"Q\n" +
+	"\x11BatchReviewResult\x12&\n" +
+	"\x06review\x18\x01 \x01(\v2\x0e.api.v1.ReviewR\x06review\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\";\n" +
+	"\x1aWatchProductReviewsRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"\x9a\x01\n" +
+	"\x1bWatchProductReviewsResponse\x12,\n" +
+	"\x06action\x18\x01 \x01(\x0e2\x14.api.v1.ReviewActionR\x06action\x12&\n" +
+	"\x06review\x18\x02 \x01(\v2\x0e.api.v1.ReviewR\x06review\x12%\n" +
+	"\x0eaverage_rating\x18\x03 \x01(\x01R\raverageRating\"\xc4\x01\n" +
 	"\aProduct\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
@@ -910,18 +1484,27 @@ const file_api_v1_product_reviews_proto_rawDesc = "" +
 	"reviewText\x12\x16\n" +
 	"\x06rating\x18\x05 \x01(\x05R\x06rating\x12:\n" +
 	"\aproduct\x18\n" +
-	" \x01(\v2\x0f.api.v1.ProductB\x0f¦I\v\b\x01\x12\areviewsR\aproduct:\x06\xba\xa6I\x02\b\x012\xd2\a\n" +
+	" \x01(\v2\x0f.api.v1.ProductB\x0f¦I\v\b\x01\x12\areviewsR\aproduct:\x06\xba\xa6I\x02\b\x01*\x7f\n" +
+	"\fReviewAction\x12\x1d\n" +
+	"\x19REVIEW_ACTION_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15REVIEW_ACTION_CREATED\x10\x01\x12\x1a\n" +
+	"\x16REVIEW_ACTION_MODIFIED\x10\x02\x12\x19\n" +
+	"\x15REVIEW_ACTION_DELETED\x10\x032\xdc\v\n" +
 	"\x15ProductReviewsService\x12k\n" +
 	"\rCreateProduct\x12\x1c.api.v1.CreateProductRequest\x1a\x1d.api.v1.CreateProductResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/product/create\x12m\n" +
 	"\x0eGetProductByID\x12\x1d.api.v1.GetProductByIDRequest\x1a\x1e.api.v1.GetProductByIDResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/product/get/{id}\x12c\n" +
 	"\vEditProduct\x12\x1a.api.v1.EditProductRequest\x1a\x1b.api.v1.EditProductResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*2\x10/v1/product/edit\x12b\n" +
-	"\rDeleteProduct\x12\x1c.api.v1.DeleteProductRequest\x1a\x16.google.protobuf.Empty\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01**\x10/v1/product/{id}\x12]\n" +
+	"\rDeleteProduct\x12\x1c.api.v1.DeleteProductRequest\x1a\x16.google.protobuf.Empty\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01**\x10/v1/product/{id}\x12t\n" +
+	"\x10BatchGetProducts\x12\x1f.api.v1.BatchGetProductsRequest\x1a .api.v1.BatchGetProductsResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/product/batch/get\x12]\n" +
 	"\fListProducts\x12\x16.google.protobuf.Empty\x1a\x1c.api.v1.ListProductsResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/product/all\x12g\n" +
-	"\fCreateReview\x12\x1b.api.v1.CreateReviewRequest\x1a\x1c.api.v1.CreateReviewResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/review/create\x12\x89\x01\n" +
+	"\fCreateReview\x12\x1b.api.v1.CreateReviewRequest\x1a\x1c.api.v1.CreateReviewResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/review/create\x12\x7f\n" +
+	"\x12BatchCreateReviews\x12!.api.v1.BatchCreateReviewsRequest\x1a\".api.v1.BatchCreateReviewsResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/review/batch/create\x12\x89\x01\n" +
 	"\x15GetReviewsByProductID\x12$.api.v1.GetReviewsByProductIDRequest\x1a%.api.v1.GetReviewsByProductIDResponse\"#\x82\xd3\xe4\x93\x02\x1d\x12\x1b/v1/review/get/product/{id}\x12_\n" +
 	"\n" +
 	"EditReview\x12\x19.api.v1.EditReviewRequest\x1a\x1a.api.v1.EditReviewResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*2\x0f/v1/review/edit\x12_\n" +
-	"\fDeleteReview\x12\x1b.api.v1.DeleteReviewRequest\x1a\x16.google.protobuf.Empty\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01**\x0f/v1/review/{id}B<Z:github.com/eroshiva/cloudtalk/api/v1/product-reviews;apiv1b\x06proto3"
+	"\fDeleteReview\x12\x1b.api.v1.DeleteReviewRequest\x1a\x16.google.protobuf.Empty\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01**\x0f/v1/review/{id}\x12\x7f\n" +
+	"\x12BatchDeleteReviews\x12!.api.v1.BatchDeleteReviewsRequest\x1a\".api.v1.BatchDeleteReviewsResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/review/batch/delete\x12\x8f\x01\n" +
+	"\x13WatchProductReviews\x12\".api.v1.WatchProductReviewsRequest\x1a#.api.v1.WatchProductReviewsResponse\"-\x82\xd3\xe4\x93\x02'\x12%/v1/review/watch/product/{product_id}0\x01B<Z:github.com/eroshiva/cloudtalk/api/v1/product-reviews;apiv1b\x06proto3"
 
 var (
 	file_api_v1_product_reviews_proto_rawDescOnce sync.Once
@@ -935,64 +1518,92 @@ func file_api_v1_product_reviews_proto_rawDescGZIP() []byte {
 	return file_api_v1_product_reviews_proto_rawDescData
 }
 
-var file_api_v1_product_reviews_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_api_v1_product_reviews_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_v1_product_reviews_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_api_v1_product_reviews_proto_goTypes = []any{
-	(*CreateProductRequest)(nil),          // 0: api.v1.CreateProductRequest
-	(*CreateProductResponse)(nil),         // 1: api.v1.CreateProductResponse
-	(*GetProductByIDRequest)(nil),         // 2: api.v1.GetProductByIDRequest
-	(*GetProductByIDResponse)(nil),        // 3: api.v1.GetProductByIDResponse
-	(*EditProductRequest)(nil),            // 4: api.v1.EditProductRequest
-	(*EditProductResponse)(nil),           // 5: api.v1.EditProductResponse
-	(*DeleteProductRequest)(nil),          // 6: api.v1.DeleteProductRequest
-	(*ListProductsResponse)(nil),          // 7: api.v1.ListProductsResponse
-	(*CreateReviewRequest)(nil),           // 8: api.v1.CreateReviewRequest
-	(*CreateReviewResponse)(nil),          // 9: api.v1.CreateReviewResponse
-	(*EditReviewRequest)(nil),             // 10: api.v1.EditReviewRequest
-	(*EditReviewResponse)(nil),            // 11: api.v1.EditReviewResponse
-	(*DeleteReviewRequest)(nil),           // 12: api.v1.DeleteReviewRequest
-	(*GetReviewsByProductIDRequest)(nil),  // 13: api.v1.GetReviewsByProductIDRequest
-	(*GetReviewsByProductIDResponse)(nil), // 14: api.v1.GetReviewsByProductIDResponse
-	(*Product)(nil),                       // 15: api.v1.Product
-	(*Review)(nil),                        // 16: api.v1.Review
-	(*emptypb.Empty)(nil),                 // 17: google.protobuf.Empty
+	(ReviewAction)(0),                     // 0: api.v1.ReviewAction
+	(*CreateProductRequest)(nil),          // 1: api.v1.CreateProductRequest
+	(*CreateProductResponse)(nil),         // 2: api.v1.CreateProductResponse
+	(*GetProductByIDRequest)(nil),         // 3: api.v1.GetProductByIDRequest
+	(*GetProductByIDResponse)(nil),        // 4: api.v1.GetProductByIDResponse
+	(*EditProductRequest)(nil),            // 5: api.v1.EditProductRequest
+	(*EditProductResponse)(nil),           // 6: api.v1.EditProductResponse
+	(*DeleteProductRequest)(nil),          // 7: api.v1.DeleteProductRequest
+	(*ListProductsResponse)(nil),          // 8: api.v1.ListProductsResponse
+	(*BatchGetProductsRequest)(nil),       // 9: api.v1.BatchGetProductsRequest
+	(*BatchGetProductsResponse)(nil),      // 10: api.v1.BatchGetProductsResponse
+	(*BatchProductResult)(nil),            // 11: api.v1.BatchProductResult
+	(*CreateReviewRequest)(nil),           // 12: api.v1.CreateReviewRequest
+	(*CreateReviewResponse)(nil),          // 13: api.v1.CreateReviewResponse
+	(*EditReviewRequest)(nil),             // 14: api.v1.EditReviewRequest
+	(*EditReviewResponse)(nil),            // 15: api.v1.EditReviewResponse
+	(*DeleteReviewRequest)(nil),           // 16: api.v1.DeleteReviewRequest
+	(*GetReviewsByProductIDRequest)(nil),  // 17: api.v1.GetReviewsByProductIDRequest
+	(*GetReviewsByProductIDResponse)(nil), // 18: api.v1.GetReviewsByProductIDResponse
+	(*BatchCreateReviewsRequest)(nil),     // 19: api.v1.BatchCreateReviewsRequest
+	(*BatchCreateReviewsResponse)(nil),    // 20: api.v1.BatchCreateReviewsResponse
+	(*BatchDeleteReviewsRequest)(nil),     // 21: api.v1.BatchDeleteReviewsRequest
+	(*BatchDeleteReviewsResponse)(nil),    // 22: api.v1.BatchDeleteReviewsResponse
+	(*BatchReviewResult)(nil),             // 23: api.v1.BatchReviewResult
+	(*WatchProductReviewsRequest)(nil),    // 24: api.v1.WatchProductReviewsRequest
+	(*WatchProductReviewsResponse)(nil),   // 25: api.v1.WatchProductReviewsResponse
+	(*Product)(nil),                       // 26: api.v1.Product
+	(*Review)(nil),                        // 27: api.v1.Review
+	(*emptypb.Empty)(nil),                 // 28: google.protobuf.Empty
 }
 var file_api_v1_product_reviews_proto_depIdxs = []int32{
-	15, // 0: api.v1.CreateProductRequest.product:type_name -> api.v1.Product
-	15, // 1: api.v1.CreateProductResponse.product:type_name -> api.v1.Product
-	15, // 2: api.v1.GetProductByIDResponse.product:type_name -> api.v1.Product
-	15, // 3: api.v1.EditProductRequest.product:type_name -> api.v1.Product
-	15, // 4: api.v1.EditProductResponse.product:type_name -> api.v1.Product
-	15, // 5: api.v1.ListProductsResponse.products:type_name -> api.v1.Product
-	16, // 6: api.v1.CreateReviewRequest.review:type_name -> api.v1.Review
-	16, // 7: api.v1.CreateReviewResponse.review:type_name -> api.v1.Review
-	16, // 8: api.v1.EditReviewRequest.review:type_name -> api.v1.Review
-	16, // 9: api.v1.EditReviewResponse.review:type_name -> api.v1.Review
-	16, // 10: api.v1.GetReviewsByProductIDResponse.reviews:type_name -> api.v1.Review
-	16, // 11: api.v1.Product.reviews:type_name -> api.v1.Review
-	15, // 12: api.v1.Review.product:type_name -> api.v1.Product
-	0,  // 13: api.v1.ProductReviewsService.CreateProduct:input_type -> api.v1.CreateProductRequest
-	2,  // 14: api.v1.ProductReviewsService.GetProductByID:input_type -> api.v1.GetProductByIDRequest
-	4,  // 15: api.v1.ProductReviewsService.EditProduct:input_type -> api.v1.EditProductRequest
-	6,  // 16: api.v1.ProductReviewsService.DeleteProduct:input_type -> api.v1.DeleteProductRequest
-	17, // 17: api.v1.ProductReviewsService.ListProducts:input_type -> google.protobuf.Empty
-	8,  // 18: api.v1.ProductReviewsService.CreateReview:input_type -> api.v1.CreateReviewRequest
-	13, // 19: api.v1.ProductReviewsService.GetReviewsByProductID:input_type -> api.v1.GetReviewsByProductIDRequest
-	10, // 20: api.v1.ProductReviewsService.EditReview:input_type -> api.v1.EditReviewRequest
-	12, // 21: api.v1.ProductReviewsService.DeleteReview:input_type -> api.v1.DeleteReviewRequest
-	1,  // 22: api.v1.ProductReviewsService.CreateProduct:output_type -> api.v1.CreateProductResponse
-	3,  // 23: api.v1.ProductReviewsService.GetProductByID:output_type -> api.v1.GetProductByIDResponse
-	5,  // 24: api.v1.ProductReviewsService.EditProduct:output_type -> api.v1.EditProductResponse
-	17, // 25: api.v1.ProductReviewsService.DeleteProduct:output_type -> google.protobuf.Empty
-	7,  // 26: api.v1.ProductReviewsService.ListProducts:output_type -> api.v1.ListProductsResponse
-	9,  // 27: api.v1.ProductReviewsService.CreateReview:output_type -> api.v1.CreateReviewResponse
-	14, // 28: api.v1.ProductReviewsService.GetReviewsByProductID:output_type -> api.v1.GetReviewsByProductIDResponse
-	11, // 29: api.v1.ProductReviewsService.EditReview:output_type -> api.v1.EditReviewResponse
-	17, // 30: api.v1.ProductReviewsService.DeleteReview:output_type -> google.protobuf.Empty
-	22, // [22:31] is the sub-list for method output_type
-	13, // [13:22] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	26, // 0: api.v1.CreateProductRequest.product:type_name -> api.v1.Product
+	26, // 1: api.v1.CreateProductResponse.product:type_name -> api.v1.Product
+	26, // 2: api.v1.GetProductByIDResponse.product:type_name -> api.v1.Product
+	26, // 3: api.v1.EditProductRequest.product:type_name -> api.v1.Product
+	26, // 4: api.v1.EditProductResponse.product:type_name -> api.v1.Product
+	26, // 5: api.v1.ListProductsResponse.products:type_name -> api.v1.Product
+	11, // 6: api.v1.BatchGetProductsResponse.results:type_name -> api.v1.BatchProductResult
+	26, // 7: api.v1.BatchProductResult.product:type_name -> api.v1.Product
+	27, // 8: api.v1.CreateReviewRequest.review:type_name -> api.v1.Review
+	27, // 9: api.v1.CreateReviewResponse.review:type_name -> api.v1.Review
+	27, // 10: api.v1.EditReviewRequest.review:type_name -> api.v1.Review
+	27, // 11: api.v1.EditReviewResponse.review:type_name -> api.v1.Review
+	27, // 12: api.v1.GetReviewsByProductIDResponse.reviews:type_name -> api.v1.Review
+	27, // 13: api.v1.BatchCreateReviewsRequest.reviews:type_name -> api.v1.Review
+	23, // 14: api.v1.BatchCreateReviewsResponse.results:type_name -> api.v1.BatchReviewResult
+	23, // 15: api.v1.BatchDeleteReviewsResponse.results:type_name -> api.v1.BatchReviewResult
+	27, // 16: api.v1.BatchReviewResult.review:type_name -> api.v1.Review
+	0,  // 17: api.v1.WatchProductReviewsResponse.action:type_name -> api.v1.ReviewAction
+	27, // 18: api.v1.WatchProductReviewsResponse.review:type_name -> api.v1.Review
+	27, // 19: api.v1.Product.reviews:type_name -> api.v1.Review
+	26, // 20: api.v1.Review.product:type_name -> api.v1.Product
+	1,  // 21: api.v1.ProductReviewsService.CreateProduct:input_type -> api.v1.CreateProductRequest
+	3,  // 22: api.v1.ProductReviewsService.GetProductByID:input_type -> api.v1.GetProductByIDRequest
+	5,  // 23: api.v1.ProductReviewsService.EditProduct:input_type -> api.v1.EditProductRequest
+	7,  // 24: api.v1.ProductReviewsService.DeleteProduct:input_type -> api.v1.DeleteProductRequest
+	9,  // 25: api.v1.ProductReviewsService.BatchGetProducts:input_type -> api.v1.BatchGetProductsRequest
+	28, // 26: api.v1.ProductReviewsService.ListProducts:input_type -> google.protobuf.Empty
+	12, // 27: api.v1.ProductReviewsService.CreateReview:input_type -> api.v1.CreateReviewRequest
+	19, // 28: api.v1.ProductReviewsService.BatchCreateReviews:input_type -> api.v1.BatchCreateReviewsRequest
+	17, // 29: api.v1.ProductReviewsService.GetReviewsByProductID:input_type -> api.v1.GetReviewsByProductIDRequest
+	14, // 30: api.v1.ProductReviewsService.EditReview:input_type -> api.v1.EditReviewRequest
+	16, // 31: api.v1.ProductReviewsService.DeleteReview:input_type -> api.v1.DeleteReviewRequest
+	21, // 32: api.v1.ProductReviewsService.BatchDeleteReviews:input_type -> api.v1.BatchDeleteReviewsRequest
+	24, // 33: api.v1.ProductReviewsService.WatchProductReviews:input_type -> api.v1.WatchProductReviewsRequest
+	2,  // 34: api.v1.ProductReviewsService.CreateProduct:output_type -> api.v1.CreateProductResponse
+	4,  // 35: api.v1.ProductReviewsService.GetProductByID:output_type -> api.v1.GetProductByIDResponse
+	6,  // 36: api.v1.ProductReviewsService.EditProduct:output_type -> api.v1.EditProductResponse
+	28, // 37: api.v1.ProductReviewsService.DeleteProduct:output_type -> google.protobuf.Empty
+	10, // 38: api.v1.ProductReviewsService.BatchGetProducts:output_type -> api.v1.BatchGetProductsResponse
+	8,  // 39: api.v1.ProductReviewsService.ListProducts:output_type -> api.v1.ListProductsResponse
+	13, // 40: api.v1.ProductReviewsService.CreateReview:output_type -> api.v1.CreateReviewResponse
+	20, // 41: api.v1.ProductReviewsService.BatchCreateReviews:output_type -> api.v1.BatchCreateReviewsResponse
+	18, // 42: api.v1.ProductReviewsService.GetReviewsByProductID:output_type -> api.v1.GetReviewsByProductIDResponse
+	15, // 43: api.v1.ProductReviewsService.EditReview:output_type -> api.v1.EditReviewResponse
+	28, // 44: api.v1.ProductReviewsService.DeleteReview:output_type -> google.protobuf.Empty
+	22, // 45: api.v1.ProductReviewsService.BatchDeleteReviews:output_type -> api.v1.BatchDeleteReviewsResponse
+	25, // 46: api.v1.ProductReviewsService.WatchProductReviews:output_type -> api.v1.WatchProductReviewsResponse
+	34, // [34:47] is the sub-list for method output_type
+	21, // [21:34] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_product_reviews_proto_init() }
@@ -1005,13 +1616,14 @@ func file_api_v1_product_reviews_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_product_reviews_proto_rawDesc), len(file_api_v1_product_reviews_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   17,
+			NumEnums:      1,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_v1_product_reviews_proto_goTypes,
 		DependencyIndexes: file_api_v1_product_reviews_proto_depIdxs,
+		EnumInfos:         file_api_v1_product_reviews_proto_enumTypes,
 		MessageInfos:      file_api_v1_product_reviews_proto_msgTypes,
 	}.Build()
 	File_api_v1_product_reviews_proto = out.File
@@ -86,6 +86,8 @@ func (m *CreateProductRequest) validate(all bool) error {
 		}
 	}
 
+	// no validation rules for IdempotencyKey
+
 	if len(errors) > 0 {
 		return CreateProductRequestMultiError(errors)
 	}
@@ -1034,71 +1036,42 @@ var _ interface {
 	ErrorName() string
 } = ListProductsResponseValidationError{}
 
-// Validate checks the field values on CreateReviewRequest with the rules
+// Validate checks the field values on BatchGetProductsRequest with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
-func (m *CreateReviewRequest) Validate() error {
+func (m *BatchGetProductsRequest) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on CreateReviewRequest with the rules
-// defined in the proto definition for this message. If any rules are
+// ValidateAll checks the field values on BatchGetProductsRequest with the
+// rules defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// CreateReviewRequestMultiError, or nil if none found.
-func (m *CreateReviewRequest) ValidateAll() error {
+// BatchGetProductsRequestMultiError, or nil if none found.
+func (m *BatchGetProductsRequest) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *CreateReviewRequest) validate(all bool) error {
+func (m *BatchGetProductsRequest) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	if all {
-		switch v := interface{}(m.GetReview()).(type) {
-		case interface{ ValidateAll() error }:
-			if err := v.ValidateAll(); err != nil {
-				errors = append(errors, CreateReviewRequestValidationError{
-					field:  "Review",
-					reason: "embedded message failed validation",
-					cause:  err,
-				})
-			}
-		case interface{ Validate() error }:
-			if err := v.Validate(); err != nil {
-				errors = append(errors, CreateReviewRequestValidationError{
-					field:  "Review",
-					reason: "embedded message failed validation",
-					cause:  err,
-				})
-			}
-		}
-	} else if v, ok := interface{}(m.GetReview()).(interface{ Validate() error }); ok {
-		if err := v.Validate(); err != nil {
-			return CreateReviewRequestValidationError{
-				field:  "Review",
-				reason: "embedded message failed validation",
-				cause:  err,
-			}
-		}
-	}
-
 	if len(errors) > 0 {
-		return CreateReviewRequestMultiError(errors)
+		return BatchGetProductsRequestMultiError(errors)
 	}
 
 	return nil
 }
 
-// CreateReviewRequestMultiError is an error wrapping multiple validation
-// errors returned by CreateReviewRequest.ValidateAll() if the designated
+// BatchGetProductsRequestMultiError is an error wrapping multiple validation
+// errors returned by BatchGetProductsRequest.ValidateAll() if the designated
 // constraints aren't met.
-type CreateReviewRequestMultiError []error
+type BatchGetProductsRequestMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m CreateReviewRequestMultiError) Error() string {
+func (m BatchGetProductsRequestMultiError) Error() string {
 	msgs := make([]string, 0, len(m))
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -1107,11 +1080,11 @@ func (m CreateReviewRequestMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m CreateReviewRequestMultiError) AllErrors() []error { return m }
+func (m BatchGetProductsRequestMultiError) AllErrors() []error { return m }
 
-// CreateReviewRequestValidationError is the validation error returned by
-// CreateReviewRequest.Validate if the designated constraints aren't met.
-type CreateReviewRequestValidationError struct {
+// BatchGetProductsRequestValidationError is the validation error returned by
+// BatchGetProductsRequest.Validate if the designated constraints aren't met.
+type BatchGetProductsRequestValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -1119,24 +1092,24 @@ type CreateReviewRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e CreateReviewRequestValidationError) Field() string { return e.field }
+func (e BatchGetProductsRequestValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e CreateReviewRequestValidationError) Reason() string { return e.reason }
+func (e BatchGetProductsRequestValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e CreateReviewRequestValidationError) Cause() error { return e.cause }
+func (e BatchGetProductsRequestValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e CreateReviewRequestValidationError) Key() bool { return e.key }
+func (e BatchGetProductsRequestValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e CreateReviewRequestValidationError) ErrorName() string {
-	return "CreateReviewRequestValidationError"
+func (e BatchGetProductsRequestValidationError) ErrorName() string {
+	return "BatchGetProductsRequestValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e CreateReviewRequestValidationError) Error() string {
+func (e BatchGetProductsRequestValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -1148,14 +1121,14 @@ func (e CreateReviewRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sCreateReviewRequest.%s: %s%s",
+		"invalid %sBatchGetProductsRequest.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = CreateReviewRequestValidationError{}
+var _ error = BatchGetProductsRequestValidationError{}
 
 var _ interface {
 	Field() string
@@ -1163,73 +1136,78 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = CreateReviewRequestValidationError{}
+} = BatchGetProductsRequestValidationError{}
 
-// Validate checks the field values on CreateReviewResponse with the rules
+// Validate checks the field values on BatchGetProductsResponse with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
-func (m *CreateReviewResponse) Validate() error {
+func (m *BatchGetProductsResponse) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on CreateReviewResponse with the rules
-// defined in the proto definition for this message. If any rules are
+// ValidateAll checks the field values on BatchGetProductsResponse with the
+// rules defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// CreateReviewResponseMultiError, or nil if none found.
-func (m *CreateReviewResponse) ValidateAll() error {
+// BatchGetProductsResponseMultiError, or nil if none found.
+func (m *BatchGetProductsResponse) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *CreateReviewResponse) validate(all bool) error {
+func (m *BatchGetProductsResponse) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	if all {
-		switch v := interface{}(m.GetReview()).(type) {
-		case interface{ ValidateAll() error }:
-			if err := v.ValidateAll(); err != nil {
-				errors = append(errors, CreateReviewResponseValidationError{
-					field:  "Review",
-					reason: "embedded message failed validation",
-					cause:  err,
-				})
+	for idx, item := range m.GetResults() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, BatchGetProductsResponseValidationError{
+						field:  fmt.Sprintf("Results[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, BatchGetProductsResponseValidationError{
+						field:  fmt.Sprintf("Results[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
 			}
-		case interface{ Validate() error }:
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
 			if err := v.Validate(); err != nil {
-				errors = append(errors, CreateReviewResponseValidationError{
-					field:  "Review",
+				return BatchGetProductsResponseValidationError{
+					field:  fmt.Sprintf("Results[%v]", idx),
 					reason: "embedded message failed validation",
 					cause:  err,
-				})
-			}
-		}
-	} else if v, ok := interface{}(m.GetReview()).(interface{ Validate() error }); ok {
-		if err := v.Validate(); err != nil {
-			return CreateReviewResponseValidationError{
-				field:  "Review",
-				reason: "embedded message failed validation",
-				cause:  err,
+				}
 			}
 		}
+
 	}
 
 	if len(errors) > 0 {
-		return CreateReviewResponseMultiError(errors)
+		return BatchGetProductsResponseMultiError(errors)
 	}
 
 	return nil
 }
 
-// CreateReviewResponseMultiError is an error wrapping multiple validation
-// errors returned by CreateReviewResponse.ValidateAll() if the designated
+// BatchGetProductsResponseMultiError is an error wrapping multiple validation
+// errors returned by BatchGetProductsResponse.ValidateAll() if the designated
 // constraints aren't met.
-type CreateReviewResponseMultiError []error
+type BatchGetProductsResponseMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m CreateReviewResponseMultiError) Error() string {
+func (m BatchGetProductsResponseMultiError) Error() string {
 	msgs := make([]string, 0, len(m))
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -1238,11 +1216,11 @@ func (m CreateReviewResponseMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m CreateReviewResponseMultiError) AllErrors() []error { return m }
+func (m BatchGetProductsResponseMultiError) AllErrors() []error { return m }
 
-// CreateReviewResponseValidationError is the validation error returned by
-// CreateReviewResponse.Validate if the designated constraints aren't met.
-type CreateReviewResponseValidationError struct {
+// BatchGetProductsResponseValidationError is the validation error returned by
+// BatchGetProductsResponse.Validate if the designated constraints aren't met.
+type BatchGetProductsResponseValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -1250,24 +1228,24 @@ type CreateReviewResponseValidationError struct {
 }
 
 // Field function returns field value.
-func (e CreateReviewResponseValidationError) Field() string { return e.field }
+func (e BatchGetProductsResponseValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e CreateReviewResponseValidationError) Reason() string { return e.reason }
+func (e BatchGetProductsResponseValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e CreateReviewResponseValidationError) Cause() error { return e.cause }
+func (e BatchGetProductsResponseValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e CreateReviewResponseValidationError) Key() bool { return e.key }
+func (e BatchGetProductsResponseValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e CreateReviewResponseValidationError) ErrorName() string {
-	return "CreateReviewResponseValidationError"
+func (e BatchGetProductsResponseValidationError) ErrorName() string {
+	return "BatchGetProductsResponseValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e CreateReviewResponseValidationError) Error() string {
+func (e BatchGetProductsResponseValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -1279,14 +1257,14 @@ func (e CreateReviewResponseValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sCreateReviewResponse.%s: %s%s",
+		"invalid %sBatchGetProductsResponse.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = CreateReviewResponseValidationError{}
+var _ error = BatchGetProductsResponseValidationError{}
 
 var _ interface {
 	Field() string
@@ -1294,24 +1272,24 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = CreateReviewResponseValidationError{}
+} = BatchGetProductsResponseValidationError{}
 
-// Validate checks the field values on EditReviewRequest with the rules defined
-// in the proto definition for this message. If any rules are violated, the
-// first error encountered is returned, or nil if there are no violations.
-func (m *EditReviewRequest) Validate() error {
+// Validate checks the field values on BatchProductResult with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *BatchProductResult) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on EditReviewRequest with the rules
+// ValidateAll checks the field values on BatchProductResult with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// EditReviewRequestMultiError, or nil if none found.
-func (m *EditReviewRequest) ValidateAll() error {
+// BatchProductResultMultiError, or nil if none found.
+func (m *BatchProductResult) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *EditReviewRequest) validate(all bool) error {
+func (m *BatchProductResult) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
@@ -1319,48 +1297,50 @@ func (m *EditReviewRequest) validate(all bool) error {
 	var errors []error
 
 	if all {
-		switch v := interface{}(m.GetReview()).(type) {
+		switch v := interface{}(m.GetProduct()).(type) {
 		case interface{ ValidateAll() error }:
 			if err := v.ValidateAll(); err != nil {
-				errors = append(errors, EditReviewRequestValidationError{
-					field:  "Review",
+				errors = append(errors, BatchProductResultValidationError{
+					field:  "Product",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		case interface{ Validate() error }:
 			if err := v.Validate(); err != nil {
-				errors = append(errors, EditReviewRequestValidationError{
-					field:  "Review",
+				errors = append(errors, BatchProductResultValidationError{
+					field:  "Product",
 					reason: "embedded message failed validation",
 					cause:  err,
 				})
 			}
 		}
-	} else if v, ok := interface{}(m.GetReview()).(interface{ Validate() error }); ok {
+	} else if v, ok := interface{}(m.GetProduct()).(interface{ Validate() error }); ok {
 		if err := v.Validate(); err != nil {
-			return EditReviewRequestValidationError{
-				field:  "Review",
+			return BatchProductResultValidationError{
+				field:  "Product",
 				reason: "embedded message failed validation",
 				cause:  err,
 			}
 		}
 	}
 
+	// no validation rules for Error
+
 	if len(errors) > 0 {
-		return EditReviewRequestMultiError(errors)
+		return BatchProductResultMultiError(errors)
 	}
 
 	return nil
 }
 
-// EditReviewRequestMultiError is an error wrapping multiple validation errors
-// returned by EditReviewRequest.ValidateAll() if the designated constraints
+// BatchProductResultMultiError is an error wrapping multiple validation errors
+// returned by BatchProductResult.ValidateAll() if the designated constraints
 // aren't met.
-type EditReviewRequestMultiError []error
+type BatchProductResultMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m EditReviewRequestMultiError) Error() string {
+func (m BatchProductResultMultiError) Error() string {
 	msgs := make([]string, 0, len(m))
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -1369,11 +1349,11 @@ func (m EditReviewRequestMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m EditReviewRequestMultiError) AllErrors() []error { return m }
+func (m BatchProductResultMultiError) AllErrors() []error { return m }
 
-// EditReviewRequestValidationError is the validation error returned by
-// EditReviewRequest.Validate if the designated constraints aren't met.
-type EditReviewRequestValidationError struct {
+// BatchProductResultValidationError is the validation error returned by
+// BatchProductResult.Validate if the designated constraints aren't met.
+type BatchProductResultValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -1381,24 +1361,24 @@ type EditReviewRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e EditReviewRequestValidationError) Field() string { return e.field }
+func (e BatchProductResultValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e EditReviewRequestValidationError) Reason() string { return e.reason }
+func (e BatchProductResultValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e EditReviewRequestValidationError) Cause() error { return e.cause }
+func (e BatchProductResultValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e EditReviewRequestValidationError) Key() bool { return e.key }
+func (e BatchProductResultValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e EditReviewRequestValidationError) ErrorName() string {
-	return "EditReviewRequestValidationError"
+func (e BatchProductResultValidationError) ErrorName() string {
+	return "BatchProductResultValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e EditReviewRequestValidationError) Error() string {
+func (e BatchProductResultValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -1410,14 +1390,14 @@ func (e EditReviewRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sEditReviewRequest.%s: %s%s",
+		"invalid %sBatchProductResult.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = EditReviewRequestValidationError{}
+var _ error = BatchProductResultValidationError{}
 
 var _ interface {
 	Field() string
@@ -1425,24 +1405,24 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = EditReviewRequestValidationError{}
+} = BatchProductResultValidationError{}
 
-// Validate checks the field values on EditReviewResponse with the rules
+// Validate checks the field values on CreateReviewRequest with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
-func (m *EditReviewResponse) Validate() error {
+func (m *CreateReviewRequest) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on EditReviewResponse with the rules
+// ValidateAll checks the field values on CreateReviewRequest with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// EditReviewResponseMultiError, or nil if none found.
-func (m *EditReviewResponse) ValidateAll() error {
+// CreateReviewRequestMultiError, or nil if none found.
+func (m *CreateReviewRequest) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *EditReviewResponse) validate(all bool) error {
+func (m *CreateReviewRequest) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
@@ -1453,7 +1433,7 @@ func (m *EditReviewResponse) validate(all bool) error {
 		switch v := interface{}(m.GetReview()).(type) {
 		case interface{ ValidateAll() error }:
 			if err := v.ValidateAll(); err != nil {
-				errors = append(errors, EditReviewResponseValidationError{
+				errors = append(errors, CreateReviewRequestValidationError{
 					field:  "Review",
 					reason: "embedded message failed validation",
 					cause:  err,
@@ -1461,7 +1441,7 @@ func (m *EditReviewResponse) validate(all bool) error {
 			}
 		case interface{ Validate() error }:
 			if err := v.Validate(); err != nil {
-				errors = append(errors, EditReviewResponseValidationError{
+				errors = append(errors, CreateReviewRequestValidationError{
 					field:  "Review",
 					reason: "embedded message failed validation",
 					cause:  err,
@@ -1470,7 +1450,7 @@ func (m *EditReviewResponse) validate(all bool) error {
 		}
 	} else if v, ok := interface{}(m.GetReview()).(interface{ Validate() error }); ok {
 		if err := v.Validate(); err != nil {
-			return EditReviewResponseValidationError{
+			return CreateReviewRequestValidationError{
 				field:  "Review",
 				reason: "embedded message failed validation",
 				cause:  err,
@@ -1478,20 +1458,22 @@ func (m *EditReviewResponse) validate(all bool) error {
 		}
 	}
 
+	// no validation rules for IdempotencyKey
+
 	if len(errors) > 0 {
-		return EditReviewResponseMultiError(errors)
+		return CreateReviewRequestMultiError(errors)
 	}
 
 	return nil
 }
 
-// EditReviewResponseMultiError is an error wrapping multiple validation errors
-// returned by EditReviewResponse.ValidateAll() if the designated constraints
-// aren't met.
-type EditReviewResponseMultiError []error
+// CreateReviewRequestMultiError is an error wrapping multiple validation
+// errors returned by CreateReviewRequest.ValidateAll() if the designated
+// constraints aren't met.
+type CreateReviewRequestMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m EditReviewResponseMultiError) Error() string {
+func (m CreateReviewRequestMultiError) Error() string {
 	msgs := make([]string, 0, len(m))
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -1500,11 +1482,11 @@ func (m EditReviewResponseMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m EditReviewResponseMultiError) AllErrors() []error { return m }
+func (m CreateReviewRequestMultiError) AllErrors() []error { return m }
 
-// EditReviewResponseValidationError is the validation error returned by
-// EditReviewResponse.Validate if the designated constraints aren't met.
-type EditReviewResponseValidationError struct {
+// CreateReviewRequestValidationError is the validation error returned by
+// CreateReviewRequest.Validate if the designated constraints aren't met.
+type CreateReviewRequestValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -1512,24 +1494,24 @@ type EditReviewResponseValidationError struct {
 }
 
 // Field function returns field value.
-func (e EditReviewResponseValidationError) Field() string { return e.field }
+func (e CreateReviewRequestValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e EditReviewResponseValidationError) Reason() string { return e.reason }
+func (e CreateReviewRequestValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e EditReviewResponseValidationError) Cause() error { return e.cause }
+func (e CreateReviewRequestValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e EditReviewResponseValidationError) Key() bool { return e.key }
+func (e CreateReviewRequestValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e EditReviewResponseValidationError) ErrorName() string {
-	return "EditReviewResponseValidationError"
+func (e CreateReviewRequestValidationError) ErrorName() string {
+	return "CreateReviewRequestValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e EditReviewResponseValidationError) Error() string {
+func (e CreateReviewRequestValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -1541,14 +1523,14 @@ func (e EditReviewResponseValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sEditReviewResponse.%s: %s%s",
+		"invalid %sCreateReviewRequest.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = EditReviewResponseValidationError{}
+var _ error = CreateReviewRequestValidationError{}
 
 var _ interface {
 	Field() string
@@ -1556,59 +1538,1204 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = EditReviewResponseValidationError{}
+} = CreateReviewRequestValidationError{}
 
-// Validate checks the field values on DeleteReviewRequest with the rules
+// Validate checks the field values on CreateReviewResponse with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
-func (m *DeleteReviewRequest) Validate() error {
+func (m *CreateReviewResponse) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on DeleteReviewRequest with the rules
+// ValidateAll checks the field values on CreateReviewResponse with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// DeleteReviewRequestMultiError, or nil if none found.
-func (m *DeleteReviewRequest) ValidateAll() error {
+// CreateReviewResponseMultiError, or nil if none found.
+func (m *CreateReviewResponse) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *DeleteReviewRequest) validate(all bool) error {
+func (m *CreateReviewResponse) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	// no validation rules for Id
-
-	if len(errors) > 0 {
-		return DeleteReviewRequestMultiError(errors)
-	}
-
-	return nil
-}
-
-// DeleteReviewRequestMultiError is an error wrapping multiple validation
-// errors returned by DeleteReviewRequest.ValidateAll() if the designated
-// constraints aren't met.
-type DeleteReviewRequestMultiError []error
-
-// Error returns a concatenation of all the error messages it wraps.
-func (m DeleteReviewRequestMultiError) Error() string {
-	msgs := make([]string, 0, len(m))
-	for _, err := range m {
+	if all {
+		switch v := interface{}(m.GetReview()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, CreateReviewResponseValidationError{
+					field:  "Review",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, CreateReviewResponseValidationError{
+					field:  "Review",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetReview()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return CreateReviewResponseValidationError{
+				field:  "Review",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return CreateReviewResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// CreateReviewResponseMultiError is an error wrapping multiple validation
+// errors returned by CreateReviewResponse.ValidateAll() if the designated
+// constraints aren't met.
+type CreateReviewResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CreateReviewResponseMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CreateReviewResponseMultiError) AllErrors() []error { return m }
+
+// CreateReviewResponseValidationError is the validation error returned by
+// CreateReviewResponse.Validate if the designated constraints aren't met.
+type CreateReviewResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CreateReviewResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CreateReviewResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CreateReviewResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CreateReviewResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CreateReviewResponseValidationError) ErrorName() string {
+	return "CreateReviewResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CreateReviewResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCreateReviewResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CreateReviewResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CreateReviewResponseValidationError{}
+
+// Validate checks the field values on EditReviewRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *EditReviewRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on EditReviewRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// EditReviewRequestMultiError, or nil if none found.
+func (m *EditReviewRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *EditReviewRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetReview()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, EditReviewRequestValidationError{
+					field:  "Review",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, EditReviewRequestValidationError{
+					field:  "Review",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetReview()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return EditReviewRequestValidationError{
+				field:  "Review",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return EditReviewRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// EditReviewRequestMultiError is an error wrapping multiple validation errors
+// returned by EditReviewRequest.ValidateAll() if the designated constraints
+// aren't met.
+type EditReviewRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m EditReviewRequestMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m EditReviewRequestMultiError) AllErrors() []error { return m }
+
+// EditReviewRequestValidationError is the validation error returned by
+// EditReviewRequest.Validate if the designated constraints aren't met.
+type EditReviewRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e EditReviewRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e EditReviewRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e EditReviewRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e EditReviewRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e EditReviewRequestValidationError) ErrorName() string {
+	return "EditReviewRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e EditReviewRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sEditReviewRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = EditReviewRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = EditReviewRequestValidationError{}
+
+// Validate checks the field values on EditReviewResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *EditReviewResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on EditReviewResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// EditReviewResponseMultiError, or nil if none found.
+func (m *EditReviewResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *EditReviewResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetReview()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, EditReviewResponseValidationError{
+					field:  "Review",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, EditReviewResponseValidationError{
+					field:  "Review",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetReview()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return EditReviewResponseValidationError{
+				field:  "Review",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return EditReviewResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// EditReviewResponseMultiError is an error wrapping multiple validation errors
+// returned by EditReviewResponse.ValidateAll() if the designated constraints
+// aren't met.
+type EditReviewResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m EditReviewResponseMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m EditReviewResponseMultiError) AllErrors() []error { return m }
+
+// EditReviewResponseValidationError is the validation error returned by
+// EditReviewResponse.Validate if the designated constraints aren't met.
+type EditReviewResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e EditReviewResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e EditReviewResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e EditReviewResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e EditReviewResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e EditReviewResponseValidationError) ErrorName() string {
+	return "EditReviewResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e EditReviewResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sEditReviewResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = EditReviewResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = EditReviewResponseValidationError{}
+
+// Validate checks the field values on DeleteReviewRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DeleteReviewRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DeleteReviewRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DeleteReviewRequestMultiError, or nil if none found.
+func (m *DeleteReviewRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DeleteReviewRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return DeleteReviewRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// DeleteReviewRequestMultiError is an error wrapping multiple validation
+// errors returned by DeleteReviewRequest.ValidateAll() if the designated
+// constraints aren't met.
+type DeleteReviewRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DeleteReviewRequestMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DeleteReviewRequestMultiError) AllErrors() []error { return m }
+
+// DeleteReviewRequestValidationError is the validation error returned by
+// DeleteReviewRequest.Validate if the designated constraints aren't met.
+type DeleteReviewRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DeleteReviewRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DeleteReviewRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DeleteReviewRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DeleteReviewRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DeleteReviewRequestValidationError) ErrorName() string {
+	return "DeleteReviewRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DeleteReviewRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDeleteReviewRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DeleteReviewRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DeleteReviewRequestValidationError{}
+
+// Validate checks the field values on GetReviewsByProductIDRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *GetReviewsByProductIDRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetReviewsByProductIDRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetReviewsByProductIDRequestMultiError, or nil if none found.
+func (m *GetReviewsByProductIDRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetReviewsByProductIDRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return GetReviewsByProductIDRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetReviewsByProductIDRequestMultiError is an error wrapping multiple
+// validation errors returned by GetReviewsByProductIDRequest.ValidateAll() if
+// the designated constraints aren't met.
+type GetReviewsByProductIDRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetReviewsByProductIDRequestMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetReviewsByProductIDRequestMultiError) AllErrors() []error { return m }
+
+// GetReviewsByProductIDRequestValidationError is the validation error returned
+// by GetReviewsByProductIDRequest.Validate if the designated constraints
+// aren't met.
+type GetReviewsByProductIDRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetReviewsByProductIDRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetReviewsByProductIDRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetReviewsByProductIDRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetReviewsByProductIDRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetReviewsByProductIDRequestValidationError) ErrorName() string {
+	return "GetReviewsByProductIDRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GetReviewsByProductIDRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetReviewsByProductIDRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetReviewsByProductIDRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetReviewsByProductIDRequestValidationError{}
+
+// Validate checks the field values on GetReviewsByProductIDResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *GetReviewsByProductIDResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetReviewsByProductIDResponse with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the result is a list of violation errors wrapped in
+// GetReviewsByProductIDResponseMultiError, or nil if none found.
+func (m *GetReviewsByProductIDResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetReviewsByProductIDResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetReviews() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, GetReviewsByProductIDResponseValidationError{
+						field:  fmt.Sprintf("Reviews[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, GetReviewsByProductIDResponseValidationError{
+						field:  fmt.Sprintf("Reviews[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return GetReviewsByProductIDResponseValidationError{
+					field:  fmt.Sprintf("Reviews[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return GetReviewsByProductIDResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetReviewsByProductIDResponseMultiError is an error wrapping multiple
+// validation errors returned by GetReviewsByProductIDResponse.ValidateAll()
+// if the designated constraints aren't met.
+type GetReviewsByProductIDResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetReviewsByProductIDResponseMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetReviewsByProductIDResponseMultiError) AllErrors() []error { return m }
+
+// GetReviewsByProductIDResponseValidationError is the validation error
+// returned by GetReviewsByProductIDResponse.Validate if the designated
+// constraints aren't met.
+type GetReviewsByProductIDResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetReviewsByProductIDResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetReviewsByProductIDResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetReviewsByProductIDResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetReviewsByProductIDResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetReviewsByProductIDResponseValidationError) ErrorName() string {
+	return "GetReviewsByProductIDResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GetReviewsByProductIDResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetReviewsByProductIDResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetReviewsByProductIDResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetReviewsByProductIDResponseValidationError{}
+
+// Validate checks the field values on BatchCreateReviewsRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *BatchCreateReviewsRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on BatchCreateReviewsRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// BatchCreateReviewsRequestMultiError, or nil if none found.
+func (m *BatchCreateReviewsRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *BatchCreateReviewsRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetReviews() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, BatchCreateReviewsRequestValidationError{
+						field:  fmt.Sprintf("Reviews[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, BatchCreateReviewsRequestValidationError{
+						field:  fmt.Sprintf("Reviews[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return BatchCreateReviewsRequestValidationError{
+					field:  fmt.Sprintf("Reviews[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return BatchCreateReviewsRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// BatchCreateReviewsRequestMultiError is an error wrapping multiple validation
+// errors returned by BatchCreateReviewsRequest.ValidateAll() if the
+// designated constraints aren't met.
+type BatchCreateReviewsRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m BatchCreateReviewsRequestMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m BatchCreateReviewsRequestMultiError) AllErrors() []error { return m }
+
+// BatchCreateReviewsRequestValidationError is the validation error returned by
+// BatchCreateReviewsRequest.Validate if the designated constraints aren't met.
+type BatchCreateReviewsRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BatchCreateReviewsRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BatchCreateReviewsRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BatchCreateReviewsRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BatchCreateReviewsRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BatchCreateReviewsRequestValidationError) ErrorName() string {
+	return "BatchCreateReviewsRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e BatchCreateReviewsRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBatchCreateReviewsRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BatchCreateReviewsRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BatchCreateReviewsRequestValidationError{}
+
+// Validate checks the field values on BatchCreateReviewsResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *BatchCreateReviewsResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on BatchCreateReviewsResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// BatchCreateReviewsResponseMultiError, or nil if none found.
+func (m *BatchCreateReviewsResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *BatchCreateReviewsResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetResults() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, BatchCreateReviewsResponseValidationError{
+						field:  fmt.Sprintf("Results[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, BatchCreateReviewsResponseValidationError{
+						field:  fmt.Sprintf("Results[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return BatchCreateReviewsResponseValidationError{
+					field:  fmt.Sprintf("Results[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return BatchCreateReviewsResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// BatchCreateReviewsResponseMultiError is an error wrapping multiple
+// validation errors returned by BatchCreateReviewsResponse.ValidateAll() if
+// the designated constraints aren't met.
+type BatchCreateReviewsResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m BatchCreateReviewsResponseMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m BatchCreateReviewsResponseMultiError) AllErrors() []error { return m }
+
+// BatchCreateReviewsResponseValidationError is the validation error returned
+// by BatchCreateReviewsResponse.Validate if the designated constraints aren't met.
+type BatchCreateReviewsResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BatchCreateReviewsResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BatchCreateReviewsResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BatchCreateReviewsResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BatchCreateReviewsResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BatchCreateReviewsResponseValidationError) ErrorName() string {
+	return "BatchCreateReviewsResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e BatchCreateReviewsResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBatchCreateReviewsResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BatchCreateReviewsResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BatchCreateReviewsResponseValidationError{}
+
+// Validate checks the field values on BatchDeleteReviewsRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *BatchDeleteReviewsRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on BatchDeleteReviewsRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// BatchDeleteReviewsRequestMultiError, or nil if none found.
+func (m *BatchDeleteReviewsRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *BatchDeleteReviewsRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return BatchDeleteReviewsRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// BatchDeleteReviewsRequestMultiError is an error wrapping multiple validation
+// errors returned by BatchDeleteReviewsRequest.ValidateAll() if the
+// designated constraints aren't met.
+type BatchDeleteReviewsRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m BatchDeleteReviewsRequestMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m BatchDeleteReviewsRequestMultiError) AllErrors() []error { return m }
+
+// BatchDeleteReviewsRequestValidationError is the validation error returned by
+// BatchDeleteReviewsRequest.Validate if the designated constraints aren't met.
+type BatchDeleteReviewsRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BatchDeleteReviewsRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BatchDeleteReviewsRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BatchDeleteReviewsRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BatchDeleteReviewsRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BatchDeleteReviewsRequestValidationError) ErrorName() string {
+	return "BatchDeleteReviewsRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e BatchDeleteReviewsRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBatchDeleteReviewsRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BatchDeleteReviewsRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BatchDeleteReviewsRequestValidationError{}
+
+// Validate checks the field values on BatchDeleteReviewsResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *BatchDeleteReviewsResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on BatchDeleteReviewsResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// BatchDeleteReviewsResponseMultiError, or nil if none found.
+func (m *BatchDeleteReviewsResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *BatchDeleteReviewsResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetResults() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, BatchDeleteReviewsResponseValidationError{
+						field:  fmt.Sprintf("Results[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, BatchDeleteReviewsResponseValidationError{
+						field:  fmt.Sprintf("Results[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return BatchDeleteReviewsResponseValidationError{
+					field:  fmt.Sprintf("Results[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return BatchDeleteReviewsResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// BatchDeleteReviewsResponseMultiError is an error wrapping multiple
+// validation errors returned by BatchDeleteReviewsResponse.ValidateAll() if
+// the designated constraints aren't met.
+type BatchDeleteReviewsResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m BatchDeleteReviewsResponseMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
 		msgs = append(msgs, err.Error())
 	}
 	return strings.Join(msgs, "; ")
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m DeleteReviewRequestMultiError) AllErrors() []error { return m }
+func (m BatchDeleteReviewsResponseMultiError) AllErrors() []error { return m }
 
-// DeleteReviewRequestValidationError is the validation error returned by
-// DeleteReviewRequest.Validate if the designated constraints aren't met.
-type DeleteReviewRequestValidationError struct {
+// BatchDeleteReviewsResponseValidationError is the validation error returned
+// by BatchDeleteReviewsResponse.Validate if the designated constraints aren't met.
+type BatchDeleteReviewsResponseValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -1616,24 +2743,24 @@ type DeleteReviewRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e DeleteReviewRequestValidationError) Field() string { return e.field }
+func (e BatchDeleteReviewsResponseValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e DeleteReviewRequestValidationError) Reason() string { return e.reason }
+func (e BatchDeleteReviewsResponseValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e DeleteReviewRequestValidationError) Cause() error { return e.cause }
+func (e BatchDeleteReviewsResponseValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e DeleteReviewRequestValidationError) Key() bool { return e.key }
+func (e BatchDeleteReviewsResponseValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e DeleteReviewRequestValidationError) ErrorName() string {
-	return "DeleteReviewRequestValidationError"
+func (e BatchDeleteReviewsResponseValidationError) ErrorName() string {
+	return "BatchDeleteReviewsResponseValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e DeleteReviewRequestValidationError) Error() string {
+func (e BatchDeleteReviewsResponseValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -1645,14 +2772,14 @@ func (e DeleteReviewRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sDeleteReviewRequest.%s: %s%s",
+		"invalid %sBatchDeleteReviewsResponse.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = DeleteReviewRequestValidationError{}
+var _ error = BatchDeleteReviewsResponseValidationError{}
 
 var _ interface {
 	Field() string
@@ -1660,46 +2787,179 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = DeleteReviewRequestValidationError{}
+} = BatchDeleteReviewsResponseValidationError{}
 
-// Validate checks the field values on GetReviewsByProductIDRequest with the
+// Validate checks the field values on BatchReviewResult with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *BatchReviewResult) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on BatchReviewResult with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// BatchReviewResultMultiError, or nil if none found.
+func (m *BatchReviewResult) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *BatchReviewResult) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetReview()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, BatchReviewResultValidationError{
+					field:  "Review",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, BatchReviewResultValidationError{
+					field:  "Review",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetReview()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return BatchReviewResultValidationError{
+				field:  "Review",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Error
+
+	if len(errors) > 0 {
+		return BatchReviewResultMultiError(errors)
+	}
+
+	return nil
+}
+
+// BatchReviewResultMultiError is an error wrapping multiple validation errors
+// returned by BatchReviewResult.ValidateAll() if the designated constraints
+// aren't met.
+type BatchReviewResultMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m BatchReviewResultMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m BatchReviewResultMultiError) AllErrors() []error { return m }
+
+// BatchReviewResultValidationError is the validation error returned by
+// BatchReviewResult.Validate if the designated constraints aren't met.
+type BatchReviewResultValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BatchReviewResultValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BatchReviewResultValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BatchReviewResultValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BatchReviewResultValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BatchReviewResultValidationError) ErrorName() string {
+	return "BatchReviewResultValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e BatchReviewResultValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBatchReviewResult.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BatchReviewResultValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BatchReviewResultValidationError{}
+
+// Validate checks the field values on WatchProductReviewsRequest with the
 // rules defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
-func (m *GetReviewsByProductIDRequest) Validate() error {
+func (m *WatchProductReviewsRequest) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on GetReviewsByProductIDRequest with the
+// ValidateAll checks the field values on WatchProductReviewsRequest with the
 // rules defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// GetReviewsByProductIDRequestMultiError, or nil if none found.
-func (m *GetReviewsByProductIDRequest) ValidateAll() error {
+// WatchProductReviewsRequestMultiError, or nil if none found.
+func (m *WatchProductReviewsRequest) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *GetReviewsByProductIDRequest) validate(all bool) error {
+func (m *WatchProductReviewsRequest) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	// no validation rules for Id
+	// no validation rules for ProductId
 
 	if len(errors) > 0 {
-		return GetReviewsByProductIDRequestMultiError(errors)
+		return WatchProductReviewsRequestMultiError(errors)
 	}
 
 	return nil
 }
 
-// GetReviewsByProductIDRequestMultiError is an error wrapping multiple
-// validation errors returned by GetReviewsByProductIDRequest.ValidateAll() if
+// WatchProductReviewsRequestMultiError is an error wrapping multiple
+// validation errors returned by WatchProductReviewsRequest.ValidateAll() if
 // the designated constraints aren't met.
-type GetReviewsByProductIDRequestMultiError []error
+type WatchProductReviewsRequestMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m GetReviewsByProductIDRequestMultiError) Error() string {
+func (m WatchProductReviewsRequestMultiError) Error() string {
 	msgs := make([]string, 0, len(m))
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -1708,12 +2968,11 @@ func (m GetReviewsByProductIDRequestMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m GetReviewsByProductIDRequestMultiError) AllErrors() []error { return m }
+func (m WatchProductReviewsRequestMultiError) AllErrors() []error { return m }
 
-// GetReviewsByProductIDRequestValidationError is the validation error returned
-// by GetReviewsByProductIDRequest.Validate if the designated constraints
-// aren't met.
-type GetReviewsByProductIDRequestValidationError struct {
+// WatchProductReviewsRequestValidationError is the validation error returned
+// by WatchProductReviewsRequest.Validate if the designated constraints aren't met.
+type WatchProductReviewsRequestValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -1721,24 +2980,24 @@ type GetReviewsByProductIDRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e GetReviewsByProductIDRequestValidationError) Field() string { return e.field }
+func (e WatchProductReviewsRequestValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e GetReviewsByProductIDRequestValidationError) Reason() string { return e.reason }
+func (e WatchProductReviewsRequestValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e GetReviewsByProductIDRequestValidationError) Cause() error { return e.cause }
+func (e WatchProductReviewsRequestValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e GetReviewsByProductIDRequestValidationError) Key() bool { return e.key }
+func (e WatchProductReviewsRequestValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e GetReviewsByProductIDRequestValidationError) ErrorName() string {
-	return "GetReviewsByProductIDRequestValidationError"
+func (e WatchProductReviewsRequestValidationError) ErrorName() string {
+	return "WatchProductReviewsRequestValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e GetReviewsByProductIDRequestValidationError) Error() string {
+func (e WatchProductReviewsRequestValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -1750,14 +3009,14 @@ func (e GetReviewsByProductIDRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sGetReviewsByProductIDRequest.%s: %s%s",
+		"invalid %sWatchProductReviewsRequest.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = GetReviewsByProductIDRequestValidationError{}
+var _ error = WatchProductReviewsRequestValidationError{}
 
 var _ interface {
 	Field() string
@@ -1765,78 +3024,77 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = GetReviewsByProductIDRequestValidationError{}
+} = WatchProductReviewsRequestValidationError{}
 
-// Validate checks the field values on GetReviewsByProductIDResponse with the
+// Validate checks the field values on WatchProductReviewsResponse with the
 // rules defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
-func (m *GetReviewsByProductIDResponse) Validate() error {
+func (m *WatchProductReviewsResponse) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on GetReviewsByProductIDResponse with
-// the rules defined in the proto definition for this message. If any rules
-// are violated, the result is a list of violation errors wrapped in
-// GetReviewsByProductIDResponseMultiError, or nil if none found.
-func (m *GetReviewsByProductIDResponse) ValidateAll() error {
+// ValidateAll checks the field values on WatchProductReviewsResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// WatchProductReviewsResponseMultiError, or nil if none found.
+func (m *WatchProductReviewsResponse) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *GetReviewsByProductIDResponse) validate(all bool) error {
+func (m *WatchProductReviewsResponse) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	for idx, item := range m.GetReviews() {
-		_, _ = idx, item
+	// no validation rules for Action
 
-		if all {
-			switch v := interface{}(item).(type) {
-			case interface{ ValidateAll() error }:
-				if err := v.ValidateAll(); err != nil {
-					errors = append(errors, GetReviewsByProductIDResponseValidationError{
-						field:  fmt.Sprintf("Reviews[%v]", idx),
-						reason: "embedded message failed validation",
-						cause:  err,
-					})
-				}
-			case interface{ Validate() error }:
-				if err := v.Validate(); err != nil {
-					errors = append(errors, GetReviewsByProductIDResponseValidationError{
-						field:  fmt.Sprintf("Reviews[%v]", idx),
-						reason: "embedded message failed validation",
-						cause:  err,
-					})
-				}
+	if all {
+		switch v := interface{}(m.GetReview()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, WatchProductReviewsResponseValidationError{
+					field:  "Review",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
 			}
-		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+		case interface{ Validate() error }:
 			if err := v.Validate(); err != nil {
-				return GetReviewsByProductIDResponseValidationError{
-					field:  fmt.Sprintf("Reviews[%v]", idx),
+				errors = append(errors, WatchProductReviewsResponseValidationError{
+					field:  "Review",
 					reason: "embedded message failed validation",
 					cause:  err,
-				}
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetReview()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return WatchProductReviewsResponseValidationError{
+				field:  "Review",
+				reason: "embedded message failed validation",
+				cause:  err,
 			}
 		}
-
 	}
 
+	// no validation rules for AverageRating
+
 	if len(errors) > 0 {
-		return GetReviewsByProductIDResponseMultiError(errors)
+		return WatchProductReviewsResponseMultiError(errors)
 	}
 
 	return nil
 }
 
-// GetReviewsByProductIDResponseMultiError is an error wrapping multiple
-// validation errors returned by GetReviewsByProductIDResponse.ValidateAll()
-// if the designated constraints aren't met.
-type GetReviewsByProductIDResponseMultiError []error
+// WatchProductReviewsResponseMultiError is an error wrapping multiple
+// validation errors returned by WatchProductReviewsResponse.ValidateAll() if
+// the designated constraints aren't met.
+type WatchProductReviewsResponseMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m GetReviewsByProductIDResponseMultiError) Error() string {
+func (m WatchProductReviewsResponseMultiError) Error() string {
 	msgs := make([]string, 0, len(m))
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -1845,12 +3103,12 @@ func (m GetReviewsByProductIDResponseMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m GetReviewsByProductIDResponseMultiError) AllErrors() []error { return m }
+func (m WatchProductReviewsResponseMultiError) AllErrors() []error { return m }
 
-// GetReviewsByProductIDResponseValidationError is the validation error
-// returned by GetReviewsByProductIDResponse.Validate if the designated
-// constraints aren't met.
-type GetReviewsByProductIDResponseValidationError struct {
+// WatchProductReviewsResponseValidationError is the validation error returned
+// by WatchProductReviewsResponse.Validate if the designated constraints
+// aren't met.
+type WatchProductReviewsResponseValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -1858,24 +3116,24 @@ type GetReviewsByProductIDResponseValidationError struct {
 }
 
 // Field function returns field value.
-func (e GetReviewsByProductIDResponseValidationError) Field() string { return e.field }
+func (e WatchProductReviewsResponseValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e GetReviewsByProductIDResponseValidationError) Reason() string { return e.reason }
+func (e WatchProductReviewsResponseValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e GetReviewsByProductIDResponseValidationError) Cause() error { return e.cause }
+func (e WatchProductReviewsResponseValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e GetReviewsByProductIDResponseValidationError) Key() bool { return e.key }
+func (e WatchProductReviewsResponseValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e GetReviewsByProductIDResponseValidationError) ErrorName() string {
-	return "GetReviewsByProductIDResponseValidationError"
+func (e WatchProductReviewsResponseValidationError) ErrorName() string {
+	return "WatchProductReviewsResponseValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e GetReviewsByProductIDResponseValidationError) Error() string {
+func (e WatchProductReviewsResponseValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -1887,14 +3145,14 @@ func (e GetReviewsByProductIDResponseValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sGetReviewsByProductIDResponse.%s: %s%s",
+		"invalid %sWatchProductReviewsResponse.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = GetReviewsByProductIDResponseValidationError{}
+var _ error = WatchProductReviewsResponseValidationError{}
 
 var _ interface {
 	Field() string
@@ -1902,7 +3160,7 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = GetReviewsByProductIDResponseValidationError{}
+} = WatchProductReviewsResponseValidationError{}
 
 // Validate checks the field values on Product with the rules defined in the
 // proto definition for this message. If any rules are violated, the first
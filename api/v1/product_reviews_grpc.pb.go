@@ -24,11 +24,15 @@ const (
 	ProductReviewsService_GetProductByID_FullMethodName        = "/api.v1.ProductReviewsService/GetProductByID"
 	ProductReviewsService_EditProduct_FullMethodName           = "/api.v1.ProductReviewsService/EditProduct"
 	ProductReviewsService_DeleteProduct_FullMethodName         = "/api.v1.ProductReviewsService/DeleteProduct"
+	ProductReviewsService_BatchGetProducts_FullMethodName      = "/api.v1.ProductReviewsService/BatchGetProducts"
 	ProductReviewsService_ListProducts_FullMethodName          = "/api.v1.ProductReviewsService/ListProducts"
 	ProductReviewsService_CreateReview_FullMethodName          = "/api.v1.ProductReviewsService/CreateReview"
+	ProductReviewsService_BatchCreateReviews_FullMethodName    = "/api.v1.ProductReviewsService/BatchCreateReviews"
 	ProductReviewsService_GetReviewsByProductID_FullMethodName = "/api.v1.ProductReviewsService/GetReviewsByProductID"
 	ProductReviewsService_EditReview_FullMethodName            = "/api.v1.ProductReviewsService/EditReview"
 	ProductReviewsService_DeleteReview_FullMethodName          = "/api.v1.ProductReviewsService/DeleteReview"
+	ProductReviewsService_BatchDeleteReviews_FullMethodName    = "/api.v1.ProductReviewsService/BatchDeleteReviews"
+	ProductReviewsService_WatchProductReviews_FullMethodName   = "/api.v1.ProductReviewsService/WatchProductReviews"
 )
 
 // ProductReviewsServiceClient is the client API for ProductReviewsService service.
@@ -46,11 +50,18 @@ type ProductReviewsServiceClient interface {
 	// DeleteProduct allows to remove Product resource from the inventory.
 	// In order to do so, you should remember ID assigned internally by the system.
 	DeleteProduct(ctx context.Context, in *DeleteProductRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// BatchGetProducts allows to retrieve multiple Product resources by specified IDs at once.
+	// Result is reported per each requested ID in the same order as requested.
+	BatchGetProducts(ctx context.Context, in *BatchGetProductsRequest, opts ...grpc.CallOption) (*BatchGetProductsResponse, error)
 	// ListProducts allows to retrieve all Product resources from the inventory.
 	ListProducts(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListProductsResponse, error)
 	// CreateReview allows to add a new Review resource to the inventory.
 	// Response will contain Product resource with ID assigned internally by the system.
 	CreateReview(ctx context.Context, in *CreateReviewRequest, opts ...grpc.CallOption) (*CreateReviewResponse, error)
+	// BatchCreateReviews allows to add multiple Review resources to the inventory at once.
+	// All Review resources are created in a single transaction and average rating is recomputed once per affected Product.
+	// Result is reported per each Review resource in the same order as requested, invalid ones don't prevent others from being created.
+	BatchCreateReviews(ctx context.Context, in *BatchCreateReviewsRequest, opts ...grpc.CallOption) (*BatchCreateReviewsResponse, error)
 	// GetReviewsByProductID allows to retrieve Review resource by specified Product ID.
 	GetReviewsByProductID(ctx context.Context, in *GetReviewsByProductIDRequest, opts ...grpc.CallOption) (*GetReviewsByProductIDResponse, error)
 	// EditReview allows to update Review resource in a PATCH fashion.
@@ -59,6 +70,13 @@ type ProductReviewsServiceClient interface {
 	// DeleteReview allows to remove Review resource from the inventory.
 	// In order to do so, you should remember ID assigned internally by the system.
 	DeleteReview(ctx context.Context, in *DeleteReviewRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// BatchDeleteReviews allows to remove multiple Review resources from the inventory at once.
+	// All Review resources are removed in a single transaction and average rating is recomputed once per affected Product.
+	// Result is reported per each requested ID in the same order as requested.
+	BatchDeleteReviews(ctx context.Context, in *BatchDeleteReviewsRequest, opts ...grpc.CallOption) (*BatchDeleteReviewsResponse, error)
+	// WatchProductReviews allows to subscribe to changes of Review resources of the specified Product.
+	// Stream delivers created, modified and deleted Review resources together with updated average rating of the Product.
+	WatchProductReviews(ctx context.Context, in *WatchProductReviewsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchProductReviewsResponse], error)
 }
 
 type productReviewsServiceClient struct {
@@ -109,6 +127,16 @@ func (c *productReviewsServiceClient) DeleteProduct(ctx context.Context, in *Del
 	return out, nil
 }
 
+func (c *productReviewsServiceClient) BatchGetProducts(ctx context.Context, in *BatchGetProductsRequest, opts ...grpc.CallOption) (*BatchGetProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetProductsResponse)
+	err := c.cc.Invoke(ctx, ProductReviewsService_BatchGetProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *productReviewsServiceClient) ListProducts(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListProductsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListProductsResponse)
@@ -129,6 +157,16 @@ func (c *productReviewsServiceClient) CreateReview(ctx context.Context, in *Crea
 	return out, nil
 }
 
+func (c *productReviewsServiceClient) BatchCreateReviews(ctx context.Context, in *BatchCreateReviewsRequest, opts ...grpc.CallOption) (*BatchCreateReviewsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchCreateReviewsResponse)
+	err := c.cc.Invoke(ctx, ProductReviewsService_BatchCreateReviews_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *productReviewsServiceClient) GetReviewsByProductID(ctx context.Context, in *GetReviewsByProductIDRequest, opts ...grpc.CallOption) (*GetReviewsByProductIDResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetReviewsByProductIDResponse)
@@ -159,6 +197,35 @@ func (c *productReviewsServiceClient) DeleteReview(ctx context.Context, in *Dele
 	return out, nil
 }
 
+func (c *productReviewsServiceClient) BatchDeleteReviews(ctx context.Context, in *BatchDeleteReviewsRequest, opts ...grpc.CallOption) (*BatchDeleteReviewsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchDeleteReviewsResponse)
+	err := c.cc.Invoke(ctx, ProductReviewsService_BatchDeleteReviews_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productReviewsServiceClient) WatchProductReviews(ctx context.Context, in *WatchProductReviewsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchProductReviewsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProductReviewsService_ServiceDesc.Streams[0], ProductReviewsService_WatchProductReviews_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchProductReviewsRequest, WatchProductReviewsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductReviewsService_WatchProductReviewsClient = grpc.ServerStreamingClient[WatchProductReviewsResponse]
+
 // ProductReviewsServiceServer is the server API for ProductReviewsService service.
 // All implementations should embed UnimplementedProductReviewsServiceServer
 // for forward compatibility.
@@ -174,11 +241,18 @@ type ProductReviewsServiceServer interface {
 	// DeleteProduct allows to remove Product resource from the inventory.
 	// In order to do so, you should remember ID assigned internally by the system.
 	DeleteProduct(context.Context, *DeleteProductRequest) (*emptypb.Empty, error)
+	// BatchGetProducts allows to retrieve multiple Product resources by specified IDs at once.
+	// Result is reported per each requested ID in the same order as requested.
+	BatchGetProducts(context.Context, *BatchGetProductsRequest) (*BatchGetProductsResponse, error)
 	// ListProducts allows to retrieve all Product resources from the inventory.
 	ListProducts(context.Context, *emptypb.Empty) (*ListProductsResponse, error)
 	// CreateReview allows to add a new Review resource to the inventory.
 	// Response will contain Product resource with ID assigned internally by the system.
 	CreateReview(context.Context, *CreateReviewRequest) (*CreateReviewResponse, error)
+	// BatchCreateReviews allows to add multiple Review resources to the inventory at once.
+	// All Review resources are created in a single transaction and average rating is recomputed once per affected Product.
+	// Result is reported per each Review resource in the same order as requested, invalid ones don't prevent others from being created.
+	BatchCreateReviews(context.Context, *BatchCreateReviewsRequest) (*BatchCreateReviewsResponse, error)
 	// GetReviewsByProductID allows to retrieve Review resource by specified Product ID.
 	GetReviewsByProductID(context.Context, *GetReviewsByProductIDRequest) (*GetReviewsByProductIDResponse, error)
 	// EditReview allows to update Review resource in a PATCH fashion.
@@ -187,6 +261,13 @@ type ProductReviewsServiceServer interface {
 	// DeleteReview allows to remove Review resource from the inventory.
 	// In order to do so, you should remember ID assigned internally by the system.
 	DeleteReview(context.Context, *DeleteReviewRequest) (*emptypb.Empty, error)
+	// BatchDeleteReviews allows to remove multiple Review resources from the inventory at once.
+	// All Review resources are removed in a single transaction and average rating is recomputed once per affected Product.
+	// Result is reported per each requested ID in the same order as requested.
+	BatchDeleteReviews(context.Context, *BatchDeleteReviewsRequest) (*BatchDeleteReviewsResponse, error)
+	// WatchProductReviews allows to subscribe to changes of Review resources of the specified Product.
+	// Stream delivers created, modified and deleted Review resources together with updated average rating of the Product.
+	WatchProductReviews(*WatchProductReviewsRequest, grpc.ServerStreamingServer[WatchProductReviewsResponse]) error
 }
 
 // UnimplementedProductReviewsServiceServer should be embedded to have
@@ -208,12 +289,18 @@ func (UnimplementedProductReviewsServiceServer) EditProduct(context.Context, *Ed
 func (UnimplementedProductReviewsServiceServer) DeleteProduct(context.Context, *DeleteProductRequest) (*emptypb.Empty, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteProduct not implemented")
 }
+func (UnimplementedProductReviewsServiceServer) BatchGetProducts(context.Context, *BatchGetProductsRequest) (*BatchGetProductsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchGetProducts not implemented")
+}
 func (UnimplementedProductReviewsServiceServer) ListProducts(context.Context, *emptypb.Empty) (*ListProductsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListProducts not implemented")
 }
 func (UnimplementedProductReviewsServiceServer) CreateReview(context.Context, *CreateReviewRequest) (*CreateReviewResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CreateReview not implemented")
 }
+func (UnimplementedProductReviewsServiceServer) BatchCreateReviews(context.Context, *BatchCreateReviewsRequest) (*BatchCreateReviewsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchCreateReviews not implemented")
+}
 func (UnimplementedProductReviewsServiceServer) GetReviewsByProductID(context.Context, *GetReviewsByProductIDRequest) (*GetReviewsByProductIDResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetReviewsByProductID not implemented")
 }
@@ -223,6 +310,12 @@ func (UnimplementedProductReviewsServiceServer) EditReview(context.Context, *Edi
 func (UnimplementedProductReviewsServiceServer) DeleteReview(context.Context, *DeleteReviewRequest) (*emptypb.Empty, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteReview not implemented")
 }
+func (UnimplementedProductReviewsServiceServer) BatchDeleteReviews(context.Context, *BatchDeleteReviewsRequest) (*BatchDeleteReviewsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchDeleteReviews not implemented")
+}
+func (UnimplementedProductReviewsServiceServer) WatchProductReviews(*WatchProductReviewsRequest, grpc.ServerStreamingServer[WatchProductReviewsResponse]) error {
+	return status.Error(codes.Unimplemented, "method WatchProductReviews not implemented")
+}
 func (UnimplementedProductReviewsServiceServer) testEmbeddedByValue() {}
 
 // UnsafeProductReviewsServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -315,6 +408,24 @@ func _ProductReviewsService_DeleteProduct_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProductReviewsService_BatchGetProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductReviewsServiceServer).BatchGetProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductReviewsService_BatchGetProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductReviewsServiceServer).BatchGetProducts(ctx, req.(*BatchGetProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ProductReviewsService_ListProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -351,6 +462,24 @@ func _ProductReviewsService_CreateReview_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProductReviewsService_BatchCreateReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductReviewsServiceServer).BatchCreateReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductReviewsService_BatchCreateReviews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductReviewsServiceServer).BatchCreateReviews(ctx, req.(*BatchCreateReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ProductReviewsService_GetReviewsByProductID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetReviewsByProductIDRequest)
 	if err := dec(in); err != nil {
@@ -405,6 +534,35 @@ func _ProductReviewsService_DeleteReview_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProductReviewsService_BatchDeleteReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductReviewsServiceServer).BatchDeleteReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductReviewsService_BatchDeleteReviews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductReviewsServiceServer).BatchDeleteReviews(ctx, req.(*BatchDeleteReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductReviewsService_WatchProductReviews_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchProductReviewsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductReviewsServiceServer).WatchProductReviews(m, &grpc.GenericServerStream[WatchProductReviewsRequest, WatchProductReviewsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductReviewsService_WatchProductReviewsServer = grpc.ServerStreamingServer[WatchProductReviewsResponse]
+
 // ProductReviewsService_ServiceDesc is the grpc.ServiceDesc for ProductReviewsService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -428,6 +586,10 @@ var ProductReviewsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteProduct",
 			Handler:    _ProductReviewsService_DeleteProduct_Handler,
 		},
+		{
+			MethodName: "BatchGetProducts",
+			Handler:    _ProductReviewsService_BatchGetProducts_Handler,
+		},
 		{
 			MethodName: "ListProducts",
 			Handler:    _ProductReviewsService_ListProducts_Handler,
@@ -436,6 +598,10 @@ var ProductReviewsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateReview",
 			Handler:    _ProductReviewsService_CreateReview_Handler,
 		},
+		{
+			MethodName: "BatchCreateReviews",
+			Handler:    _ProductReviewsService_BatchCreateReviews_Handler,
+		},
 		{
 			MethodName: "GetReviewsByProductID",
 			Handler:    _ProductReviewsService_GetReviewsByProductID_Handler,
@@ -448,7 +614,17 @@ var ProductReviewsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteReview",
 			Handler:    _ProductReviewsService_DeleteReview_Handler,
 		},
+		{
+			MethodName: "BatchDeleteReviews",
+			Handler:    _ProductReviewsService_BatchDeleteReviews_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchProductReviews",
+			Handler:       _ProductReviewsService_WatchProductReviews_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/v1/product_reviews.proto",
 }
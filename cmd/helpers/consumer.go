@@ -2,17 +2,43 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/eroshiva/cloudtalk/internal/config"
 	"github.com/eroshiva/cloudtalk/pkg/logger"
 	"github.com/eroshiva/cloudtalk/pkg/rabbitmq"
+	"github.com/eroshiva/cloudtalk/pkg/tracing"
+	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 var zlog = logger.NewLogger("cloudtalk-consumer")
 
 func main() {
+	loader := config.NewLoader(flag.CommandLine)
+	flag.Parse()
+	cfg, err := loader.Load()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to load configuration")
+	}
+	if err = cfg.Apply(); err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to apply configuration")
+	}
+
+	// processing of the messages continues traces started by the publisher
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to set up tracing")
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			zlog.Error().Err(err).Msg("Failed to shut down tracing")
+		}
+	}()
+
 	msgs, conn, ch, err := rabbitmq.ConnectAndConsume()
 	if err != nil {
 		zlog.Fatal().Err(err).Msg("Failed to connect to RabbitMQ")
@@ -23,9 +49,14 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	go func() {
 		for d := range msgs {
-			zlog.Info().Msgf("Received a message: '%s'", d.Body)
+			// failed messages are retried with delay and eventually parked in the DLQ
+			if err := rabbitmq.HandleDelivery(ctx, ch, d, handleMessage); err != nil {
+				zlog.Error().Err(err).Msg("Failed to acknowledge a message")
+			}
 		}
 	}()
 
@@ -33,3 +64,9 @@ func main() {
 	<-sigChan
 	zlog.Info().Msg("Shutdown signal is received. Wrapping up...")
 }
+
+// handleMessage processes a single received message.
+func handleMessage(_ context.Context, d amqp.Delivery) error {
+	zlog.Info().Msgf("Received a message: '%s'", d.Body)
+	return nil
+}
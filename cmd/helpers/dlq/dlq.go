@@ -0,0 +1,66 @@
+// Package main is a main entry point for a tool, which inspects and re-drives messages parked in the DLQ.
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/eroshiva/cloudtalk/internal/config"
+	"github.com/eroshiva/cloudtalk/pkg/logger"
+	"github.com/eroshiva/cloudtalk/pkg/rabbitmq"
+)
+
+const (
+	actionInspect = "inspect"
+	actionRedrive = "redrive"
+	defaultLimit  = 100
+)
+
+var zlog = logger.NewLogger("cloudtalk-dlq")
+
+func main() {
+	action := flag.String("action", actionInspect, "action to perform on the DLQ: inspect or redrive")
+	limit := flag.Int("limit", defaultLimit, "maximum number of messages to process")
+	loader := config.NewLoader(flag.CommandLine)
+	flag.Parse()
+	if *action != actionInspect && *action != actionRedrive {
+		zlog.Error().Msgf("Unknown action %s", *action)
+		flag.Usage()
+		os.Exit(1)
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to load configuration")
+	}
+	if err = cfg.Apply(); err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to apply configuration")
+	}
+
+	conn, ch, err := rabbitmq.Connect()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to connect to RabbitMQ")
+	}
+	defer rabbitmq.CloseConnection(conn)
+	defer rabbitmq.CloseChannel(ch)
+
+	switch *action {
+	case actionInspect:
+		msgs, err := rabbitmq.InspectDeadLetters(ch, *limit)
+		if err != nil {
+			zlog.Error().Err(err).Msg("Failed to inspect DLQ")
+			return
+		}
+		for _, d := range msgs {
+			zlog.Info().Msgf("Dead-lettered message (routing key %s, headers %v): '%s'", d.RoutingKey, d.Headers, d.Body)
+		}
+		zlog.Info().Msgf("Inspected %d message(s) in the DLQ", len(msgs))
+	case actionRedrive:
+		n, err := rabbitmq.RedriveDeadLetters(context.Background(), ch, *limit)
+		if err != nil {
+			zlog.Error().Err(err).Msgf("Failed to re-drive DLQ, %d message(s) were re-driven", n)
+			return
+		}
+		zlog.Info().Msgf("Re-driven %d message(s) from the DLQ", n)
+	}
+}
@@ -0,0 +1,78 @@
+// Package main is a main entry point for a tool, which re-emits current state of products and reviews to RabbitMQ.
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"github.com/eroshiva/cloudtalk/internal/config"
+	"github.com/eroshiva/cloudtalk/internal/server"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+	"github.com/eroshiva/cloudtalk/pkg/logger"
+	"github.com/eroshiva/cloudtalk/pkg/rabbitmq"
+)
+
+const defaultRate = 100
+
+var zlog = logger.NewLogger("cloudtalk-replay")
+
+func main() {
+	productIDs := flag.String("products", "", "comma-separated list of product IDs to re-emit, "+
+		"all products are re-emitted when neither products nor reviews are selected")
+	reviewIDs := flag.String("reviews", "", "comma-separated list of review IDs to re-emit on their own")
+	routingKey := flag.String("routing-key", "", "routing key to publish snapshot events with, main queue's routing key is used when empty")
+	rate := flag.Int("rate", defaultRate, "maximum number of published events per second, 0 disables throttling")
+	loader := config.NewLoader(flag.CommandLine)
+	flag.Parse()
+	if *rate < 0 {
+		zlog.Fatal().Msgf("Rate must not be negative, got %d", *rate)
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to load configuration")
+	}
+	// replay only reads the DB, thus it never changes its schema
+	if cfg.DB.Migrations == db.MigrationsApply {
+		cfg.DB.Migrations = db.MigrationsVerify
+	}
+	if err = cfg.Apply(); err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to apply configuration")
+	}
+
+	dbClient, err := db.RunSchemaMigration()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to instantiate connection with PostgreSQL DB")
+	}
+	store := db.NewEntStore(dbClient)
+	defer func() {
+		if err := store.Close(); err != nil {
+			zlog.Error().Err(err).Msg("Failed to gracefully close DB connection")
+		}
+	}()
+
+	conn, ch, err := rabbitmq.Connect()
+	if err != nil {
+		zlog.Error().Err(err).Msg("Failed to connect to RabbitMQ")
+		return
+	}
+	defer rabbitmq.CloseConnection(conn)
+	defer rabbitmq.CloseChannel(ch)
+
+	opts := server.SnapshotOptions{
+		RoutingKey: *routingKey,
+		Rate:       *rate,
+	}
+	if *productIDs != "" {
+		opts.ProductIDs = strings.Split(*productIDs, ",")
+	}
+	if *reviewIDs != "" {
+		opts.ReviewIDs = strings.Split(*reviewIDs, ",")
+	}
+	n, err := server.EmitSnapshot(context.Background(), store, server.NewRabbitMQMessaging(conn, ch), opts)
+	if err != nil {
+		zlog.Error().Err(err).Msgf("Failed to emit snapshot, %d event(s) were published", n)
+		return
+	}
+	zlog.Info().Msgf("Published %d snapshot event(s)", n)
+}
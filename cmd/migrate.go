@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/eroshiva/cloudtalk/internal/config"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+)
+
+const (
+	// migrateCommand manages DB schema with the migrations embedded in the binary, e.g., "prs migrate up".
+	migrateCommand = "migrate"
+
+	migrateUp       = "up"
+	migrateStatus   = "status"
+	migrateValidate = "validate"
+)
+
+// migrateUsage prints usage of the migrate command.
+func migrateUsage(fs *flag.FlagSet) {
+	fmt.Fprintf(fs.Output(), "Usage: %s %s <%s|%s|%s> [flags]\n", os.Args[0], migrateCommand, migrateUp, migrateStatus, migrateValidate)
+	fmt.Fprintf(fs.Output(), "  %s\tapplies pending migrations\n", migrateUp)
+	fmt.Fprintf(fs.Output(), "  %s\treports pending and drifted migrations, exits with 1 unless the schema is up to date\n", migrateStatus)
+	fmt.Fprintf(fs.Output(), "  %s\tverifies checksums of the embedded migration files\n", migrateValidate)
+	fs.PrintDefaults()
+}
+
+// migrate runs the migrate command. Returns exit code of the process.
+func migrate(args []string) int {
+	fs := flag.NewFlagSet(migrateCommand, flag.ContinueOnError)
+	fs.Usage = func() { migrateUsage(fs) }
+	loader := config.NewLoader(fs)
+	if len(args) == 0 {
+		fs.Usage()
+		return 2
+	}
+	action := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	// embedded migrations are validated without connecting to the DB
+	if action == migrateValidate {
+		if err := db.ValidateMigrations(); err != nil {
+			zlog.Error().Err(err).Msg("Migrations are not valid")
+			return 1
+		}
+		zlog.Info().Msg("Migrations are valid")
+		return 0
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		zlog.Error().Err(err).Msg("Failed to load configuration")
+		return 1
+	}
+	if err = cfg.Apply(); err != nil {
+		zlog.Error().Err(err).Msg("Failed to apply configuration")
+		return 1
+	}
+
+	ctx := context.Background()
+	switch action {
+	case migrateUp:
+		applied, err := db.ApplyMigrations(ctx)
+		for _, name := range applied {
+			zlog.Info().Msgf("Applied migration %s", name)
+		}
+		if err != nil {
+			zlog.Error().Err(err).Msg("Failed to apply migrations")
+			return 1
+		}
+		zlog.Info().Msgf("DB schema is up to date, %d migration(s) applied", len(applied))
+		return 0
+	case migrateStatus:
+		status, err := db.GetSchemaStatus(ctx)
+		if err != nil {
+			zlog.Error().Err(err).Msg("Failed to read DB schema status")
+			return 1
+		}
+		zlog.Info().Msgf("Current version: %s, latest version: %s", status.Current, status.Latest)
+		if len(status.Unknown) > 0 {
+			zlog.Warn().Msgf("Migrations unknown to this release: %v", status.Unknown)
+		}
+		if err = status.Err(); err != nil {
+			zlog.Error().Err(err).Msg("DB schema is not up to date")
+			return 1
+		}
+		zlog.Info().Msg("DB schema is up to date")
+		return 0
+	default:
+		zlog.Error().Msgf("Unknown migrate action %q", action)
+		fs.Usage()
+		return 2
+	}
+}
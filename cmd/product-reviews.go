@@ -2,65 +2,193 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
-	"sync"
 	"syscall"
+	"time"
 
+	"github.com/eroshiva/cloudtalk/internal/config"
 	"github.com/eroshiva/cloudtalk/internal/server"
 	"github.com/eroshiva/cloudtalk/pkg/client/db"
 	"github.com/eroshiva/cloudtalk/pkg/logger"
-	"github.com/eroshiva/cloudtalk/pkg/rabbitmq"
+	"github.com/eroshiva/cloudtalk/pkg/tlsconfig"
+	"github.com/eroshiva/cloudtalk/pkg/tracing"
+)
+
+const (
+	// healthcheckCommand checks readiness of the already running service instead of starting a new one.
+	// Distroless image has no shell nor curl, thus docker-compose runs the binary itself to check the service.
+	healthcheckCommand = "healthcheck"
+	healthcheckTimeout = 3 * time.Second
 )
 
 var zlog = logger.NewLogger("main")
 
 func main() { //nolint:unused // this is a main entry point to our service.
-	zlog.Info().Msgf("Starting product review service")
+	if len(os.Args) > 1 && os.Args[1] == healthcheckCommand {
+		os.Exit(healthcheck(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == migrateCommand {
+		os.Exit(migrate(os.Args[2:]))
+	}
+
+	// configuration is loaded from the file, environment variables and flags
+	loader := config.NewLoader(flag.CommandLine)
+	flag.Parse()
+	serviceConfig, err := loader.Load()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to load configuration")
+	}
+	if loader.PrintConfig() {
+		fmt.Print(serviceConfig)
+		os.Exit(0)
+	}
+	if err = serviceConfig.Apply(); err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to apply configuration")
+	}
+	zlog.Info().Msgf("Starting product review service with configuration:\n%s", serviceConfig)
 
-	// channels to handle termination and capture signals
-	termChan := make(chan bool)
-	reverseProxyTermChan := make(chan bool)
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+	// context is cancelled on termination signals, which starts the graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
 
-	readyChan := make(chan bool, 1)
-	reverseProxyReadyChan := make(chan bool, 1)
+	// setting up tracing, spans are exported with the exporter selected by configuration
+	shutdownTracing, err := tracing.Setup(context.Background(), serviceConfig.Tracing)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to set up tracing")
+	}
+
+	cfg := serviceConfig.Server
 
 	// connecting to DB
 	dbClient, err := db.RunSchemaMigration()
 	if err != nil {
 		zlog.Fatal().Err(err).Msg("Failed to instantiate connection with PostgreSQL DB")
 	}
+	// read-only queries are served by the read replica, if it is configured
+	replicaClient, err := db.OpenReadReplica()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to instantiate connection with read replica of PostgreSQL DB")
+	}
+	cfg.Store = db.NewEntStore(dbClient, db.WithReadReplica(replicaClient))
 
-	// connecting to RabbitMQ
-	rabbitMQConn, rabbitMQCh, err := rabbitmq.Connect()
+	// connecting to RabbitMQ, unless the in-memory messaging is configured
+	cfg.Messaging, err = server.NewMessaging(cfg.MessagingBackend)
 	if err != nil {
-		zlog.Fatal().Err(err).Msg("Failed to connect to RabbitMQ")
+		zlog.Fatal().Err(err).Msg("Failed to create messaging")
+	}
+
+	// starting NB API server, it closes DB connection and the messaging once it is stopped.
+	srv, err := server.New(cfg)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("Failed to create server")
+	}
+	go reloadOnHangup(ctx, loader, serviceConfig, srv)
+	exitCode := 0
+	if err = srv.Run(ctx); err != nil {
+		zlog.Error().Err(err).Msg("Server stopped with an error")
+		exitCode = 1
 	}
+	stop()
 
-	// creating waitgroup so main will wait for servers to exit cleanly
-	wg := &sync.WaitGroup{}
-	wg.Go(func() {
-		<-sigChan
-		zlog.Info().Msg("Shutdown signal received. Closing channels...")
-		close(termChan)
-		close(reverseProxyTermChan)
-	})
+	// flushing pending spans
+	if err = shutdownTracing(context.Background()); err != nil {
+		zlog.Error().Err(err).Msg("Failed to shut down tracing")
+	}
 
-	// starting NB API server.
-	server.StartServer(server.GetGRPCServerAddress(), server.GetHTTPServerAddress(), dbClient, rabbitMQCh, wg, termChan, readyChan, reverseProxyReadyChan, reverseProxyTermChan)
+	zlog.Info().Msgf("Shutdown is complete. Goodbye!")
+	os.Exit(exitCode)
+}
 
-	wg.Wait()
-	zlog.Info().Msg("Shutting down product reviews service")
+// reloadOnHangup reloads configuration on SIGHUP until the context is cancelled. Log settings and TLS certificates
+// are applied right away, other changed settings take effect only after restart. Invalid configuration is
+// reported and the current one is kept in use.
+func reloadOnHangup(ctx context.Context, loader *config.Loader, current config.Config, srv *server.Server) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+		}
+		zlog.Info().Msg("Reloading configuration")
+		next, err := loader.Load()
+		if err != nil {
+			zlog.Error().Err(err).Msg("Failed to reload configuration, keeping the current one")
+			continue
+		}
+		var restart []string
+		current, restart = current.Reload(next)
+		if err = logger.Configure(current.Log); err != nil {
+			zlog.Error().Err(err).Msg("Failed to apply log configuration")
+		}
+		if len(restart) > 0 {
+			zlog.Warn().Strs("settings", restart).Msg("Changed settings take effect only after restart")
+		}
+		if err = srv.ReloadCertificates(); err != nil {
+			zlog.Error().Err(err).Msg("Failed to reload certificates, keeping the previous ones")
+		}
+	}
+}
 
-	// gracefully closing clients
-	err = db.GracefullyCloseDBClient(dbClient)
+// healthcheck queries readiness endpoint of the service running on this host. Returns exit code of the process.
+// Configuration is loaded the same way as by the service, so the same file, environment and flags apply.
+func healthcheck(args []string) int {
+	fs := flag.NewFlagSet(healthcheckCommand, flag.ContinueOnError)
+	loader := config.NewLoader(fs)
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	cfg, err := loader.Load()
 	if err != nil {
-		zlog.Error().Err(err).Msg("Failed to gracefully close DB connection")
+		zlog.Error().Err(err).Msg("Failed to load configuration")
+		return 1
 	}
-	rabbitmq.CloseConnection(rabbitMQConn)
-	rabbitmq.CloseChannel(rabbitMQCh)
 
-	zlog.Info().Msgf("Shutdown is complete. Goodbye!")
+	host, port, err := net.SplitHostPort(cfg.Server.HTTPAddress)
+	if err != nil {
+		zlog.Error().Err(err).Msg("Invalid HTTP server address")
+		return 1
+	}
+	if host == "" || host == "0.0.0.0" {
+		host = "localhost"
+	}
+
+	// when TLS is enabled, the service certificate is verified and presented back for mutual TLS
+	scheme := "http"
+	client := http.DefaultClient
+	if cfg.Server.TLS.Enabled() {
+		certs, err := tlsconfig.NewReloader(cfg.Server.TLS)
+		if err != nil {
+			zlog.Error().Err(err).Msg("Failed to load certificates")
+			return 1
+		}
+		scheme = "https"
+		client = &http.Client{Transport: &http.Transport{TLSClientConfig: certs.ClientConfig(host)}}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthcheckTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+net.JoinHostPort(host, port)+server.ReadinessPath, nil)
+	if err != nil {
+		zlog.Error().Err(err).Msg("Failed to create readiness request")
+		return 1
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		zlog.Error().Err(err).Msg("Service is not reachable")
+		return 1
+	}
+	defer resp.Body.Close() //nolint:errcheck // body is not read
+	if resp.StatusCode != http.StatusOK {
+		zlog.Error().Msgf("Service is not ready (%s)", resp.Status)
+		return 1
+	}
+	return 0
 }
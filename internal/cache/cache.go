@@ -0,0 +1,139 @@
+// Package cache implements caching of Product and Review resources. Resources are cached in their Protobuf notation,
+// so they can be shared between replicas through an external store (e.g., Redis).
+package cache
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/eroshiva/cloudtalk/pkg/logger"
+)
+
+const (
+	productKeyPrefix  = "product:"
+	notFoundKeyPrefix = "notfound:product:"
+	reviewsKeyPrefix  = "reviews:"
+	// product lists are cached under keys containing the catalog generation, bumping it orphans all of them at once
+	productListKeyPrefix = "products:"
+	generationKey        = "products:generation"
+	// versions of entries are striped, so their number is bounded and they never have to be evicted.
+	// Entries sharing the stripe only cause an occasional rejected fill.
+	versionKeyPrefix = "version:"
+	versionStripes   = 4096
+)
+
+var zlog = logger.NewLogger("cache")
+
+// Cache defines caching operations over single Product resources, lists of Review resources per Product
+// and lists of Product resources. Cached values are copies, mutating them doesn't affect the cache and vice versa.
+//
+// Products and lists of reviews are versioned to avoid stale fills: each deletion bumps the version of the entry.
+// Version must be read before the entry is loaded from the DB (Get* methods return it on a miss) and the entry
+// is stored only if its version didn't change in the meantime. Thus, an entry loaded concurrently with a mutation
+// never overwrites the invalidation performed after the mutation.
+//
+// Lists of products are invalidated with a catalog generation counter instead of enumerating cached variants.
+// Generation is handled the same way as the version, it must be read before the list is loaded from the DB and
+// the list is stored under that generation.
+type Cache interface {
+	// GetProduct gets a product from the cache. Returns current version of the entry, which should be used
+	// to store the product in case of a miss.
+	GetProduct(ctx context.Context, id string) (*apiv1.Product, uint64, bool)
+	// SetProduct sets a product in the cache, unless the entry was invalidated since the version was read.
+	SetProduct(ctx context.Context, version uint64, p *apiv1.Product)
+	// DeleteProduct deletes a product from the cache and bumps version of the entry.
+	DeleteProduct(ctx context.Context, id string)
+	// SetProductNotFound remembers for a short time that the product doesn't exist.
+	SetProductNotFound(ctx context.Context, id string)
+	// IsProductNotFound reports whether the product is remembered as not existing.
+	IsProductNotFound(ctx context.Context, id string) bool
+
+	// GetProductList gets a variant of the product list (e.g., page or filter) identified by the query from the cache.
+	// Returns current catalog generation, which should be used to store the list in case of a miss.
+	GetProductList(ctx context.Context, query string) ([]*apiv1.Product, uint64, bool)
+	// SetProductList sets a variant of the product list identified by the query in the cache under the catalog generation.
+	SetProductList(ctx context.Context, generation uint64, query string, ps []*apiv1.Product)
+	// InvalidateProductLists bumps the catalog generation, which invalidates all cached variants of the product list.
+	// Must be called on every mutation of product or review.
+	InvalidateProductLists(ctx context.Context)
+
+	// GetReviews gets reviews of a product from the cache. Returns current version of the entry, which should be used
+	// to store the reviews in case of a miss.
+	GetReviews(ctx context.Context, productID string) ([]*apiv1.Review, uint64, bool)
+	// SetReviews sets reviews of a product in the cache, unless the entry was invalidated since the version was read.
+	SetReviews(ctx context.Context, version uint64, productID string, reviews []*apiv1.Review)
+	// DeleteReviews deletes reviews of a product from the cache and bumps version of the entry.
+	DeleteReviews(ctx context.Context, productID string)
+
+	// Stats returns statistics of the cache usage.
+	Stats(ctx context.Context) (Stats, error)
+	// Flush removes all entries from the cache.
+	Flush(ctx context.Context) error
+
+	// Close releases resources held by the cache.
+	Close() error
+}
+
+// Stats holds statistics of the cache usage.
+type Stats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	// Entries is a number of entries currently stored in the cache.
+	Entries int64 `json:"entries"`
+}
+
+// HitRatio returns ratio of hits to all lookups, or 0 if there were no lookups yet.
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// New creates a cache backend selected in the configuration.
+func New(cfg Config) (Cache, error) {
+	zlog.Info().Msgf("Using %s cache backend", cfg.Backend)
+	switch cfg.Backend {
+	case BackendOtter:
+		return NewOtterCache(cfg)
+	case BackendRedis:
+		return NewRedisCache(cfg)
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q, expected one of %q or %q", cfg.Backend, BackendOtter, BackendRedis)
+	}
+}
+
+func productKey(id string) string {
+	return productKeyPrefix + id
+}
+
+func notFoundKey(id string) string {
+	return notFoundKeyPrefix + id
+}
+
+func reviewsKey(productID string) string {
+	return reviewsKeyPrefix + productID
+}
+
+// versionStripe returns index of the version stripe, which the key belongs to.
+func versionStripe(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key)) // never fails
+	return h.Sum32() % versionStripes
+}
+
+func versionKey(key string) string {
+	return versionStripeKey(versionStripe(key))
+}
+
+// versionStripeKey returns key, which holds version of the stripe.
+func versionStripeKey(stripe uint32) string {
+	return fmt.Sprintf("%s%d", versionKeyPrefix, stripe)
+}
+
+func productListKey(generation uint64, query string) string {
+	return fmt.Sprintf("%s%d:%s", productListKeyPrefix, generation, query)
+}
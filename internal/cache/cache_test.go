@@ -0,0 +1,349 @@
+// Package cache_test contains unit tests for cache backends.
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/eroshiva/cloudtalk/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	productID = "product-id"
+	reviewID  = "review-id"
+)
+
+func newProduct() *apiv1.Product {
+	return &apiv1.Product{
+		Id:            productID,
+		Name:          "myAwesomeProduct",
+		Description:   "Product description",
+		Price:         "19.90",
+		AverageRating: 4.5,
+		Reviews: []*apiv1.Review{
+			{Id: reviewID, FirstName: "John", LastName: "Doe", ReviewText: "Product is good!", Rating: 5},
+		},
+	}
+}
+
+func newReviews() []*apiv1.Review {
+	return []*apiv1.Review{
+		{Id: reviewID, FirstName: "John", LastName: "Doe", ReviewText: "Product is good!", Rating: 5},
+		{Id: reviewID + "2", FirstName: "Theo", LastName: "Walcott", ReviewText: "Product is OK.", Rating: 4},
+	}
+}
+
+// redisConfig returns default configuration of the cache pointing to the Redis running on the address.
+func redisConfig(address string) cache.Config {
+	cfg := cache.DefaultConfig()
+	cfg.Backend = cache.BackendRedis
+	cfg.RedisAddress = address
+	return cfg
+}
+
+// newRedisCache starts in-process Redis stand-in and connects cache to it.
+func newRedisCache(t *testing.T) (cache.Cache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	c, err := cache.NewRedisCache(redisConfig(mr.Addr()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, c.Close())
+	})
+	return c, mr
+}
+
+func TestCacheBackends(t *testing.T) {
+	backends := map[string]func(t *testing.T) cache.Cache{
+		cache.BackendOtter: func(t *testing.T) cache.Cache {
+			c, err := cache.NewOtterCache(cache.DefaultConfig())
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				assert.NoError(t, c.Close())
+			})
+			return c
+		},
+		cache.BackendRedis: func(t *testing.T) cache.Cache {
+			c, _ := newRedisCache(t)
+			return c
+		},
+	}
+
+	for name, newCache := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := newCache(t)
+
+			// products
+			_, version, ok := c.GetProduct(ctx, productID)
+			assert.False(t, ok)
+			c.SetProduct(ctx, version, newProduct())
+			p, _, ok := c.GetProduct(ctx, productID)
+			require.True(t, ok)
+			assert.True(t, proto.Equal(newProduct(), p))
+			c.DeleteProduct(ctx, productID)
+			_, newVersion, ok := c.GetProduct(ctx, productID)
+			assert.False(t, ok)
+			assert.NotEqual(t, version, newVersion)
+
+			// product loaded before the invalidation must not overwrite it
+			c.SetProduct(ctx, version, newProduct())
+			_, _, ok = c.GetProduct(ctx, productID)
+			assert.False(t, ok)
+			c.SetProduct(ctx, newVersion, newProduct())
+			_, _, ok = c.GetProduct(ctx, productID)
+			assert.True(t, ok)
+			c.DeleteProduct(ctx, productID)
+
+			// remembered "not found" result is independent of the product entry
+			assert.False(t, c.IsProductNotFound(ctx, productID))
+			c.SetProductNotFound(ctx, productID)
+			assert.True(t, c.IsProductNotFound(ctx, productID))
+			_, _, ok = c.GetProduct(ctx, productID)
+			assert.False(t, ok)
+
+			// lists of products, each page is cached separately
+			_, generation, ok := c.GetProductList(ctx, "page=1")
+			assert.False(t, ok)
+			c.SetProductList(ctx, generation, "page=1", []*apiv1.Product{newProduct()})
+			ps, _, ok := c.GetProductList(ctx, "page=1")
+			require.True(t, ok)
+			require.Len(t, ps, 1)
+			assert.True(t, proto.Equal(newProduct(), ps[0]))
+			_, _, ok = c.GetProductList(ctx, "page=2")
+			assert.False(t, ok)
+			c.SetProductList(ctx, generation, "page=2", nil)
+			ps, _, ok = c.GetProductList(ctx, "page=2")
+			require.True(t, ok)
+			assert.Empty(t, ps)
+
+			// bumping generation invalidates all variants
+			c.InvalidateProductLists(ctx)
+			_, newGeneration, ok := c.GetProductList(ctx, "page=1")
+			assert.False(t, ok)
+			assert.Greater(t, newGeneration, generation)
+			_, _, ok = c.GetProductList(ctx, "page=2")
+			assert.False(t, ok)
+
+			// list loaded before the mutation is never visible after the mutation
+			c.SetProductList(ctx, generation, "page=1", []*apiv1.Product{newProduct()})
+			_, _, ok = c.GetProductList(ctx, "page=1")
+			assert.False(t, ok)
+
+			// reviews
+			_, version, ok = c.GetReviews(ctx, productID)
+			assert.False(t, ok)
+			c.SetReviews(ctx, version, productID, newReviews())
+			rs, _, ok := c.GetReviews(ctx, productID)
+			require.True(t, ok)
+			require.Len(t, rs, 2)
+			for i, r := range newReviews() {
+				assert.True(t, proto.Equal(r, rs[i]))
+			}
+			// reviews and product are cached under different keys
+			_, _, ok = c.GetProduct(ctx, productID)
+			assert.False(t, ok)
+			c.DeleteReviews(ctx, productID)
+			_, newVersion, ok = c.GetReviews(ctx, productID)
+			assert.False(t, ok)
+
+			// reviews loaded before the invalidation must not overwrite it
+			c.SetReviews(ctx, version, productID, newReviews())
+			_, _, ok = c.GetReviews(ctx, productID)
+			assert.False(t, ok)
+
+			// empty list of reviews is a valid cache entry
+			c.SetReviews(ctx, newVersion, productID, nil)
+			rs, _, ok = c.GetReviews(ctx, productID)
+			assert.True(t, ok)
+			assert.Empty(t, rs)
+
+			// cached values are copies, modifications of the returned value don't affect the cache
+			_, version, _ = c.GetProduct(ctx, productID)
+			original := newProduct()
+			c.SetProduct(ctx, version, original)
+			original.Name = "modified after being cached"
+			p, _, ok = c.GetProduct(ctx, productID)
+			require.True(t, ok)
+			assert.Equal(t, newProduct().GetName(), p.GetName())
+			p.Reviews[0].Rating = 1
+			p, _, ok = c.GetProduct(ctx, productID)
+			require.True(t, ok)
+			assert.Equal(t, newProduct().GetReviews()[0].GetRating(), p.GetReviews()[0].GetRating())
+		})
+	}
+}
+
+func TestRedisCacheSharedBetweenReplicas(t *testing.T) {
+	ctx := context.Background()
+	replicaA, mr := newRedisCache(t)
+	replicaB, err := cache.NewRedisCache(redisConfig(mr.Addr()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, replicaB.Close())
+	})
+
+	// product cached by one replica is visible to the other one
+	replicaA.SetProduct(ctx, 0, newProduct())
+	p, _, ok := replicaB.GetProduct(ctx, productID)
+	require.True(t, ok)
+	assert.Equal(t, productID, p.GetId())
+
+	// invalidation performed by one replica is visible to the other one
+	replicaB.DeleteProduct(ctx, productID)
+	_, _, ok = replicaA.GetProduct(ctx, productID)
+	assert.False(t, ok)
+
+	// catalog generation is shared as well
+	_, generation, ok := replicaA.GetProductList(ctx, "all")
+	assert.False(t, ok)
+	replicaA.SetProductList(ctx, generation, "all", []*apiv1.Product{newProduct()})
+	_, _, ok = replicaB.GetProductList(ctx, "all")
+	assert.True(t, ok)
+	replicaB.InvalidateProductLists(ctx)
+	_, _, ok = replicaA.GetProductList(ctx, "all")
+	assert.False(t, ok)
+}
+
+func TestRedisCacheEntriesExpire(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newRedisCache(t)
+
+	c.SetProduct(ctx, 0, newProduct())
+	_, _, ok := c.GetProduct(ctx, productID)
+	require.True(t, ok)
+
+	mr.FastForward(cache.DefaultCachingTimeout + time.Second)
+	_, _, ok = c.GetProduct(ctx, productID)
+	assert.False(t, ok)
+}
+
+func TestRedisCacheNotFoundExpiresEarly(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newRedisCache(t)
+
+	c.SetProduct(ctx, 0, newProduct())
+	c.SetProductNotFound(ctx, "other-id")
+	mr.FastForward(cache.DefaultNotFoundTimeout + time.Second)
+	assert.False(t, c.IsProductNotFound(ctx, "other-id"))
+	_, _, ok := c.GetProduct(ctx, productID)
+	assert.True(t, ok)
+}
+
+func TestRedisCacheDegradesToMisses(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newRedisCache(t)
+	c.SetProduct(ctx, 0, newProduct())
+
+	// Redis is unavailable, cache reports miss instead of failing
+	mr.Close()
+	_, _, ok := c.GetProduct(ctx, productID)
+	assert.False(t, ok)
+	c.SetProduct(ctx, 0, newProduct())
+	c.DeleteProduct(ctx, productID)
+}
+
+func TestRedisCacheUnreachable(t *testing.T) {
+	mr := miniredis.RunT(t)
+	addr := mr.Addr()
+	mr.Close()
+
+	_, err := cache.NewRedisCache(redisConfig(addr))
+	assert.Error(t, err)
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := cache.DefaultConfig()
+	require.NoError(t, cfg.Validate())
+
+	cfg.Backend = "memcached"
+	cfg.Capacity = -1
+	cfg.ReviewsTTL = 0
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown backend")
+	assert.Contains(t, err.Error(), "capacity")
+	assert.Contains(t, err.Error(), "reviews_ttl")
+}
+
+func TestOtterCacheCostIsWeightedByReviews(t *testing.T) {
+	ctx := context.Background()
+	cfg := cache.DefaultConfig()
+	cfg.Capacity = 100
+	c, err := cache.NewOtterCache(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, c.Close())
+	})
+
+	// review list costing more than the whole capacity is not admitted
+	reviews := make([]*apiv1.Review, 0, cfg.Capacity)
+	for range cfg.Capacity {
+		reviews = append(reviews, newReviews()[0])
+	}
+	c.SetReviews(ctx, 0, productID, reviews)
+	_, _, ok := c.GetReviews(ctx, productID)
+	assert.False(t, ok)
+
+	// while a short one is
+	c.SetReviews(ctx, 0, productID, newReviews())
+	_, _, ok = c.GetReviews(ctx, productID)
+	assert.True(t, ok)
+}
+
+func TestStatsAndFlush(t *testing.T) {
+	for name, newCache := range map[string]func(t *testing.T) cache.Cache{
+		cache.BackendOtter: func(t *testing.T) cache.Cache {
+			c, err := cache.NewOtterCache(cache.DefaultConfig())
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				assert.NoError(t, c.Close())
+			})
+			return c
+		},
+		cache.BackendRedis: func(t *testing.T) cache.Cache {
+			c, _ := newRedisCache(t)
+			return c
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := newCache(t)
+
+			_, version, _ := c.GetProduct(ctx, productID) // miss
+			c.SetProduct(ctx, version, newProduct())
+			_, _, _ = c.GetProduct(ctx, productID) // hit
+			_, generation, _ := c.GetProductList(ctx, "all")
+			c.SetProductList(ctx, generation, "all", []*apiv1.Product{newProduct()})
+
+			stats, err := c.Stats(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), stats.Hits)
+			assert.Equal(t, int64(2), stats.Misses)
+			assert.InDelta(t, 1.0/3, stats.HitRatio(), 1e-9)
+			// versions and generation are not counted as entries
+			assert.Equal(t, int64(2), stats.Entries)
+
+			// flushing removes all entries
+			require.NoError(t, c.Flush(ctx))
+			_, _, ok := c.GetProduct(ctx, productID)
+			assert.False(t, ok)
+			_, _, ok = c.GetProductList(ctx, "all")
+			assert.False(t, ok)
+			stats, err = c.Stats(ctx)
+			require.NoError(t, err)
+			assert.Zero(t, stats.Entries)
+
+			// product loaded before the flush is not cached
+			c.SetProduct(ctx, version, newProduct())
+			_, _, ok = c.GetProduct(ctx, productID)
+			assert.False(t, ok)
+		})
+	}
+}
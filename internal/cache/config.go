@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// BackendOtter is an in-process cache, which is local to each replica.
+	BackendOtter = "otter"
+	// BackendRedis is a cache shared between replicas.
+	BackendRedis = "redis"
+
+	// DefaultCachingTimeout is a default TTL of cached products and lists.
+	DefaultCachingTimeout = time.Minute
+	// DefaultNotFoundTimeout is a default TTL of a remembered "not found" result. It is kept short,
+	// so a resource created in the meantime becomes visible quickly.
+	DefaultNotFoundTimeout = 5 * time.Second
+	// DefaultCapacity is a default capacity of the in-process cache.
+	DefaultCapacity = 1000
+
+	defaultRedisAddress = "localhost:6379"
+)
+
+// Config holds configuration of the cache.
+type Config struct {
+	// Backend is one of BackendOtter or BackendRedis.
+	Backend string `yaml:"backend" env:"CACHE_BACKEND"`
+	// Capacity is a total cost of entries the in-process cache can hold. Each entry costs 1 plus number of reviews it carries,
+	// so large review lists don't crowd out the rest of the cache unnoticed. Redis is sized by its own configuration.
+	Capacity int `yaml:"capacity" env:"CACHE_CAPACITY"`
+	// TTLs per each type of the entry.
+	ProductTTL     time.Duration `yaml:"product_ttl" env:"CACHE_PRODUCT_TTL"`
+	ReviewsTTL     time.Duration `yaml:"reviews_ttl" env:"CACHE_REVIEWS_TTL"`
+	ProductListTTL time.Duration `yaml:"product_list_ttl" env:"CACHE_PRODUCT_LIST_TTL"`
+	NotFoundTTL    time.Duration `yaml:"not_found_ttl" env:"CACHE_NOT_FOUND_TTL"`
+	// WarmUpProducts is a number of products with the most reviews, which are loaded into the cache at startup. Zero disables warm-up.
+	WarmUpProducts int `yaml:"warm_up_products" env:"CACHE_WARM_UP_PRODUCTS"`
+
+	RedisAddress  string `yaml:"redis_address" env:"REDIS_ADDRESS"`
+	RedisPassword string `yaml:"redis_password" env:"REDIS_PASSWORD" secret:"true"`
+}
+
+// DefaultConfig returns default configuration of the cache.
+func DefaultConfig() Config {
+	return Config{
+		Backend:        BackendOtter,
+		Capacity:       DefaultCapacity,
+		ProductTTL:     DefaultCachingTimeout,
+		ReviewsTTL:     DefaultCachingTimeout,
+		ProductListTTL: DefaultCachingTimeout,
+		NotFoundTTL:    DefaultNotFoundTimeout,
+		RedisAddress:   defaultRedisAddress,
+	}
+}
+
+// Validate reports invalid values of the configuration.
+func (c Config) Validate() error {
+	var errs []error
+	switch c.Backend {
+	case BackendOtter:
+	case BackendRedis:
+		if c.RedisAddress == "" {
+			errs = append(errs, fmt.Errorf("redis_address must be set for %s backend", BackendRedis))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown backend %q, expected %q or %q", c.Backend, BackendOtter, BackendRedis))
+	}
+	if c.Capacity < 0 {
+		errs = append(errs, fmt.Errorf("capacity must not be negative, got %d", c.Capacity))
+	}
+	if c.WarmUpProducts < 0 {
+		errs = append(errs, fmt.Errorf("warm_up_products must not be negative, got %d", c.WarmUpProducts))
+	}
+	for name, ttl := range map[string]time.Duration{
+		"product_ttl":      c.ProductTTL,
+		"reviews_ttl":      c.ReviewsTTL,
+		"product_list_ttl": c.ProductListTTL,
+		"not_found_ttl":    c.NotFoundTTL,
+	} {
+		if ttl <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", name, ttl))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invalidation describes cache entries evicted by a mutation performed on one of the replicas.
+type Invalidation struct {
+	// Origin identifies replica, which has performed the mutation.
+	Origin string `json:"origin"`
+	// Products contains IDs of evicted products.
+	Products []string `json:"products,omitempty"`
+	// Reviews contains IDs of products, which review lists were evicted.
+	Reviews []string `json:"reviews,omitempty"`
+	// ProductLists is set when the catalog generation was bumped.
+	ProductLists bool `json:"product_lists,omitempty"`
+	// Flush is set when the whole cache was flushed.
+	Flush bool `json:"flush,omitempty"`
+}
+
+// Publisher broadcasts serialized Invalidation to all replicas.
+type Publisher func(ctx context.Context, body []byte) error
+
+// BroadcastingCache evicts entries from the wrapped cache and broadcasts every eviction to other replicas,
+// so they don't serve stale data after a mutation handled elsewhere.
+type BroadcastingCache struct {
+	Cache
+
+	origin  string
+	publish Publisher
+
+	// repeatAfter is a delay, after which each eviction is repeated, zero disables repeating
+	repeatAfter time.Duration
+	mu          sync.Mutex
+	pending     map[*time.Timer]struct{}
+	closed      bool
+	repeats     sync.WaitGroup
+}
+
+// BroadcastingOption customizes the BroadcastingCache.
+type BroadcastingOption func(*BroadcastingCache)
+
+// WithRepeatedEvictions repeats every eviction (and its broadcast) once the delay passes. Entries may be loaded
+// again right after the eviction from a read replica of the DB, which hasn't applied the mutation yet. Repeated
+// eviction removes such stale entries, provided that the delay is longer than the replication lag.
+func WithRepeatedEvictions(delay time.Duration) BroadcastingOption {
+	return func(c *BroadcastingCache) {
+		c.repeatAfter = delay
+	}
+}
+
+// NewBroadcastingCache wraps the cache, so evictions are broadcast with the publisher.
+func NewBroadcastingCache(c Cache, publish Publisher, opts ...BroadcastingOption) *BroadcastingCache {
+	bc := &BroadcastingCache{
+		Cache:   c,
+		origin:  uuid.NewString(),
+		publish: publish,
+		pending: make(map[*time.Timer]struct{}),
+	}
+	for _, opt := range opts {
+		opt(bc)
+	}
+	return bc
+}
+
+// Invalidate evicts all entries described by the invalidation from the cache. BroadcastingCache sends them to other
+// replicas in a single message, thus a mutation affecting several entries is broadcast only once.
+func Invalidate(ctx context.Context, c Cache, inv Invalidation) {
+	if bc, ok := c.(*BroadcastingCache); ok {
+		bc.Invalidate(ctx, inv)
+		return
+	}
+	// eviction itself doesn't fail, only flush does
+	_ = applyInvalidation(ctx, c, &inv)
+}
+
+// Invalidate evicts all entries described by the invalidation on all replicas with a single broadcast.
+func (c *BroadcastingCache) Invalidate(ctx context.Context, inv Invalidation) {
+	c.evict(ctx, &inv)
+}
+
+// DeleteProduct deletes a product from the cache on all replicas.
+func (c *BroadcastingCache) DeleteProduct(ctx context.Context, id string) {
+	c.evict(ctx, &Invalidation{Products: []string{id}})
+}
+
+// InvalidateProductLists bumps the catalog generation on all replicas.
+func (c *BroadcastingCache) InvalidateProductLists(ctx context.Context) {
+	c.evict(ctx, &Invalidation{ProductLists: true})
+}
+
+// DeleteReviews deletes reviews of a product from the cache on all replicas.
+func (c *BroadcastingCache) DeleteReviews(ctx context.Context, productID string) {
+	c.evict(ctx, &Invalidation{Reviews: []string{productID}})
+}
+
+// Flush removes all entries from the cache on all replicas.
+func (c *BroadcastingCache) Flush(ctx context.Context) error {
+	if err := c.Cache.Flush(ctx); err != nil {
+		return err
+	}
+	c.broadcast(ctx, &Invalidation{Flush: true})
+	return nil
+}
+
+// Close stops pending repeated evictions and closes the wrapped cache.
+func (c *BroadcastingCache) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	for timer := range c.pending {
+		if timer.Stop() {
+			c.repeats.Done()
+		}
+	}
+	clear(c.pending)
+	c.mu.Unlock()
+	// repeated evictions, which are already running, are finished first
+	c.repeats.Wait()
+	return c.Cache.Close()
+}
+
+// evict applies invalidation to the wrapped cache and broadcasts it. The same is repeated after the delay,
+// if it is configured.
+func (c *BroadcastingCache) evict(ctx context.Context, inv *Invalidation) {
+	// eviction itself doesn't fail, only flush does
+	_ = applyInvalidation(ctx, c.Cache, inv)
+	c.broadcast(ctx, inv)
+	if c.repeatAfter <= 0 {
+		return
+	}
+
+	// repeated eviction is not cancelled together with the request
+	ctx = context.WithoutCancel(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.repeats.Add(1)
+	var timer *time.Timer
+	timer = time.AfterFunc(c.repeatAfter, func() {
+		defer c.repeats.Done()
+		c.mu.Lock()
+		delete(c.pending, timer)
+		closed := c.closed
+		c.mu.Unlock()
+		if closed {
+			return
+		}
+		_ = applyInvalidation(ctx, c.Cache, inv)
+		c.broadcast(ctx, inv)
+	})
+	c.pending[timer] = struct{}{}
+}
+
+// broadcast publishes invalidation to other replicas. Failure is only logged, since entries expire after their TTL anyway.
+func (c *BroadcastingCache) broadcast(ctx context.Context, inv *Invalidation) {
+	inv.Origin = c.origin
+	body, err := json.Marshal(inv)
+	if err != nil {
+		zlog.Error().Err(err).Msg("Failed to serialize cache invalidation")
+		return
+	}
+	if err = c.publish(ctx, body); err != nil {
+		zlog.Error().Err(err).Msg("Failed to broadcast cache invalidation, other replicas may serve stale data until TTL expires")
+	}
+}
+
+// Apply evicts entries described by the received invalidation from the wrapped cache.
+// Invalidations originated from this replica are ignored, since they were already applied.
+func (c *BroadcastingCache) Apply(ctx context.Context, body []byte) error {
+	inv := &Invalidation{}
+	if err := json.Unmarshal(body, inv); err != nil {
+		zlog.Error().Err(err).Msgf("Failed to de-serialize cache invalidation: '%s'", body)
+		return err
+	}
+	if inv.Origin == c.origin {
+		return nil
+	}
+
+	zlog.Debug().Msgf("Applying cache invalidation from replica %s", inv.Origin)
+	return applyInvalidation(ctx, c.Cache, inv)
+}
+
+// applyInvalidation evicts entries described by the invalidation from the cache.
+func applyInvalidation(ctx context.Context, c Cache, inv *Invalidation) error {
+	for _, id := range inv.Products {
+		c.DeleteProduct(ctx, id)
+	}
+	for _, id := range inv.Reviews {
+		c.DeleteReviews(ctx, id)
+	}
+	if inv.ProductLists {
+		c.InvalidateProductLists(ctx)
+	}
+	if inv.Flush {
+		return c.Flush(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package cache_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eroshiva/cloudtalk/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replicas simulates fanout exchange, which delivers every invalidation to all replicas, including the sender.
+type replicas struct {
+	caches []*cache.BroadcastingCache
+}
+
+func (r *replicas) publish(ctx context.Context, body []byte) error {
+	for _, c := range r.caches {
+		if err := c.Apply(ctx, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *replicas) add(t *testing.T) *cache.BroadcastingCache {
+	t.Helper()
+	local, err := cache.NewOtterCache(cache.DefaultConfig())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, local.Close())
+	})
+	c := cache.NewBroadcastingCache(local, r.publish)
+	r.caches = append(r.caches, c)
+	return c
+}
+
+func TestCrossReplicaInvalidation(t *testing.T) {
+	ctx := context.Background()
+	bus := &replicas{}
+	replicaA := bus.add(t)
+	replicaB := bus.add(t)
+	replicaC := bus.add(t)
+
+	// every replica caches the same product and its reviews independently
+	for _, c := range bus.caches {
+		c.SetProduct(ctx, 0, newProduct())
+		c.SetReviews(ctx, 0, productID, newReviews())
+		_, generation, _ := c.GetProductList(ctx, "all")
+		c.SetProductList(ctx, generation, "all", nil)
+	}
+
+	// product is edited on replica A, replicas B and C evict it too
+	replicaA.DeleteProduct(ctx, productID)
+	for _, c := range bus.caches {
+		_, _, ok := c.GetProduct(ctx, productID)
+		assert.False(t, ok)
+		_, _, ok = c.GetReviews(ctx, productID)
+		assert.True(t, ok) // reviews are untouched
+		_, _, ok = c.GetProductList(ctx, "all")
+		assert.True(t, ok) // product lists are untouched
+	}
+
+	// review is created on replica B
+	replicaB.DeleteReviews(ctx, productID)
+	replicaB.InvalidateProductLists(ctx)
+	for _, c := range bus.caches {
+		_, _, ok := c.GetReviews(ctx, productID)
+		assert.False(t, ok)
+		_, _, ok = c.GetProductList(ctx, "all")
+		assert.False(t, ok)
+	}
+
+	// malformed invalidation is refused
+	assert.Error(t, replicaC.Apply(ctx, []byte("not a JSON")))
+}
+
+func TestInvalidateBroadcastsOnce(t *testing.T) {
+	ctx := context.Background()
+	var sent [][]byte
+	local, err := cache.NewOtterCache(cache.DefaultConfig())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, local.Close())
+	})
+	c := cache.NewBroadcastingCache(local, func(_ context.Context, body []byte) error {
+		sent = append(sent, body)
+		return nil
+	})
+	bus := &replicas{}
+	peer := bus.add(t)
+	peer.SetProduct(ctx, 0, newProduct())
+	peer.SetReviews(ctx, 0, productID, newReviews())
+	_, generation, _ := peer.GetProductList(ctx, "all")
+	peer.SetProductList(ctx, generation, "all", nil)
+
+	// review is created, all affected entries are sent in a single message
+	cache.Invalidate(ctx, c, cache.Invalidation{Products: []string{productID}, Reviews: []string{productID}, ProductLists: true})
+	require.Len(t, sent, 1)
+	inv := &cache.Invalidation{}
+	require.NoError(t, json.Unmarshal(sent[0], inv))
+	assert.Equal(t, []string{productID}, inv.Products)
+	assert.Equal(t, []string{productID}, inv.Reviews)
+	assert.True(t, inv.ProductLists)
+
+	// peer evicts all of them
+	require.NoError(t, peer.Apply(ctx, sent[0]))
+	_, _, ok := peer.GetProduct(ctx, productID)
+	assert.False(t, ok)
+	_, _, ok = peer.GetReviews(ctx, productID)
+	assert.False(t, ok)
+	_, _, ok = peer.GetProductList(ctx, "all")
+	assert.False(t, ok)
+
+	// plain cache is invalidated without any broadcast
+	local.SetProduct(ctx, 0, newProduct())
+	cache.Invalidate(ctx, local, cache.Invalidation{Products: []string{productID}})
+	_, _, ok = local.GetProduct(ctx, productID)
+	assert.False(t, ok)
+	assert.Len(t, sent, 1)
+}
+
+func TestOwnInvalidationsAreIgnored(t *testing.T) {
+	ctx := context.Background()
+	var sent []byte
+	local, err := cache.NewOtterCache(cache.DefaultConfig())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, local.Close())
+	})
+	c := cache.NewBroadcastingCache(local, func(_ context.Context, body []byte) error {
+		sent = body
+		return nil
+	})
+
+	c.DeleteProduct(ctx, productID)
+	require.NotEmpty(t, sent)
+	inv := &cache.Invalidation{}
+	require.NoError(t, json.Unmarshal(sent, inv))
+	assert.Equal(t, []string{productID}, inv.Products)
+
+	// product is cached again after the invalidation was sent, echo of own invalidation must not evict it
+	_, version, _ := c.GetProduct(ctx, productID)
+	c.SetProduct(ctx, version, newProduct())
+	require.NoError(t, c.Apply(ctx, sent))
+	_, _, ok := c.GetProduct(ctx, productID)
+	assert.True(t, ok)
+}
+
+func TestRepeatedEvictions(t *testing.T) {
+	ctx := context.Background()
+	local, err := cache.NewOtterCache(cache.DefaultConfig())
+	require.NoError(t, err)
+	delay := 50 * time.Millisecond
+	var mu sync.Mutex
+	var sent []*cache.Invalidation
+	c := cache.NewBroadcastingCache(local, func(_ context.Context, body []byte) error {
+		inv := &cache.Invalidation{}
+		assert.NoError(t, json.Unmarshal(body, inv))
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, inv)
+		return nil
+	}, cache.WithRepeatedEvictions(delay))
+
+	// product is loaded again from the lagging read replica right after the eviction
+	c.DeleteProduct(ctx, productID)
+	_, version, _ := c.GetProduct(ctx, productID)
+	c.SetProduct(ctx, version, newProduct())
+	_, _, ok := c.GetProduct(ctx, productID)
+	require.True(t, ok)
+
+	// stale entry is evicted once more on all replicas
+	assert.Eventually(t, func() bool {
+		_, _, ok = c.GetProduct(ctx, productID)
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+	mu.Lock()
+	require.Len(t, sent, 2)
+	assert.Equal(t, []string{productID}, sent[1].Products)
+	mu.Unlock()
+
+	// pending repeated evictions are stopped with the cache
+	c.InvalidateProductLists(ctx)
+	require.NoError(t, c.Close())
+	time.Sleep(2 * delay)
+	mu.Lock()
+	assert.Len(t, sent, 3)
+	mu.Unlock()
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	hitsDesc = prometheus.NewDesc("cache_hits_total", "Number of cache lookups, which found the entry.",
+		nil, nil)
+	missesDesc = prometheus.NewDesc("cache_misses_total", "Number of cache lookups, which didn't find the entry.",
+		nil, nil)
+	evictionsDesc = prometheus.NewDesc("cache_evictions_total", "Number of entries evicted from the cache due to its capacity.",
+		nil, nil)
+	entriesDesc = prometheus.NewDesc("cache_entries", "Number of entries currently stored in the cache.",
+		nil, nil)
+	hitRatioDesc = prometheus.NewDesc("cache_hit_ratio", "Ratio of cache lookups, which found the entry, to all cache lookups.",
+		nil, nil)
+)
+
+// collector exposes statistics of the cache usage as Prometheus metrics.
+type collector struct {
+	c Cache
+}
+
+// NewCollector returns Prometheus collector of the cache statistics.
+func NewCollector(c Cache) prometheus.Collector {
+	return &collector{c: c}
+}
+
+// Describe sends descriptors of all metrics to the channel.
+func (col *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- hitsDesc
+	ch <- missesDesc
+	ch <- evictionsDesc
+	ch <- entriesDesc
+	ch <- hitRatioDesc
+}
+
+// Collect reads statistics of the cache and sends them to the channel.
+func (col *collector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := col.c.Stats(context.Background())
+	if err != nil {
+		zlog.Warn().Err(err).Msg("Failed to collect cache statistics")
+	}
+	ch <- prometheus.MustNewConstMetric(hitsDesc, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(missesDesc, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(evictionsDesc, prometheus.CounterValue, float64(stats.Evictions))
+	ch <- prometheus.MustNewConstMetric(entriesDesc, prometheus.GaugeValue, float64(stats.Entries))
+	ch <- prometheus.MustNewConstMetric(hitRatioDesc, prometheus.GaugeValue, stats.HitRatio())
+}
@@ -0,0 +1,220 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/maypok86/otter"
+	"google.golang.org/protobuf/proto"
+)
+
+// otterCache is an in-process cache, local to each replica. Values are cloned on the way in and out,
+// because handlers hold and pass on the pointers they get.
+type otterCache struct {
+	c          otter.CacheWithVariableTTL[string, any]
+	cfg        Config
+	generation atomic.Uint64
+	versions   [versionStripes]atomic.Uint64
+}
+
+// notFound is a value of the remembered "not found" result.
+type notFound struct{}
+
+// NewOtterCache creates a new in-process cache.
+func NewOtterCache(cfg Config) (Cache, error) {
+	c, err := otter.MustBuilder[string, any](cfg.Capacity).
+		CollectStats().
+		Cost(cost).
+		WithVariableTTL().
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &otterCache{c: c, cfg: cfg}, nil
+}
+
+// cost returns cost of the entry, which is weighted by the number of reviews it carries.
+func cost(_ string, value any) uint32 {
+	switch v := value.(type) {
+	case *apiv1.Product:
+		return 1 + uint32(len(v.GetReviews()))
+	case []*apiv1.Review:
+		return 1 + uint32(len(v))
+	case []*apiv1.Product:
+		total := uint32(1)
+		for _, p := range v {
+			total += 1 + uint32(len(p.GetReviews()))
+		}
+		return total
+	default:
+		return 1
+	}
+}
+
+// get reads value stored under the key together with current version of the entry.
+func (c *otterCache) get(key string) (any, uint64, bool) {
+	// version is read first, so invalidation in between makes the following fill to be rejected
+	version := c.versions[versionStripe(key)].Load()
+	v, ok := c.c.Get(key)
+	return v, version, ok
+}
+
+// setVersioned stores value under the key, unless the entry was invalidated since the version was read.
+func (c *otterCache) setVersioned(key string, version uint64, value any, ttl time.Duration) {
+	stripe := &c.versions[versionStripe(key)]
+	if stripe.Load() != version {
+		zlog.Debug().Msgf("Entry %s was invalidated while being loaded, not caching it", key)
+		return
+	}
+	c.c.Set(key, value, ttl)
+	// invalidation may have happened right before the value was set, it must win
+	if stripe.Load() != version {
+		c.c.Delete(key)
+	}
+}
+
+// invalidate bumps version of the entry and removes its value.
+func (c *otterCache) invalidate(key string) {
+	c.versions[versionStripe(key)].Add(1)
+	c.c.Delete(key)
+}
+
+// GetProduct gets a product from the cache.
+func (c *otterCache) GetProduct(_ context.Context, id string) (*apiv1.Product, uint64, bool) {
+	p, version, ok := c.get(productKey(id))
+	if !ok {
+		return nil, version, false
+	}
+
+	// casting back to original structure
+	product, ok := p.(*apiv1.Product)
+	if !ok {
+		return nil, version, false
+	}
+	return cloneProduct(product), version, true
+}
+
+// SetProduct sets a product in the cache.
+func (c *otterCache) SetProduct(_ context.Context, version uint64, p *apiv1.Product) {
+	c.setVersioned(productKey(p.GetId()), version, cloneProduct(p), c.cfg.ProductTTL)
+}
+
+// DeleteProduct deletes a product from the cache.
+func (c *otterCache) DeleteProduct(_ context.Context, id string) {
+	c.invalidate(productKey(id))
+}
+
+// SetProductNotFound remembers for a short time that the product doesn't exist.
+func (c *otterCache) SetProductNotFound(_ context.Context, id string) {
+	c.c.Set(notFoundKey(id), notFound{}, c.cfg.NotFoundTTL)
+}
+
+// IsProductNotFound reports whether the product is remembered as not existing.
+func (c *otterCache) IsProductNotFound(_ context.Context, id string) bool {
+	return c.c.Has(notFoundKey(id))
+}
+
+// GetProductList gets a variant of the product list from the cache.
+func (c *otterCache) GetProductList(_ context.Context, query string) ([]*apiv1.Product, uint64, bool) {
+	generation := c.generation.Load()
+	p, ok := c.c.Get(productListKey(generation, query))
+	if !ok {
+		return nil, generation, false
+	}
+
+	// casting back to original structure
+	products, ok := p.([]*apiv1.Product)
+	if !ok {
+		return nil, generation, false
+	}
+	return cloneProducts(products), generation, true
+}
+
+// SetProductList sets a variant of the product list in the cache.
+func (c *otterCache) SetProductList(_ context.Context, generation uint64, query string, ps []*apiv1.Product) {
+	if generation != c.generation.Load() {
+		// list was loaded before the last mutation, nobody would read it anyway
+		return
+	}
+	c.c.Set(productListKey(generation, query), cloneProducts(ps), c.cfg.ProductListTTL)
+}
+
+// InvalidateProductLists bumps the catalog generation. Entries of the previous generations expire after their TTL.
+func (c *otterCache) InvalidateProductLists(_ context.Context) {
+	c.generation.Add(1)
+}
+
+// GetReviews gets reviews from the cache.
+func (c *otterCache) GetReviews(_ context.Context, productID string) ([]*apiv1.Review, uint64, bool) {
+	r, version, ok := c.get(reviewsKey(productID))
+	if !ok {
+		return nil, version, false
+	}
+
+	// casting back to original structure
+	reviews, ok := r.([]*apiv1.Review)
+	if !ok {
+		return nil, version, false
+	}
+	return cloneReviews(reviews), version, true
+}
+
+// SetReviews sets reviews in the cache.
+func (c *otterCache) SetReviews(_ context.Context, version uint64, productID string, reviews []*apiv1.Review) {
+	c.setVersioned(reviewsKey(productID), version, cloneReviews(reviews), c.cfg.ReviewsTTL)
+}
+
+// DeleteReviews deletes reviews from the cache.
+func (c *otterCache) DeleteReviews(_ context.Context, productID string) {
+	c.invalidate(reviewsKey(productID))
+}
+
+// Stats returns statistics of the cache usage.
+func (c *otterCache) Stats(_ context.Context) (Stats, error) {
+	stats := c.c.Stats()
+	return Stats{
+		Hits:      stats.Hits(),
+		Misses:    stats.Misses(),
+		Evictions: stats.EvictedCount(),
+		Entries:   int64(c.c.Size()),
+	}, nil
+}
+
+// Flush removes all entries from the cache. Versions and generation are bumped, so fills in flight are rejected.
+func (c *otterCache) Flush(_ context.Context) error {
+	for i := range c.versions {
+		c.versions[i].Add(1)
+	}
+	c.generation.Add(1)
+	c.c.Clear()
+	return nil
+}
+
+// Close releases resources held by the cache.
+func (c *otterCache) Close() error {
+	c.c.Close()
+	return nil
+}
+
+func cloneProduct(p *apiv1.Product) *apiv1.Product {
+	return proto.CloneOf(p)
+}
+
+func cloneProducts(ps []*apiv1.Product) []*apiv1.Product {
+	cloned := make([]*apiv1.Product, 0, len(ps))
+	for _, p := range ps {
+		cloned = append(cloned, cloneProduct(p))
+	}
+	return cloned
+}
+
+func cloneReviews(rs []*apiv1.Review) []*apiv1.Review {
+	cloned := make([]*apiv1.Review, 0, len(rs))
+	for _, r := range rs {
+		cloned = append(cloned, proto.CloneOf(r))
+	}
+	return cloned
+}
@@ -0,0 +1,319 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// redisTimeout bounds latency added by the cache when Redis is slow or unavailable.
+	redisTimeout = 500 * time.Millisecond
+	// flushBatchSize is a number of keys scanned (and removed, while flushing the cache) at once.
+	flushBatchSize = 500
+)
+
+// setIfVersionScript stores the value (ARGV[2]) with TTL in milliseconds (ARGV[3]) under the key (KEYS[2]),
+// only if version of the entry (KEYS[1]) is still equal to the one read before the value was loaded (ARGV[1]).
+var setIfVersionScript = redis.NewScript(`
+if (redis.call('GET', KEYS[1]) or '0') == ARGV[1] then
+  redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[3])
+  return 1
+end
+return 0
+`)
+
+// redisCache is a cache shared between replicas. Values are stored serialized in Protobuf wire format.
+// Failures of Redis are not propagated to the caller: reads are treated as misses and writes are only logged.
+type redisCache struct {
+	client *redis.Client
+	cfg    Config
+	// hits and misses are counted by each replica, Redis only reports them for the whole server
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewRedisCache creates a new cache backed by Redis running on the configured address.
+func NewRedisCache(cfg Config) (Cache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddress,
+		Password: cfg.RedisPassword,
+		// cache must not slow down requests, it is cheaper to fall back to the DB
+		DialTimeout:  redisTimeout,
+		ReadTimeout:  redisTimeout,
+		WriteTimeout: redisTimeout,
+		MaxRetries:   1,
+	})
+	// verifying connectivity
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		zlog.Error().Err(err).Msgf("Failed to connect to Redis on %s", cfg.RedisAddress)
+		return nil, errors.Join(err, client.Close())
+	}
+
+	return &redisCache{client: client, cfg: cfg}, nil
+}
+
+// record counts hit or miss of the lookup.
+func (c *redisCache) record(hit bool) bool {
+	if hit {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return hit
+}
+
+// get reads value stored under the key and de-serializes it into the provided message.
+func (c *redisCache) get(ctx context.Context, key string, m proto.Message) bool {
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			zlog.Warn().Err(err).Msgf("Failed to read %s from Redis", key)
+		}
+		return false
+	}
+	if err = proto.Unmarshal(data, m); err != nil {
+		zlog.Warn().Err(err).Msgf("Failed to de-serialize %s", key)
+		return false
+	}
+	return true
+}
+
+// getVersioned reads value stored under the key together with current version of the entry in a single round trip.
+func (c *redisCache) getVersioned(ctx context.Context, key string, m proto.Message) (uint64, bool) {
+	values, err := c.client.MGet(ctx, versionKey(key), key).Result()
+	if err != nil {
+		zlog.Warn().Err(err).Msgf("Failed to read %s from Redis", key)
+		return 0, false
+	}
+
+	var version uint64
+	if v, ok := values[0].(string); ok {
+		if version, err = strconv.ParseUint(v, 10, 64); err != nil {
+			zlog.Warn().Err(err).Msgf("Failed to parse version of %s", key)
+			return 0, false
+		}
+	}
+	data, ok := values[1].(string)
+	if !ok {
+		return version, false
+	}
+	if err = proto.Unmarshal([]byte(data), m); err != nil {
+		zlog.Warn().Err(err).Msgf("Failed to de-serialize %s", key)
+		return version, false
+	}
+	return version, true
+}
+
+// set serializes provided message and stores it under the key.
+func (c *redisCache) set(ctx context.Context, key string, m proto.Message, ttl time.Duration) {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		zlog.Warn().Err(err).Msgf("Failed to serialize %s", key)
+		return
+	}
+	if err = c.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		zlog.Warn().Err(err).Msgf("Failed to write %s to Redis", key)
+	}
+}
+
+// setVersioned serializes provided message and stores it under the key, unless the entry was invalidated
+// since the version was read.
+func (c *redisCache) setVersioned(ctx context.Context, key string, version uint64, m proto.Message, ttl time.Duration) {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		zlog.Warn().Err(err).Msgf("Failed to serialize %s", key)
+		return
+	}
+	set, err := setIfVersionScript.Run(ctx, c.client, []string{versionKey(key), key}, version, data, ttl.Milliseconds()).Int()
+	if err != nil {
+		zlog.Warn().Err(err).Msgf("Failed to write %s to Redis", key)
+		return
+	}
+	if set == 0 {
+		zlog.Debug().Msgf("Entry %s was invalidated while being loaded, not caching it", key)
+	}
+}
+
+// invalidate bumps version of the entry and removes its value atomically.
+func (c *redisCache) invalidate(ctx context.Context, key string) {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, versionKey(key))
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		zlog.Warn().Err(err).Msgf("Failed to invalidate %s in Redis", key)
+	}
+}
+
+// GetProduct gets a product from the cache.
+func (c *redisCache) GetProduct(ctx context.Context, id string) (*apiv1.Product, uint64, bool) {
+	p := &apiv1.Product{}
+	version, ok := c.getVersioned(ctx, productKey(id), p)
+	if !c.record(ok) {
+		return nil, version, false
+	}
+	return p, version, true
+}
+
+// SetProduct sets a product in the cache.
+func (c *redisCache) SetProduct(ctx context.Context, version uint64, p *apiv1.Product) {
+	c.setVersioned(ctx, productKey(p.GetId()), version, p, c.cfg.ProductTTL)
+}
+
+// DeleteProduct deletes a product from the cache.
+func (c *redisCache) DeleteProduct(ctx context.Context, id string) {
+	c.invalidate(ctx, productKey(id))
+}
+
+// SetProductNotFound remembers for a short time that the product doesn't exist.
+func (c *redisCache) SetProductNotFound(ctx context.Context, id string) {
+	if err := c.client.Set(ctx, notFoundKey(id), 1, c.cfg.NotFoundTTL).Err(); err != nil {
+		zlog.Warn().Err(err).Msgf("Failed to write %s to Redis", notFoundKey(id))
+	}
+}
+
+// IsProductNotFound reports whether the product is remembered as not existing.
+func (c *redisCache) IsProductNotFound(ctx context.Context, id string) bool {
+	n, err := c.client.Exists(ctx, notFoundKey(id)).Result()
+	if err != nil {
+		zlog.Warn().Err(err).Msgf("Failed to read %s from Redis", notFoundKey(id))
+		return false
+	}
+	return n > 0
+}
+
+// GetProductList gets a variant of the product list from the cache. Catalog generation is shared between replicas.
+func (c *redisCache) GetProductList(ctx context.Context, query string) ([]*apiv1.Product, uint64, bool) {
+	generation, err := c.client.Get(ctx, generationKey).Uint64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		zlog.Warn().Err(err).Msgf("Failed to read %s from Redis", generationKey)
+		return nil, 0, false
+	}
+
+	ps := &apiv1.ListProductsResponse{}
+	if !c.record(c.get(ctx, productListKey(generation, query), ps)) {
+		return nil, generation, false
+	}
+	return ps.GetProducts(), generation, true
+}
+
+// SetProductList sets a variant of the product list in the cache.
+func (c *redisCache) SetProductList(ctx context.Context, generation uint64, query string, ps []*apiv1.Product) {
+	c.set(ctx, productListKey(generation, query), &apiv1.ListProductsResponse{Products: ps}, c.cfg.ProductListTTL)
+}
+
+// InvalidateProductLists bumps the catalog generation. Entries of the previous generations expire after their TTL.
+func (c *redisCache) InvalidateProductLists(ctx context.Context) {
+	if err := c.client.Incr(ctx, generationKey).Err(); err != nil {
+		zlog.Warn().Err(err).Msgf("Failed to bump %s in Redis", generationKey)
+	}
+}
+
+// GetReviews gets reviews from the cache.
+func (c *redisCache) GetReviews(ctx context.Context, productID string) ([]*apiv1.Review, uint64, bool) {
+	rs := &apiv1.GetReviewsByProductIDResponse{}
+	version, ok := c.getVersioned(ctx, reviewsKey(productID), rs)
+	if !c.record(ok) {
+		return nil, version, false
+	}
+	return rs.GetReviews(), version, true
+}
+
+// SetReviews sets reviews in the cache.
+func (c *redisCache) SetReviews(ctx context.Context, version uint64, productID string, reviews []*apiv1.Review) {
+	c.setVersioned(ctx, reviewsKey(productID), version, &apiv1.GetReviewsByProductIDResponse{Reviews: reviews}, c.cfg.ReviewsTTL)
+}
+
+// DeleteReviews deletes reviews from the cache.
+func (c *redisCache) DeleteReviews(ctx context.Context, productID string) {
+	c.invalidate(ctx, reviewsKey(productID))
+}
+
+// Stats returns statistics of the cache usage. Evictions are reported for the whole Redis database, while entries
+// of this cache are counted by scanning their keys.
+func (c *redisCache) Stats(ctx context.Context) (Stats, error) {
+	stats := Stats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+	info, err := c.client.InfoMap(ctx, "stats").Result()
+	if err != nil {
+		return stats, err
+	}
+	if v, ok := info["Stats"]["evicted_keys"]; ok {
+		if stats.Evictions, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return stats, err
+		}
+	}
+	// versions and generation are bookkeeping, not entries
+	err = c.scanEntries(ctx, func(keys []string) error {
+		stats.Entries += int64(len(keys))
+		return nil
+	})
+	return stats, err
+}
+
+// Flush removes all entries of this cache from Redis. Other keys in the database are left untouched.
+// Versions and generation are bumped rather than removed, so fills in flight are rejected and lists loaded
+// in the meantime are never served.
+func (c *redisCache) Flush(ctx context.Context) error {
+	for stripe := uint32(0); stripe < versionStripes; stripe += flushBatchSize {
+		_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i := stripe; i < min(stripe+flushBatchSize, versionStripes); i++ {
+				pipe.Incr(ctx, versionStripeKey(i))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := c.client.Incr(ctx, generationKey).Err(); err != nil {
+		return err
+	}
+	return c.scanEntries(ctx, func(keys []string) error {
+		return c.client.Unlink(ctx, keys...).Err()
+	})
+}
+
+// scanEntries passes keys of all entries stored in Redis by this cache to fn in batches.
+func (c *redisCache) scanEntries(ctx context.Context, fn func(keys []string) error) error {
+	for _, prefix := range []string{productKeyPrefix, notFoundKeyPrefix, reviewsKeyPrefix, productListKeyPrefix} {
+		iter := c.client.Scan(ctx, 0, prefix+"*", flushBatchSize).Iterator()
+		keys := make([]string, 0, flushBatchSize)
+		for iter.Next(ctx) {
+			if iter.Val() == generationKey {
+				continue
+			}
+			keys = append(keys, iter.Val())
+			if len(keys) == flushBatchSize {
+				if err := fn(keys); err != nil {
+					return err
+				}
+				keys = keys[:0]
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Close releases resources held by the cache.
+func (c *redisCache) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,245 @@
+// Package config aggregates configuration of all components of the service. Configuration is loaded from
+// a YAML file, environment variables and command-line flags, each overriding the previous one.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eroshiva/cloudtalk/internal/server"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+	"github.com/eroshiva/cloudtalk/pkg/logger"
+	"github.com/eroshiva/cloudtalk/pkg/rabbitmq"
+	"github.com/eroshiva/cloudtalk/pkg/tracing"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	redacted = "*****"
+	// secretURL marks secrets, which are URLs with credentials (secret:"url"), other secrets are tagged with secret:"true".
+	secretURL = "url"
+)
+
+// Config holds configuration of the whole service.
+type Config struct {
+	Server   server.Config   `yaml:"server"`
+	DB       db.Config       `yaml:"db"`
+	RabbitMQ rabbitmq.Config `yaml:"rabbitmq"`
+	Tracing  tracing.Config  `yaml:"tracing"`
+	Log      logger.Config   `yaml:"log"`
+}
+
+// Default returns default configuration of the service.
+func Default() Config {
+	return Config{
+		Server:   server.DefaultConfig(),
+		DB:       db.DefaultConfig(),
+		RabbitMQ: rabbitmq.DefaultConfig(),
+		Tracing:  tracing.DefaultConfig(),
+		Log:      logger.DefaultConfig(),
+	}
+}
+
+// Validate reports all invalid values of the configuration, each prefixed with its section.
+func (c Config) Validate() error {
+	var errs []error
+	for _, section := range []struct {
+		name string
+		err  error
+	}{
+		{"server", c.Server.Validate()},
+		{"db", c.DB.Validate()},
+		{"rabbitmq", c.RabbitMQ.Validate()},
+		{"tracing", c.Tracing.Validate()},
+		{"log", c.Log.Validate()},
+	} {
+		if section.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", section.name, section.err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Apply configures packages, which are configured globally (i.e., logger, DB and RabbitMQ clients).
+func (c Config) Apply() error {
+	if err := logger.Configure(c.Log); err != nil {
+		return fmt.Errorf("log: %w", err)
+	}
+	db.Configure(c.DB)
+	rabbitmq.Configure(c.RabbitMQ)
+	return nil
+}
+
+// reloadable lists sections, which may be changed while the service is running.
+var reloadable = []string{"log."}
+
+// Reload returns configuration, in which reloadable settings are taken from the next configuration.
+// Paths of the other changed settings are returned, they take effect only after restart.
+func (c Config) Reload(next Config) (Config, []string) {
+	var restart []string
+	nextFields := fields(reflect.ValueOf(&next).Elem(), "")
+	for i, f := range fields(reflect.ValueOf(&c).Elem(), "") {
+		if reflect.DeepEqual(f.value.Interface(), nextFields[i].value.Interface()) {
+			continue
+		}
+		if isReloadable(f.path) {
+			f.value.Set(nextFields[i].value)
+			continue
+		}
+		restart = append(restart, f.path)
+	}
+	return c, restart
+}
+
+func isReloadable(path string) bool {
+	for _, prefix := range reloadable {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the configuration in YAML with secrets redacted.
+func (c Config) String() string {
+	var out strings.Builder
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(toNode(reflect.ValueOf(c))); err != nil {
+		return fmt.Sprintf("failed to encode configuration: %v", err)
+	}
+	return out.String()
+}
+
+// field is a single setting of the configuration.
+type field struct {
+	// path is a dot-separated path of the setting in the YAML file, e.g., server.tls.cert_file.
+	path   string
+	env    string
+	secret string
+	value  reflect.Value
+}
+
+// fields returns all settings of the structure in the order of declaration. Nested structures are flattened,
+// fields tagged with yaml:"-" (i.e., dependencies) are skipped.
+func fields(v reflect.Value, prefix string) []field {
+	var out []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, inline := yamlName(sf)
+		if name == "-" || !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Type.Kind() == reflect.Struct {
+			if inline {
+				out = append(out, fields(fv, prefix)...)
+			} else {
+				out = append(out, fields(fv, prefix+name+".")...)
+			}
+			continue
+		}
+		out = append(out, field{
+			path:   prefix + name,
+			env:    sf.Tag.Get("env"),
+			secret: sf.Tag.Get("secret"),
+			value:  fv,
+		})
+	}
+	return out
+}
+
+// yamlName returns name of the field in YAML and whether it is inlined into the parent structure.
+func yamlName(sf reflect.StructField) (string, bool) {
+	name, opts, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+	if name == "" {
+		name = strings.ToLower(sf.Name)
+	}
+	return name, opts == "inline" || (sf.Anonymous && opts == "")
+}
+
+// set parses the raw value into the setting.
+func (f field) set(raw string) error {
+	v := f.value
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("expected duration, e.g., 10s")
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("expected integer")
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("expected number")
+		}
+		v.SetFloat(n)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("expected true or false")
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// toNode encodes the structure as YAML mapping, preserving order of the fields and redacting secrets.
+func toNode(v reflect.Value) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, inline := yamlName(sf)
+		if name == "-" || !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Type.Kind() == reflect.Struct {
+			child := toNode(fv)
+			if inline {
+				node.Content = append(node.Content, child.Content...)
+			} else {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, child)
+			}
+			continue
+		}
+		value := &yaml.Node{}
+		if d, ok := fv.Interface().(time.Duration); ok {
+			_ = value.Encode(d.String())
+		} else {
+			_ = value.Encode(fv.Interface())
+		}
+		if secret := sf.Tag.Get("secret"); secret != "" && fv.String() != "" {
+			_ = value.Encode(redact(fv.String(), secret))
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+	}
+	return node
+}
+
+// redact hides the secret. Only password is hidden in URLs, so the address remains visible.
+func redact(value, secret string) string {
+	if secret == secretURL {
+		if u, err := url.Parse(value); err == nil {
+			return u.Redacted()
+		}
+	}
+	return redacted
+}
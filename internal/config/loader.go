@@ -0,0 +1,146 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	envConfigFile = "CONFIG_FILE"
+	// fileSuffix of the environment variable points to the file holding the secret, e.g., PGPASSWORD_FILE.
+	fileSuffix = "_FILE"
+
+	flagConfig      = "config"
+	flagPrintConfig = "print-config"
+)
+
+// Loader loads configuration from the YAML file, environment variables and command-line flags, in this order
+// of precedence (i.e., flags override environment variables, which override the file). Unset settings keep
+// their defaults.
+type Loader struct {
+	file        string
+	printConfig bool
+	// flags holds raw values of the flags, which were set on the command line, by path of the setting.
+	flags map[string]string
+}
+
+// NewLoader registers flags of all settings in the flag set. Flags are named by the path of the setting,
+// e.g., --server.grpc-address. Secrets have no flags, so they don't leak into the process list.
+func NewLoader(fs *flag.FlagSet) *Loader {
+	l := &Loader{flags: make(map[string]string)}
+	fs.StringVar(&l.file, flagConfig, os.Getenv(envConfigFile), "path to the YAML configuration file (env "+envConfigFile+")")
+	fs.BoolVar(&l.printConfig, flagPrintConfig, false, "print the effective configuration with secrets redacted and exit")
+
+	defaults := Default()
+	for _, f := range fields(reflect.ValueOf(&defaults).Elem(), "") {
+		if f.secret != "" {
+			continue
+		}
+		path := f.path
+		usage := fmt.Sprintf("overrides %s (env %s, default %v)", path, f.env, f.value.Interface())
+		name := strings.ReplaceAll(path, "_", "-")
+		record := func(raw string) error {
+			l.flags[path] = raw
+			return nil
+		}
+		if f.value.Kind() == reflect.Bool {
+			fs.BoolFunc(name, usage, record)
+		} else {
+			fs.Func(name, usage, record)
+		}
+	}
+	return l
+}
+
+// PrintConfig reports whether the effective configuration should be printed instead of starting the service.
+func (l *Loader) PrintConfig() bool {
+	return l.printConfig
+}
+
+// Load loads and validates the configuration. Flags must be parsed beforehand. Load may be called repeatedly,
+// e.g., to reload configuration, each time the file and environment variables are read again.
+func (l *Loader) Load() (Config, error) {
+	cfg := Default()
+	if l.file != "" {
+		if err := loadFile(l.file, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	var errs []error
+	for _, f := range fields(reflect.ValueOf(&cfg).Elem(), "") {
+		if err := loadEnv(f); err != nil {
+			errs = append(errs, err)
+		}
+		if raw, ok := l.flags[f.path]; ok {
+			if err := f.set(raw); err != nil {
+				errs = append(errs, fmt.Errorf("invalid value %q of flag --%s: %w", raw, strings.ReplaceAll(f.path, "_", "-"), err))
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+// loadFile decodes the YAML file over the configuration. Unknown settings are rejected, so typos don't go unnoticed.
+func loadFile(file string, cfg *Config) error {
+	content, err := os.ReadFile(file) //nolint:gosec // path is set by the operator
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	if err = dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse configuration file %s: %w", file, err)
+	}
+	return nil
+}
+
+// loadEnv sets the setting from its environment variable, empty variables are ignored. Secrets may be read
+// from the file instead, which is pointed to by the variable with _FILE suffix (e.g., Docker or Kubernetes secrets).
+func loadEnv(f field) error {
+	if f.env == "" {
+		return nil
+	}
+	raw := os.Getenv(f.env)
+	ok := raw != ""
+	if f.secret != "" {
+		file := os.Getenv(f.env + fileSuffix)
+		fromFile := file != ""
+		if ok && fromFile {
+			return fmt.Errorf("only one of environment variables %s and %s may be set", f.env, f.env+fileSuffix)
+		}
+		if fromFile {
+			content, err := os.ReadFile(file) //nolint:gosec // path is set by the operator
+			if err != nil {
+				return fmt.Errorf("failed to read secret %s from %s: %w", f.path, f.env+fileSuffix, err)
+			}
+			raw, ok = strings.TrimRight(string(content), "\r\n"), true
+		}
+	}
+	if !ok {
+		return nil
+	}
+	if err := f.set(raw); err != nil {
+		if f.secret != "" {
+			// value of the secret is not reported
+			return fmt.Errorf("invalid value of environment variable %s: %w", f.env, err)
+		}
+		return fmt.Errorf("invalid value %q of environment variable %s: %w", raw, f.env, err)
+	}
+	return nil
+}
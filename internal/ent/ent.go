@@ -12,6 +12,7 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/eroshiva/cloudtalk/internal/ent/idempotencykey"
 	"github.com/eroshiva/cloudtalk/internal/ent/product"
 	"github.com/eroshiva/cloudtalk/internal/ent/review"
 )
@@ -74,8 +75,9 @@ var (
 func checkColumn(t, c string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			product.Table: product.ValidColumn,
-			review.Table:  review.ValidColumn,
+			idempotencykey.Table: idempotencykey.ValidColumn,
+			product.Table:        product.ValidColumn,
+			review.Table:         review.ValidColumn,
 		})
 	})
 	return columnCheck(t, c)
@@ -0,0 +1,9 @@
+package migrate
+
+import "embed"
+
+// Migrations holds versioned migration files generated by atlas (see "make migration-generate") together with
+// their checksum file, so the binary applies exactly the migrations it was built with.
+//
+//go:embed migrations
+var Migrations embed.FS
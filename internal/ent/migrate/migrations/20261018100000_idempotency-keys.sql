@@ -0,0 +1,9 @@
+-- Create "idempotency_keys" table
+CREATE TABLE "idempotency_keys" (
+  "id" character varying NOT NULL,
+  "resource_id" character varying NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idempotencykey_expires_at" to table: "idempotency_keys"
+CREATE INDEX "idempotencykey_expires_at" ON "idempotency_keys" ("expires_at");
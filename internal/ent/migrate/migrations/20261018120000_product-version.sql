@@ -0,0 +1,2 @@
+-- Modify "products" table
+ALTER TABLE "products" ADD COLUMN "version" bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Modify "idempotency_keys" table
+ALTER TABLE "idempotency_keys" ADD COLUMN "request_hash" character varying NOT NULL DEFAULT '';
@@ -301,12 +301,12 @@ func (_q *ProductQuery) WithReviews(opts ...func(*ReviewQuery)) *ProductQuery {
 // Example:
 //
 //	var v []struct {
-//		Name string `json:"name,omitempty"`
+//		Version int64 `json:"version,omitempty"`
 //		Count int `json:"count,omitempty"`
 //	}
 //
 //	client.Product.Query().
-//		GroupBy(product.FieldName).
+//		GroupBy(product.FieldVersion).
 //		Aggregate(ent.Count()).
 //		Scan(ctx, &v)
 func (_q *ProductQuery) GroupBy(field string, fields ...string) *ProductGroupBy {
@@ -324,11 +324,11 @@ func (_q *ProductQuery) GroupBy(field string, fields ...string) *ProductGroupBy
 // Example:
 //
 //	var v []struct {
-//		Name string `json:"name,omitempty"`
+//		Version int64 `json:"version,omitempty"`
 //	}
 //
 //	client.Product.Query().
-//		Select(product.FieldName).
+//		Select(product.FieldVersion).
 //		Scan(ctx, &v)
 func (_q *ProductQuery) Select(fields ...string) *ProductSelect {
 	_q.ctx.Fields = append(_q.ctx.Fields, fields...)
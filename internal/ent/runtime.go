@@ -2,8 +2,29 @@
 
 package ent
 
+import (
+	"github.com/eroshiva/cloudtalk/internal/ent/idempotencykey"
+	"github.com/eroshiva/cloudtalk/internal/ent/product"
+	"github.com/eroshiva/cloudtalk/internal/ent/schema"
+)
+
 // The init function reads all schema descriptors with runtime code
 // (default values, validators, hooks and policies) and stitches it
 // to their package variables.
 func init() {
+	idempotencykeyFields := schema.IdempotencyKey{}.Fields()
+	_ = idempotencykeyFields
+	// idempotencykeyDescRequestHash is the schema descriptor for request_hash field.
+	idempotencykeyDescRequestHash := idempotencykeyFields[2].Descriptor()
+	// idempotencykey.DefaultRequestHash holds the default value on creation for the request_hash field.
+	idempotencykey.DefaultRequestHash = idempotencykeyDescRequestHash.Default.(string)
+	productMixin := schema.Product{}.Mixin()
+	productMixinFields0 := productMixin[0].Fields()
+	_ = productMixinFields0
+	productFields := schema.Product{}.Fields()
+	_ = productFields
+	// productDescVersion is the schema descriptor for version field.
+	productDescVersion := productMixinFields0[0].Descriptor()
+	// product.DefaultVersion holds the default value on creation for the version field.
+	product.DefaultVersion = productDescVersion.Default.(int64)
 }
@@ -0,0 +1,32 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// IdempotencyKey holds the idempotency key of a create request together with the ID of the created resource.
+// This resource is internal and is not exposed over the API, thus it is not generated out of Protobuf.
+type IdempotencyKey struct {
+	ent.Schema
+}
+
+// Fields of the IdempotencyKey.
+func (IdempotencyKey) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").Immutable(), // idempotency key scoped by the operation
+		field.String("resource_id").Immutable(),
+		// hash of the create request, key reused with a different request is rejected. Keys stored before the hashes
+		// were introduced have it empty.
+		field.String("request_hash").Immutable().Default(""),
+		field.Time("expires_at").Immutable(),
+	}
+}
+
+// Indexes of the IdempotencyKey.
+func (IdempotencyKey) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("expires_at"), // expired keys are purged periodically
+	}
+}
@@ -0,0 +1,26 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+)
+
+// Mixin of the Product. It is kept apart from the Protobuf-generated fields, so it survives their regeneration.
+func (Product) Mixin() []ent.Mixin {
+	return []ent.Mixin{VersionMixin{}}
+}
+
+// VersionMixin adds version of the row, which is incremented with each update of the average rating.
+// Optimistic updates succeed only if the version has not changed since the row was read.
+// The field is internal and is not exposed over the API.
+type VersionMixin struct {
+	mixin.Schema
+}
+
+// Fields of the VersionMixin.
+func (VersionMixin) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int64("version").Default(0),
+	}
+}
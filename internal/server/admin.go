@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/eroshiva/cloudtalk/internal/cache"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// MetricsPath is a path of the admin server, where Prometheus metrics are exposed.
+	MetricsPath = "/metrics"
+	// AdminCacheStatsPath is a path of the admin server, where statistics of the cache usage are exposed.
+	AdminCacheStatsPath = "/admin/cache/stats"
+	// AdminCacheFlushPath is a path of the admin server, which removes all entries from the cache (on all replicas).
+	AdminCacheFlushPath = "/admin/cache/flush"
+)
+
+// newAdminHandler returns handler of the administrative endpoints and metrics endpoint. They are not authenticated,
+// thus they are served on the admin listener, which is separate from the HTTP gateway and bound to localhost by default.
+func newAdminHandler(c cache.Cache, registry *prometheus.Registry) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(http.MethodGet+" "+MetricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	mux.HandleFunc(http.MethodGet+" "+AdminCacheStatsPath, func(w http.ResponseWriter, r *http.Request) {
+		stats, err := c.Stats(r.Context())
+		if err != nil {
+			zlog.Error().Err(err).Msg("Failed to retrieve cache statistics")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, stats)
+	})
+
+	mux.HandleFunc(http.MethodPost+" "+AdminCacheFlushPath, func(w http.ResponseWriter, r *http.Request) {
+		zlog.Info().Msg("Flushing cache")
+		if err := c.Flush(r.Context()); err != nil {
+			zlog.Error().Err(err).Msg("Failed to flush cache")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
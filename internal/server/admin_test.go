@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/eroshiva/cloudtalk/internal/cache"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminEndpointsAreSeparated(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.GRPCAddress = "127.0.0.1:0"
+	cfg.HTTPAddress = "127.0.0.1:0"
+	cfg.AdminAddress = "127.0.0.1:0"
+	cfg.Store = db.NewMemoryStore()
+	cfg.Messaging = NewMemoryMessaging()
+	srv, err := New(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		srv.closeListeners()
+		assert.NoError(t, srv.cache.Close())
+		assert.NoError(t, srv.gatewayConn.Close())
+		assert.NoError(t, cfg.Messaging.Close())
+	})
+	assert.NotEqual(t, srv.HTTPAddr().String(), srv.AdminAddr().String())
+
+	for path, method := range map[string]string{
+		MetricsPath:         http.MethodGet,
+		AdminCacheStatsPath: http.MethodGet,
+		AdminCacheFlushPath: http.MethodPost,
+	} {
+		// administrative endpoints are not reachable at the HTTP gateway
+		rec := httptest.NewRecorder()
+		srv.gwServer.Handler.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+
+	// they are served by the admin server instead
+	for path, method := range map[string]string{
+		MetricsPath:         http.MethodGet,
+		AdminCacheStatsPath: http.MethodGet,
+		AdminCacheFlushPath: http.MethodPost,
+	} {
+		rec := httptest.NewRecorder()
+		srv.adminServer.Handler.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		assert.Less(t, rec.Code, http.StatusBadRequest, path)
+	}
+}
+
+func TestAdminCacheFlush(t *testing.T) {
+	c, err := cache.New(cache.DefaultConfig())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, c.Close())
+	})
+	c.SetProduct(context.Background(), 0, &apiv1.Product{Id: "product"})
+	handler := newAdminHandler(c, prometheus.NewRegistry())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, AdminCacheFlushPath, nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	_, _, ok := c.GetProduct(context.Background(), "product")
+	assert.False(t, ok)
+}
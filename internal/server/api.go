@@ -5,30 +5,35 @@ import (
 	"fmt"
 
 	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/eroshiva/cloudtalk/internal/cache"
 	"github.com/eroshiva/cloudtalk/pkg/client/db"
-	"github.com/eroshiva/cloudtalk/pkg/rabbitmq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 // CreateProduct creates a Product resource in the DB.
 func (srv *server) CreateProduct(ctx context.Context, req *apiv1.CreateProductRequest) (*apiv1.CreateProductResponse, error) {
-	zlog.Info().Msgf("Creating product %s", req.GetProduct().GetName())
+	zlog.Info().Ctx(ctx).Msgf("Creating product %s", req.GetProduct().GetName())
 	// sanity check
 	if req.GetProduct() == nil {
 		err := fmt.Errorf("product resource is not specified")
-		zlog.Error().Err(err).Msg("Failed to create product")
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to create product")
 		return nil, err
 	}
 
-	// creating product
-	p, err := db.CreateProduct(ctx, srv.dbClient, req.GetProduct().GetName(),
-		req.GetProduct().GetDescription(), req.GetProduct().GetPrice())
+	// creating product, retried request returns originally created product
+	p, created, err := srv.products.CreateProductIdempotent(ctx, getIdempotencyKey(ctx, req.GetIdempotencyKey()),
+		req.GetProduct().GetName(), req.GetProduct().GetDescription(), req.GetProduct().GetPrice())
 	if err != nil {
-		return nil, err
+		return nil, idempotencyStatus(err)
+	}
+	if !created {
+		zlog.Info().Ctx(ctx).Msgf("Product %s was already created with the same idempotency key", p.ID)
 	}
 
-	// updating cache
-	srv.cache.SetProduct(p)
+	// invalidating cache, product itself is cached on the first Get operation
+	srv.cache.InvalidateProductLists(ctx)
 
 	return &apiv1.CreateProductResponse{
 		Product: &apiv1.Product{
@@ -42,60 +47,79 @@ func (srv *server) CreateProduct(ctx context.Context, req *apiv1.CreateProductRe
 
 // GetProductByID retrieves Product resource from the DB by specified ID.
 func (srv *server) GetProductByID(ctx context.Context, req *apiv1.GetProductByIDRequest) (*apiv1.GetProductByIDResponse, error) {
-	zlog.Info().Msgf("Retrieving product by its ID (%s)", req.GetId())
+	zlog.Info().Ctx(ctx).Msgf("Retrieving product by its ID (%s)", req.GetId())
 	// sanity check
 	if req.GetId() == "" {
 		err := fmt.Errorf("ID is not specified")
-		zlog.Error().Err(err).Msg("Failed to retrieve product by its ID")
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to retrieve product by its ID")
 		return nil, err
 	}
 
-	// checking cache first
-	if p, ok := srv.cache.GetProduct(req.GetId()); ok {
-		zlog.Info().Msgf("Product %s found in cache", req.GetId())
+	// checking cache first, reads pinned to the primary DB skip entries, which may be loaded from the read replica
+	pinned := db.ReadsFromPrimary(ctx)
+	p, version, ok := srv.cache.GetProduct(ctx, req.GetId())
+	if ok && !pinned {
+		zlog.Info().Ctx(ctx).Msgf("Product %s found in cache", req.GetId())
 		return &apiv1.GetProductByIDResponse{
-			Product: ConvertProductResourceToProtobuf(p),
+			Product: p,
 		}, nil
 	}
 
-	// retrieving product by ID
-	p, err := db.GetProductByID(ctx, srv.dbClient, req.GetId())
+	// product was recently looked up and doesn't exist
+	if !pinned && srv.cache.IsProductNotFound(ctx, req.GetId()) {
+		zlog.Info().Ctx(ctx).Msgf("Product %s is remembered as not found", req.GetId())
+		return nil, productNotFoundError(req.GetId())
+	}
+
+	// retrieving product by ID, concurrent misses are coalesced into a single DB query
+	product, err := load(ctx, &srv.loads, loadKey(ctx, productLoadKeyPrefix+req.GetId()), func(ctx context.Context) (*apiv1.Product, error) {
+		p, err := srv.products.GetProductByID(ctx, req.GetId())
+		if err != nil {
+			if db.IsNotFound(err) {
+				srv.cache.SetProductNotFound(ctx, req.GetId())
+				return nil, productNotFoundError(req.GetId())
+			}
+			return nil, err
+		}
+
+		// setting cache, unless the product was invalidated while being loaded
+		product := ConvertProductResourceToProtobuf(p)
+		srv.cache.SetProduct(ctx, version, product)
+		return product, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// setting cache
-	srv.cache.SetProduct(p)
-
 	return &apiv1.GetProductByIDResponse{
-		Product: ConvertProductResourceToProtobuf(p),
+		Product: product,
 	}, nil
 }
 
 // EditProduct updates specified fields in Product resource in the DB.
 func (srv *server) EditProduct(ctx context.Context, req *apiv1.EditProductRequest) (*apiv1.EditProductResponse, error) {
-	zlog.Info().Msgf("Editing product (%s)", req.GetProduct().GetId())
+	zlog.Info().Ctx(ctx).Msgf("Editing product (%s)", req.GetProduct().GetId())
 	// sanity check
 	if req.GetProduct() == nil {
 		err := fmt.Errorf("product resource is not specified")
-		zlog.Error().Err(err).Msg("Failed to edit product")
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to edit product")
 		return nil, err
 	}
 	if req.GetProduct().GetId() == "" {
 		err := fmt.Errorf("product ID is not specified")
-		zlog.Error().Err(err).Msg("Failed to edit product")
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to edit product")
 		return nil, err
 	}
 
 	// updating product in DB
-	updP, err := db.EditProduct(ctx, srv.dbClient, req.GetProduct().GetId(), req.GetProduct().GetName(),
+	updP, err := srv.products.EditProduct(ctx, req.GetProduct().GetId(), req.GetProduct().GetName(),
 		req.GetProduct().GetDescription(), req.GetProduct().GetPrice())
 	if err != nil {
 		return nil, err
 	}
 
 	// invalidating cache
-	srv.cache.DeleteProduct(req.GetProduct().GetId())
+	cache.Invalidate(ctx, srv.cache, cache.Invalidation{Products: []string{req.GetProduct().GetId()}, ProductLists: true})
 
 	return &apiv1.EditProductResponse{
 		Product: ConvertProductResourceToProtobuf(updP),
@@ -104,30 +128,39 @@ func (srv *server) EditProduct(ctx context.Context, req *apiv1.EditProductReques
 
 // DeleteProduct deletes Product resource from the DB.
 func (srv *server) DeleteProduct(ctx context.Context, req *apiv1.DeleteProductRequest) (*emptypb.Empty, error) {
-	zlog.Info().Msgf("Deleting product (%s)", req.GetId())
+	zlog.Info().Ctx(ctx).Msgf("Deleting product (%s)", req.GetId())
 	// sanity check
 	if req.GetId() == "" {
 		err := fmt.Errorf("product ID is not specified")
-		zlog.Error().Err(err).Msg("Failed to delete product")
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to delete product")
 		return nil, err
 	}
 
 	// deleting product from DB
-	err := db.DeleteProductByID(ctx, srv.dbClient, req.GetId())
+	err := srv.products.DeleteProductByID(ctx, req.GetId())
 	if err != nil {
 		return nil, err
 	}
 
 	// invalidating cache
-	srv.cache.DeleteProduct(req.GetId())
+	cache.Invalidate(ctx, srv.cache, cache.Invalidation{Products: []string{req.GetId()}, ProductLists: true})
 
 	return &emptypb.Empty{}, nil
 }
 
 // ListProducts lists all Product resources available in the DB.
 func (srv *server) ListProducts(ctx context.Context, _ *emptypb.Empty) (*apiv1.ListProductsResponse, error) {
-	zlog.Info().Msgf("Listing all products")
-	ps, err := db.ListProducts(ctx, srv.dbClient)
+	zlog.Info().Ctx(ctx).Msgf("Listing all products")
+	// checking cache first, generation is captured before the DB is queried
+	cached, generation, ok := srv.cache.GetProductList(ctx, allProductsQuery)
+	if ok && !db.ReadsFromPrimary(ctx) {
+		zlog.Info().Ctx(ctx).Msg("List of all products found in cache")
+		return &apiv1.ListProductsResponse{
+			Products: cached,
+		}, nil
+	}
+
+	ps, err := srv.products.ListProducts(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +169,10 @@ func (srv *server) ListProducts(ctx context.Context, _ *emptypb.Empty) (*apiv1.L
 	for _, p := range ps {
 		resp = append(resp, ConvertProductResourceToProtobuf(p))
 	}
+
+	// setting cache
+	srv.cache.SetProductList(ctx, generation, allProductsQuery, resp)
+
 	return &apiv1.ListProductsResponse{
 		Products: resp,
 	}, nil
@@ -143,38 +180,49 @@ func (srv *server) ListProducts(ctx context.Context, _ *emptypb.Empty) (*apiv1.L
 
 // CreateReview creates Review resource in the DB. Enough to specify only Product ID in the Product field.
 func (srv *server) CreateReview(ctx context.Context, req *apiv1.CreateReviewRequest) (*apiv1.CreateReviewResponse, error) {
-	zlog.Info().Msgf("Creating review %s", req.GetReview().GetId())
+	zlog.Info().Ctx(ctx).Msgf("Creating review %s", req.GetReview().GetId())
 	// sanity check
 	if req.GetReview() == nil {
 		err := fmt.Errorf("review resource is not specified")
-		zlog.Error().Err(err).Msg("Failed to create review")
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to create review")
 		return nil, err
 	}
 	if req.GetReview().GetFirstName() == "" || req.GetReview().GetLastName() == "" {
 		err := fmt.Errorf("reviewer's identity is not specified")
-		zlog.Error().Err(err).Msg("Failed to create review")
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to create review")
 		return nil, err
 	}
 	if req.GetReview().GetRating() < 1 || req.GetReview().GetRating() > 5 {
 		err := fmt.Errorf("reviewer's rating is out of range")
-		zlog.Error().Err(err).Msg("Failed to create review")
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to create review")
 		return nil, err
 	}
 	// allowing to create review with empty text
 
-	// creating review
-	r, err := db.CreateReview(ctx, srv.dbClient, req.GetReview().GetFirstName(), req.GetReview().GetLastName(),
-		req.GetReview().GetReviewText(), req.GetReview().GetRating(), req.GetReview().GetProduct().GetId())
+	// creating review, retried request returns originally created review
+	r, created, err := srv.reviews.CreateReviewIdempotent(ctx, getIdempotencyKey(ctx, req.GetIdempotencyKey()),
+		req.GetReview().GetFirstName(), req.GetReview().GetLastName(), req.GetReview().GetReviewText(),
+		req.GetReview().GetRating(), req.GetReview().GetProduct().GetId())
 	if err != nil {
-		return nil, err
+		return nil, idempotencyStatus(err)
+	}
+	if !created {
+		// review was created and announced by the original request
+		zlog.Info().Ctx(ctx).Msgf("Review %s was already created with the same idempotency key", r.ID)
+		return &apiv1.CreateReviewResponse{
+			Review: ConvertReviewResourceToProtobuf(r),
+		}, nil
 	}
 
-	// invalidating cache
-	srv.cache.DeleteReviews(req.GetReview().GetProduct().GetId())
-	srv.cache.DeleteProduct(req.GetReview().GetProduct().GetId()) // removing product entry so fresh data can be fetched during the Get operation
+	// invalidating cache, product entry is removed so fresh data can be fetched during the Get operation
+	srv.invalidateProductReviews(ctx, req.GetReview().GetProduct().GetId())
+
+	// notifying subscribers
+	srv.broker.Publish(req.GetReview().GetProduct().GetId(), ComposeWatchEvent(apiv1.ReviewAction_REVIEW_ACTION_CREATED,
+		r, req.GetReview().GetProduct().GetId(), r.Edges.Product.AverageRating))
 
 	// publishing event that review was created
-	err = rabbitmq.PublishMessage(ctx, srv.rabbitMQChannel, ComposeEventOnReviewChange("created",
+	err = srv.events.PublishEvent(ctx, "", ComposeEventOnReviewChange("created",
 		r.Rating, r.FirstName, r.LastName, req.GetReview().GetProduct().GetId()))
 	if err != nil {
 		return nil, err
@@ -187,40 +235,44 @@ func (srv *server) CreateReview(ctx context.Context, req *apiv1.CreateReviewRequ
 
 // GetReviewsByProductID retrieves Review resource by Product ID from DB.
 func (srv *server) GetReviewsByProductID(ctx context.Context, req *apiv1.GetReviewsByProductIDRequest) (*apiv1.GetReviewsByProductIDResponse, error) {
-	zlog.Info().Msgf("Retrieving Review by Product ID (%s)", req.GetId())
+	zlog.Info().Ctx(ctx).Msgf("Retrieving Review by Product ID (%s)", req.GetId())
 	// sanity check
 	if req.GetId() == "" {
 		err := fmt.Errorf("product ID is not specified")
-		zlog.Error().Err(err).Msg("Failed to retrieve review by product ID")
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to retrieve review by product ID")
 		return nil, err
 	}
 
 	// checking cache first
-	if rs, ok := srv.cache.GetReviews(req.GetId()); ok {
-		zlog.Info().Msgf("Reviews for product %s found in cache", req.GetId())
-		reviews := make([]*apiv1.Review, 0)
-		for _, r := range rs {
-			reviews = append(reviews, ConvertReviewResourceToProtobuf(r))
-		}
+	reviews, version, ok := srv.cache.GetReviews(ctx, req.GetId())
+	if ok && !db.ReadsFromPrimary(ctx) {
+		zlog.Info().Ctx(ctx).Msgf("Reviews for product %s found in cache", req.GetId())
 		return &apiv1.GetReviewsByProductIDResponse{
 			Reviews: reviews,
 		}, nil
 	}
 
-	// retrieving resource
-	rs, err := db.GetReviewsByProductID(ctx, srv.dbClient, req.GetId())
+	// retrieving resource, concurrent misses are coalesced into a single DB query
+	reviews, err := load(ctx, &srv.loads, loadKey(ctx, reviewsLoadKeyPrefix+req.GetId()), func(ctx context.Context) ([]*apiv1.Review, error) {
+		rs, err := srv.reviews.GetReviewsByProductID(ctx, req.GetId())
+		if err != nil {
+			return nil, err
+		}
+
+		// converting back to Protobuf format
+		reviews := make([]*apiv1.Review, 0)
+		for _, r := range rs {
+			reviews = append(reviews, ConvertReviewResourceToProtobuf(r))
+		}
+
+		// setting/updating cache, unless reviews were invalidated while being loaded
+		srv.cache.SetReviews(ctx, version, req.GetId(), reviews)
+		return reviews, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// setting/updating cache
-	srv.cache.SetReviews(req.GetId(), rs)
-
-	// converting back to Protobuf format
-	reviews := make([]*apiv1.Review, 0)
-	for _, r := range rs {
-		reviews = append(reviews, ConvertReviewResourceToProtobuf(r))
-	}
 	return &apiv1.GetReviewsByProductIDResponse{
 		Reviews: reviews,
 	}, nil
@@ -228,32 +280,35 @@ func (srv *server) GetReviewsByProductID(ctx context.Context, req *apiv1.GetRevi
 
 // EditReview updates specified fields of the Review resource in the DB.
 func (srv *server) EditReview(ctx context.Context, req *apiv1.EditReviewRequest) (*apiv1.EditReviewResponse, error) {
-	zlog.Info().Msgf("Editing review (%s)", req.GetReview().GetId())
+	zlog.Info().Ctx(ctx).Msgf("Editing review (%s)", req.GetReview().GetId())
 	// sanity check
 	if req.GetReview() == nil {
 		err := fmt.Errorf("review resource is not specified")
-		zlog.Error().Err(err).Msg("Failed to edit review")
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to edit review")
 		return nil, err
 	}
 	if req.GetReview().GetId() == "" {
 		err := fmt.Errorf("review ID is not specified")
-		zlog.Error().Err(err).Msg("Failed to edit review")
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to edit review")
 		return nil, err
 	}
 
 	// updating review resource
-	updR, err := db.EditReview(ctx, srv.dbClient, req.GetReview().GetId(), req.GetReview().GetFirstName(),
+	updR, err := srv.reviews.EditReview(ctx, req.GetReview().GetId(), req.GetReview().GetFirstName(),
 		req.GetReview().GetLastName(), req.GetReview().GetReviewText(), req.GetReview().GetRating())
 	if err != nil {
 		return nil, err
 	}
 
-	// invalidating cache
-	srv.cache.DeleteReviews(updR.Edges.Product.ID)
-	srv.cache.DeleteProduct(updR.Edges.Product.ID) // removing product entry so fresh data can be fetched during the Get operation
+	// invalidating cache, product entry is removed so fresh data can be fetched during the Get operation
+	srv.invalidateProductReviews(ctx, updR.Edges.Product.ID)
+
+	// notifying subscribers
+	srv.broker.Publish(updR.Edges.Product.ID, ComposeWatchEvent(apiv1.ReviewAction_REVIEW_ACTION_MODIFIED,
+		updR, updR.Edges.Product.ID, updR.Edges.Product.AverageRating))
 
 	// publishing event that review was modified
-	err = rabbitmq.PublishMessage(ctx, srv.rabbitMQChannel, ComposeEventOnReviewChange("modified", updR.Rating,
+	err = srv.events.PublishEvent(ctx, "", ComposeEventOnReviewChange("modified", updR.Rating,
 		updR.FirstName, updR.LastName, updR.Edges.Product.ID))
 	if err != nil {
 		return nil, err
@@ -266,37 +321,94 @@ func (srv *server) EditReview(ctx context.Context, req *apiv1.EditReviewRequest)
 
 // DeleteReview removes specified Review resource from the DB.
 func (srv *server) DeleteReview(ctx context.Context, req *apiv1.DeleteReviewRequest) (*emptypb.Empty, error) {
-	zlog.Info().Msgf("Deleting review (%s)", req.GetId())
+	zlog.Info().Ctx(ctx).Msgf("Deleting review (%s)", req.GetId())
 	// sanity check
 	if req.GetId() == "" {
 		err := fmt.Errorf("review ID is not specified")
-		zlog.Error().Err(err).Msg("Failed to delete review")
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to delete review")
 		return nil, err
 	}
 
 	// Querying review first to get a fancy-published message - in favor of unified published messages structure.
 	// Normally, this should be brought to forum with colleagues and defined what precisely we want to publish on bus. My take - IDs are simple enough.
 	// For the sake of better visibility for this task leaving it this way.
-	r, err := db.GetReviewByID(ctx, srv.dbClient, req.GetId())
+	r, err := srv.reviews.GetReviewByID(ctx, req.GetId())
 	if err != nil {
 		return nil, err
 	}
 
 	// removing review resource
-	err = db.DeleteReviewByID(ctx, srv.dbClient, req.GetId(), r.Edges.Product.ID)
+	updP, err := srv.reviews.DeleteReviewByID(ctx, req.GetId(), r.Edges.Product.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	// invalidating cache
-	srv.cache.DeleteReviews(r.Edges.Product.ID)
-	srv.cache.DeleteProduct(r.Edges.Product.ID) // removing product entry so fresh data can be fetched during the Get operation
+	// invalidating cache, product entry is removed so fresh data can be fetched during the Get operation
+	srv.invalidateProductReviews(ctx, r.Edges.Product.ID)
+
+	// notifying subscribers
+	srv.broker.Publish(r.Edges.Product.ID, ComposeWatchEvent(apiv1.ReviewAction_REVIEW_ACTION_DELETED,
+		r, r.Edges.Product.ID, updP.AverageRating))
 
 	// publishing event that review was deleted
-	err = rabbitmq.PublishMessage(ctx, srv.rabbitMQChannel, ComposeEventOnReviewChange("deleted", r.Rating, r.FirstName, r.LastName, r.Edges.Product.ID))
+	err = srv.events.PublishEvent(ctx, "", ComposeEventOnReviewChange("deleted", r.Rating, r.FirstName, r.LastName, r.Edges.Product.ID))
 	if err != nil {
 		return nil, err
 	}
 
 	return &emptypb.Empty{}, nil
 }
+
+// WatchProductReviews streams changes of Review resources of the specified Product until client disconnects.
+func (srv *server) WatchProductReviews(req *apiv1.WatchProductReviewsRequest, stream grpc.ServerStreamingServer[apiv1.WatchProductReviewsResponse]) error {
+	zlog.Info().Ctx(stream.Context()).Msgf("Watching reviews of product (%s)", req.GetProductId())
+	// sanity check
+	if req.GetProductId() == "" {
+		err := fmt.Errorf("product ID is not specified")
+		zlog.Error().Ctx(stream.Context()).Err(err).Msg("Failed to watch reviews of the product")
+		return err
+	}
+	// making sure that product exists
+	_, err := srv.products.GetProductByID(stream.Context(), req.GetProductId())
+	if err != nil {
+		return err
+	}
+
+	sub := srv.broker.Subscribe(req.GetProductId())
+	defer srv.broker.Unsubscribe(sub)
+	// sending headers right away, so the client knows that subscription is established
+	if err = stream.SendHeader(metadata.MD{}); err != nil {
+		zlog.Error().Ctx(stream.Context()).Err(err).Msgf("Failed to establish watch of product (%s)", req.GetProductId())
+		return err
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			zlog.Info().Ctx(stream.Context()).Msgf("Stopped watching reviews of product (%s)", req.GetProductId())
+			return nil
+		case <-sub.dropped:
+			err = fmt.Errorf("subscriber is not able to keep up with the changes, some of them were dropped")
+			zlog.Error().Ctx(stream.Context()).Err(err).Msgf("Stopped watching reviews of product (%s)", req.GetProductId())
+			return err
+		case <-srv.broker.Done():
+			// server is shutting down, delivering events, which are still pending
+			for {
+				select {
+				case event := <-sub.events:
+					if err = stream.Send(event); err != nil {
+						zlog.Error().Ctx(stream.Context()).Err(err).Msgf("Failed to send change of review to the watcher of product (%s)", req.GetProductId())
+						return err
+					}
+				default:
+					zlog.Info().Ctx(stream.Context()).Msgf("Stopped watching reviews of product (%s), server is shutting down", req.GetProductId())
+					return nil
+				}
+			}
+		case event := <-sub.events:
+			if err = stream.Send(event); err != nil {
+				zlog.Error().Ctx(stream.Context()).Err(err).Msgf("Failed to send change of review to the watcher of product (%s)", req.GetProductId())
+				return err
+			}
+		}
+	}
+}
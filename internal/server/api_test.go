@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/eroshiva/cloudtalk/internal/cache"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// newTestServer returns handlers backed by the in-memory store and the local cache.
+func newTestServer(t *testing.T) (*server, db.Store) {
+	store := db.NewMemoryStore()
+	c, err := cache.New(cache.DefaultConfig())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, c.Close())
+	})
+	return &server{
+		products: store,
+		reviews:  store,
+		events:   NewMemoryMessaging(),
+		cache:    c,
+		broker:   newBroker(defaultSubscriberBufferSize),
+	}, store
+}
+
+func TestProductHandlers(t *testing.T) {
+	srv, _ := newTestServer(t)
+	ctx := context.Background()
+
+	created, err := srv.CreateProduct(ctx, &apiv1.CreateProductRequest{
+		Product: &apiv1.Product{Name: "myAwesomeProduct", Description: "Product description", Price: "19.90"},
+	})
+	require.NoError(t, err)
+	id := created.GetProduct().GetId()
+	require.NotEmpty(t, id)
+
+	got, err := srv.GetProductByID(ctx, &apiv1.GetProductByIDRequest{Id: id})
+	require.NoError(t, err)
+	assert.Equal(t, "myAwesomeProduct", got.GetProduct().GetName())
+
+	// edit invalidates the cached product
+	_, err = srv.EditProduct(ctx, &apiv1.EditProductRequest{Product: &apiv1.Product{Id: id, Price: "9.90"}})
+	require.NoError(t, err)
+	got, err = srv.GetProductByID(ctx, &apiv1.GetProductByIDRequest{Id: id})
+	require.NoError(t, err)
+	assert.Equal(t, "9.90", got.GetProduct().GetPrice())
+
+	list, err := srv.ListProducts(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, list.GetProducts(), 1)
+
+	_, err = srv.DeleteProduct(ctx, &apiv1.DeleteProductRequest{Id: id})
+	require.NoError(t, err)
+	_, err = srv.GetProductByID(ctx, &apiv1.GetProductByIDRequest{Id: id})
+	require.ErrorContains(t, err, "is not found")
+	// missing product is remembered
+	assert.True(t, srv.cache.IsProductNotFound(ctx, id))
+	list, err = srv.ListProducts(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	assert.Empty(t, list.GetProducts())
+}
+
+func TestIdempotencyKeyReusedHandler(t *testing.T) {
+	srv, _ := newTestServer(t)
+	ctx := context.Background()
+
+	req := &apiv1.CreateProductRequest{
+		Product:        &apiv1.Product{Name: "myAwesomeProduct", Description: "Product description", Price: "19.90"},
+		IdempotencyKey: "product-key",
+	}
+	created, err := srv.CreateProduct(ctx, req)
+	require.NoError(t, err)
+	retried, err := srv.CreateProduct(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, created.GetProduct().GetId(), retried.GetProduct().GetId())
+
+	// the same key with a different product is rejected
+	req.Product.Price = "29.90"
+	_, err = srv.CreateProduct(ctx, req)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestGetReviewsByProductIDHandler(t *testing.T) {
+	srv, store := newTestServer(t)
+	ctx := context.Background()
+
+	p, err := store.CreateProduct(ctx, "myAwesomeProduct", "Product description", "19.90")
+	require.NoError(t, err)
+	_, err = store.CreateReview(ctx, "John", "Doe", "Product is good!", 5, p.ID)
+	require.NoError(t, err)
+	_, err = store.CreateReview(ctx, "Theo", "Walcott", "Product is OK.", 4, p.ID)
+	require.NoError(t, err)
+
+	resp, err := srv.GetReviewsByProductID(ctx, &apiv1.GetReviewsByProductIDRequest{Id: p.ID})
+	require.NoError(t, err)
+	assert.Len(t, resp.GetReviews(), 2)
+
+	got, err := srv.GetProductByID(ctx, &apiv1.GetProductByIDRequest{Id: p.ID})
+	require.NoError(t, err)
+	assert.InDelta(t, 4.5, got.GetProduct().GetAverageRating(), 0.001)
+	assert.Len(t, got.GetProduct().GetReviews(), 2)
+}
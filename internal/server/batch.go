@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/eroshiva/cloudtalk/internal/ent"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+)
+
+// maxBatchSize limits number of resources processed by a single batch request.
+const maxBatchSize = 1000
+
+// checkBatchSize performs sanity check of the number of resources in the batch request.
+func checkBatchSize(size int) error {
+	if size == 0 {
+		return fmt.Errorf("batch is empty")
+	}
+	if size > maxBatchSize {
+		return fmt.Errorf("batch contains %d resources, which is more than allowed %d", size, maxBatchSize)
+	}
+	return nil
+}
+
+// BatchGetProducts retrieves multiple Product resources by specified IDs, either from cache or from the DB with a single query.
+func (srv *server) BatchGetProducts(ctx context.Context, req *apiv1.BatchGetProductsRequest) (*apiv1.BatchGetProductsResponse, error) {
+	zlog.Info().Ctx(ctx).Msgf("Retrieving %d products by their IDs", len(req.GetIds()))
+	// sanity check
+	if err := checkBatchSize(len(req.GetIds())); err != nil {
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to retrieve products by their IDs")
+		return nil, err
+	}
+
+	results := make([]*apiv1.BatchProductResult, len(req.GetIds()))
+	missing := make([]string, 0)         // IDs of products, which are not cached
+	missingIdx := make([]int, 0)         // positions of not cached products in the response
+	missingVersions := make([]uint64, 0) // versions of cache entries of not cached products
+	for i, id := range req.GetIds() {
+		results[i] = &apiv1.BatchProductResult{}
+		if id == "" {
+			results[i].Error = "ID is not specified"
+			continue
+		}
+		// checking cache first, reads pinned to the primary DB skip entries, which may be loaded from the read replica
+		p, version, ok := srv.cache.GetProduct(ctx, id)
+		if ok && !db.ReadsFromPrimary(ctx) {
+			results[i].Product = p
+			continue
+		}
+		missing = append(missing, id)
+		missingIdx = append(missingIdx, i)
+		missingVersions = append(missingVersions, version)
+	}
+
+	if len(missing) > 0 {
+		// retrieving the rest of the products
+		rs, err := srv.products.GetProductsByIDs(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for j, r := range rs {
+			if r.Err != nil {
+				results[missingIdx[j]].Error = r.Err.Error()
+				continue
+			}
+			// setting cache
+			results[missingIdx[j]].Product = ConvertProductResourceToProtobuf(r.Product)
+			srv.cache.SetProduct(ctx, missingVersions[j], results[missingIdx[j]].Product)
+		}
+	}
+
+	return &apiv1.BatchGetProductsResponse{
+		Results: results,
+	}, nil
+}
+
+// BatchCreateReviews creates multiple Review resources in the DB within a single transaction.
+// Invalid Review resources are reported per item and don't prevent others from being created.
+func (srv *server) BatchCreateReviews(ctx context.Context, req *apiv1.BatchCreateReviewsRequest) (*apiv1.BatchCreateReviewsResponse, error) {
+	zlog.Info().Ctx(ctx).Msgf("Creating %d reviews", len(req.GetReviews()))
+	// sanity check
+	if err := checkBatchSize(len(req.GetReviews())); err != nil {
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to create reviews")
+		return nil, err
+	}
+
+	inputs := make([]db.ReviewInput, 0, len(req.GetReviews()))
+	for _, r := range req.GetReviews() {
+		inputs = append(inputs, db.ReviewInput{
+			FirstName: r.GetFirstName(),
+			LastName:  r.GetLastName(),
+			Text:      r.GetReviewText(),
+			Rating:    r.GetRating(),
+			ProductID: r.GetProduct().GetId(),
+		})
+	}
+
+	// creating reviews
+	rs, err := srv.reviews.CreateReviews(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.BatchCreateReviewsResponse{
+		Results: srv.announceBatchReviewChanges(ctx, rs, apiv1.ReviewAction_REVIEW_ACTION_CREATED, "created"),
+	}, nil
+}
+
+// BatchDeleteReviews removes multiple Review resources from the DB within a single transaction.
+func (srv *server) BatchDeleteReviews(ctx context.Context, req *apiv1.BatchDeleteReviewsRequest) (*apiv1.BatchDeleteReviewsResponse, error) {
+	zlog.Info().Ctx(ctx).Msgf("Deleting %d reviews", len(req.GetIds()))
+	// sanity check
+	if err := checkBatchSize(len(req.GetIds())); err != nil {
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to delete reviews")
+		return nil, err
+	}
+
+	// removing review resources
+	rs, err := srv.reviews.DeleteReviewsByIDs(ctx, req.GetIds())
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.BatchDeleteReviewsResponse{
+		Results: srv.announceBatchReviewChanges(ctx, rs, apiv1.ReviewAction_REVIEW_ACTION_DELETED, "deleted"),
+	}, nil
+}
+
+// announceBatchReviewChanges invalidates cache of all affected Products at once, notifies subscribers and publishes one event per
+// each changed Review resource. Returns results of the batch operation in Protobuf notation.
+// Changes are already committed, thus failed events are only logged and the results are always returned,
+// otherwise a retrying client would repeat the whole batch.
+func (srv *server) announceBatchReviewChanges(ctx context.Context, rs []db.ReviewResult, action apiv1.ReviewAction, actionName string,
+) []*apiv1.BatchReviewResult {
+	results := make([]*apiv1.BatchReviewResult, len(rs))
+	changed := make([]*ent.Review, 0, len(rs))
+	invalidated := make(map[string]bool)
+	productIDs := make([]string, 0)
+	for i, r := range rs {
+		results[i] = &apiv1.BatchReviewResult{}
+		if r.Err != nil {
+			results[i].Error = r.Err.Error()
+			continue
+		}
+		results[i].Review = ConvertReviewResourceToProtobuf(r.Review)
+		if r.Review.Edges.Product == nil {
+			// review doesn't belong to any product, nothing else to announce
+			continue
+		}
+		changed = append(changed, r.Review)
+		if productID := r.Review.Edges.Product.ID; !invalidated[productID] {
+			productIDs = append(productIDs, productID)
+			invalidated[productID] = true
+		}
+	}
+	if len(changed) == 0 {
+		return results
+	}
+
+	// invalidating cache before subscribers are notified, so they don't fetch stale data
+	srv.invalidateProductReviews(ctx, productIDs...)
+
+	failed := 0
+	for _, r := range changed {
+		productID := r.Edges.Product.ID
+
+		// notifying subscribers
+		srv.broker.Publish(productID, ComposeWatchEvent(action, r, productID, r.Edges.Product.AverageRating))
+
+		// publishing event per each review
+		err := srv.events.PublishEvent(ctx, "", ComposeEventOnReviewChange(actionName,
+			r.Rating, r.FirstName, r.LastName, productID))
+		if err != nil {
+			zlog.Error().Ctx(ctx).Err(err).Msgf("Failed to publish event on %s review %s", actionName, r.ID)
+			failed++
+		}
+	}
+	if failed > 0 {
+		zlog.Warn().Ctx(ctx).Msgf("Events on %d out of %d %s reviews were not published", failed, len(rs), actionName)
+	}
+	return results
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"sync"
+
+	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+)
+
+// defaultSubscriberBufferSize defines how many events may be queued for a single subscriber before it is considered slow.
+const defaultSubscriberBufferSize = 64
+
+// subscription represents a single subscriber to the changes of Review resources of a Product.
+type subscription struct {
+	productID string
+	events    chan *apiv1.WatchProductReviewsResponse
+	// dropped is closed when the subscriber is not able to keep up with the events and was disconnected
+	dropped chan struct{}
+	once    sync.Once
+}
+
+// drop disconnects subscriber from receiving further events.
+func (s *subscription) drop() {
+	s.once.Do(func() {
+		close(s.dropped)
+	})
+}
+
+// broker is an in-process fan-out broker, which delivers changes of Review resources to all subscribers of a Product.
+// Publishing never blocks - subscribers, which are not able to keep up with the events, are dropped.
+type broker struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[*subscription]struct{} // subscribers by Product ID
+	bufferSize  int
+	// closed is closed once the server is shutting down, subscribers deliver pending events and stop
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// newBroker creates a new fan-out broker.
+func newBroker(bufferSize int) *broker {
+	return &broker{
+		subscribers: make(map[string]map[*subscription]struct{}),
+		bufferSize:  bufferSize,
+		closed:      make(chan struct{}),
+	}
+}
+
+// Close signals all subscribers to deliver pending events and to stop watching.
+func (b *broker) Close() {
+	b.closeOnce.Do(func() {
+		close(b.closed)
+	})
+}
+
+// Done returns channel, which is closed once the broker is closed.
+func (b *broker) Done() <-chan struct{} {
+	return b.closed
+}
+
+// Subscribe registers a new subscriber to the changes of Review resources of the specified Product.
+func (b *broker) Subscribe(productID string) *subscription {
+	s := &subscription{
+		productID: productID,
+		events:    make(chan *apiv1.WatchProductReviewsResponse, b.bufferSize),
+		dropped:   make(chan struct{}),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[productID]; !ok {
+		b.subscribers[productID] = make(map[*subscription]struct{})
+	}
+	b.subscribers[productID][s] = struct{}{}
+	return s
+}
+
+// Unsubscribe removes subscriber from the broker.
+func (b *broker) Unsubscribe(s *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscribers[s.productID], s)
+	if len(b.subscribers[s.productID]) == 0 {
+		delete(b.subscribers, s.productID)
+	}
+}
+
+// Publish delivers event to all subscribers of the specified Product.
+func (b *broker) Publish(productID string, event *apiv1.WatchProductReviewsResponse) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for s := range b.subscribers[productID] {
+		select {
+		case s.events <- event:
+		default:
+			// subscriber's buffer is full, dropping the subscriber instead of blocking the writer
+			zlog.Warn().Msgf("Subscriber to product %s is too slow, dropping it", productID)
+			s.drop()
+		}
+	}
+}
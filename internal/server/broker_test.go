@@ -0,0 +1,56 @@
+package server
+
+import (
+	"testing"
+
+	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBrokerDropsSlowSubscribers(t *testing.T) {
+	b := newBroker(1)
+	fast := b.Subscribe("product-1")
+	slow := b.Subscribe("product-1")
+	other := b.Subscribe("product-2")
+	t.Cleanup(func() {
+		b.Unsubscribe(fast)
+		b.Unsubscribe(slow)
+		b.Unsubscribe(other)
+	})
+
+	// first event fits into buffers of both subscribers
+	b.Publish("product-1", &apiv1.WatchProductReviewsResponse{Action: apiv1.ReviewAction_REVIEW_ACTION_CREATED})
+	assert.Equal(t, apiv1.ReviewAction_REVIEW_ACTION_CREATED, (<-fast.events).GetAction())
+
+	// second event doesn't fit into the buffer of slow subscriber, it must be dropped without blocking the publisher
+	b.Publish("product-1", &apiv1.WatchProductReviewsResponse{Action: apiv1.ReviewAction_REVIEW_ACTION_MODIFIED})
+	assert.Equal(t, apiv1.ReviewAction_REVIEW_ACTION_MODIFIED, (<-fast.events).GetAction())
+	select {
+	case <-slow.dropped:
+	default:
+		t.Fatal("slow subscriber was not dropped")
+	}
+
+	// subscriber to other product doesn't receive anything
+	assert.Empty(t, other.events)
+}
+
+func TestBrokerClose(t *testing.T) {
+	b := newBroker(2)
+	s := b.Subscribe("product-1")
+	t.Cleanup(func() {
+		b.Unsubscribe(s)
+	})
+	b.Publish("product-1", &apiv1.WatchProductReviewsResponse{Action: apiv1.ReviewAction_REVIEW_ACTION_CREATED})
+
+	b.Close()
+	// closing twice is safe
+	b.Close()
+	select {
+	case <-b.Done():
+	default:
+		t.Fatal("broker is expected to be closed")
+	}
+	// events published before closing remain pending for delivery
+	assert.Equal(t, apiv1.ReviewAction_REVIEW_ACTION_CREATED, (<-s.events).GetAction())
+}
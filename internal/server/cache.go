@@ -1,96 +1,40 @@
 package server
 
 import (
-	"time"
+	"context"
 
-	"github.com/eroshiva/cloudtalk/internal/ent"
-	"github.com/maypok86/otter"
+	"github.com/eroshiva/cloudtalk/internal/cache"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
 )
 
-const defaultCachingTimeout = time.Minute
+// warmUpCache loads products with the most reviews (together with their reviews) into the cache.
+// Failure is not fatal, the cache is populated on demand anyway.
+func warmUpCache(ctx context.Context, c cache.Cache, products db.ProductStore, limit int) {
+	zlog.Info().Ctx(ctx).Msgf("Warming up cache with %d products with the most reviews", limit)
+	ctx, cancel := context.WithTimeout(ctx, defaultLoadTimeout)
+	defer cancel()
 
-// Cache is a struct for caching operations.
-type Cache struct {
-	c otter.Cache[string, any]
-}
-
-// NewCache creates a new cache.
-func NewCache() (*Cache, error) {
-	c, err := otter.MustBuilder[string, any](1000). // default 1000 items capacity
-							CollectStats().
-							Cost(func(_ string, _ any) uint32 {
-			return 1 // equal cost for each entry
-		}).
-		WithTTL(defaultCachingTimeout).
-		Build()
+	ps, err := products.ListTopProducts(ctx, limit)
 	if err != nil {
-		return nil, err
-	}
-
-	return &Cache{c: c}, nil
-}
-
-// GetProduct gets a product from the cache.
-func (c *Cache) GetProduct(id string) (*ent.Product, bool) {
-	p, ok := c.c.Get(id)
-	if !ok {
-		return nil, false
+		zlog.Warn().Ctx(ctx).Err(err).Msg("Failed to warm up cache")
+		return
 	}
-
-	// casting back to original structure
-	product, ok := p.(*ent.Product)
-	if !ok {
-		return nil, false
+	for _, p := range ps {
+		product := ConvertProductResourceToProtobuf(p)
+		_, version, _ := c.GetProduct(ctx, p.ID)
+		c.SetProduct(ctx, version, product)
+		_, version, _ = c.GetReviews(ctx, p.ID)
+		c.SetReviews(ctx, version, p.ID, product.GetReviews())
 	}
-	return product, ok
-}
-
-// SetProduct sets a product in the cache.
-func (c *Cache) SetProduct(p *ent.Product) {
-	c.c.Set(p.ID, p)
-}
-
-// DeleteProduct deletes a product from the cache.
-func (c *Cache) DeleteProduct(id string) {
-	c.c.Delete(id)
-}
-
-// GetProducts gets all products from the cache.
-func (c *Cache) GetProducts() ([]*ent.Product, bool) {
-	p, ok := c.c.Get("products")
-	if !ok {
-		return nil, false
-	}
-
-	// casting back to original structure
-	products, ok := p.([]*ent.Product)
-	if !ok {
-		return nil, false
-	}
-	return products, true
-}
-
-// GetReviews gets reviews from the cache.
-func (c *Cache) GetReviews(productID string) ([]*ent.Review, bool) {
-	r, ok := c.c.Get("reviews:" + productID)
-	if !ok {
-		return nil, false
-	}
-
-	// casting back to original structure
-	reviews, ok := r.([]*ent.Review)
-	if !ok {
-		return nil, false
-	}
-	return reviews, true
-}
-
-// SetReviews sets reviews in the cache.
-func (c *Cache) SetReviews(productID string, reviews []*ent.Review) {
-	c.c.Set("reviews:"+productID, reviews)
+	zlog.Info().Ctx(ctx).Msgf("Cache is warmed up with %d products", len(ps))
 }
 
-// DeleteReviews deletes reviews from the cache.
-func (c *Cache) DeleteReviews(productID string) {
-	c.c.Delete("reviews:" + productID)
+// invalidateProductReviews evicts reviews and entries of the products (so fresh average rating is fetched during
+// the Get operation) together with product lists. All entries are invalidated with a single broadcast.
+func (srv *server) invalidateProductReviews(ctx context.Context, productIDs ...string) {
+	cache.Invalidate(ctx, srv.cache, cache.Invalidation{
+		Products:     productIDs,
+		Reviews:      productIDs,
+		ProductLists: true,
+	})
 }
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+
+	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/swaggest/swgui/v5emb"
+)
+
+const (
+	// OpenAPIPath is a path of the HTTP gateway, where OpenAPI document of the API is served.
+	OpenAPIPath = "/openapi.json"
+	// SwaggerUIPath is a path of the HTTP gateway, where Swagger UI exploring the API is served.
+	SwaggerUIPath = "/docs"
+
+	swaggerUITitle = "Product Reviews Service"
+)
+
+// registerDocsHandlers registers endpoints serving OpenAPI document and Swagger UI at the HTTP gateway.
+// Both are embedded in the binary, thus no external resources are needed.
+func registerDocsHandlers(mux *runtime.ServeMux) error {
+	err := mux.HandlePath(http.MethodGet, OpenAPIPath, func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(apiv1.OpenAPI); err != nil {
+			zlog.Error().Err(err).Msg("Failed to write OpenAPI document")
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	ui := v5emb.New(swaggerUITitle, OpenAPIPath, SwaggerUIPath+"/")
+	handleUI := func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ui.ServeHTTP(w, r)
+	}
+	// index page and its assets
+	if err = mux.HandlePath(http.MethodGet, SwaggerUIPath, handleUI); err != nil {
+		return err
+	}
+	return mux.HandlePath(http.MethodGet, SwaggerUIPath+"/{asset=**}", handleUI)
+}
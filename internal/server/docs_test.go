@@ -0,0 +1,37 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocsHandlers(t *testing.T) {
+	mux := runtime.NewServeMux()
+	require.NoError(t, registerDocsHandlers(mux))
+
+	// OpenAPI document is served as is
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, OpenAPIPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	doc := struct {
+		Paths map[string]any `json:"paths"`
+	}{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&doc))
+	assert.Contains(t, doc.Paths, "/v1/product/all")
+
+	// Swagger UI refers to the OpenAPI document and its assets are embedded
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, SwaggerUIPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), OpenAPIPath)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, SwaggerUIPath+"/swagger-ui-bundle.js", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// LivenessPath is a path of the HTTP gateway, which reports whether the service is running.
+	LivenessPath = "/healthz"
+	// ReadinessPath is a path of the HTTP gateway, which reports whether the service is able to serve requests.
+	ReadinessPath = "/readyz"
+
+	// healthCheckInterval is a period, in which status of the gRPC health service is refreshed.
+	healthCheckInterval = 5 * time.Second
+	// healthCheckTimeout bounds time spent on a single dependency check.
+	healthCheckTimeout = 2 * time.Second
+
+	statusOK       = "ok"
+	statusReady    = "ready"
+	statusNotReady = "not ready"
+)
+
+// healthCheck verifies a single dependency of the service.
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// readinessReport is a response of the readiness endpoint.
+type readinessReport struct {
+	Status string `json:"status"`
+	// Checks holds result of each dependency check, either "ok" or the error message.
+	Checks map[string]string `json:"checks"`
+}
+
+// healthChecker verifies dependencies of the service and reports the result over the gRPC health service
+// and the HTTP readiness endpoint.
+type healthChecker struct {
+	checks []healthCheck
+	health *health.Server
+	stop   chan struct{}
+	once   sync.Once
+}
+
+// newHealthChecker creates checker of the store connectivity, messaging state and the schema migration status.
+func newHealthChecker(store db.Store, messaging Messaging) *healthChecker {
+	return &healthChecker{
+		checks: []healthCheck{
+			{name: "db", check: func(ctx context.Context) error {
+				return store.Ping(ctx)
+			}},
+			{name: "messaging", check: messaging.Check},
+			{name: "migration", check: func(_ context.Context) error {
+				return store.MigrationStatus()
+			}},
+		},
+		health: health.NewServer(),
+		stop:   make(chan struct{}),
+	}
+}
+
+// check runs all dependency checks. Service is ready, if all of them pass.
+func (hc *healthChecker) check(ctx context.Context) readinessReport {
+	report := readinessReport{Status: statusReady, Checks: make(map[string]string, len(hc.checks))}
+	for _, c := range hc.checks {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := c.check(checkCtx)
+		cancel()
+		if err != nil {
+			zlog.Warn().Ctx(ctx).Err(err).Msgf("Health check %s failed", c.name)
+			report.Status = statusNotReady
+			report.Checks[c.name] = err.Error()
+			continue
+		}
+		report.Checks[c.name] = statusOK
+	}
+	return report
+}
+
+// update sets status of the gRPC health service according to the result of dependency checks.
+func (hc *healthChecker) update(ctx context.Context) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if hc.check(ctx).Status != statusReady {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	hc.health.SetServingStatus("", status)
+	hc.health.SetServingStatus(apiv1.ProductReviewsService_ServiceDesc.ServiceName, status)
+}
+
+// run refreshes status of the gRPC health service periodically until the checker is shut down.
+func (hc *healthChecker) run() {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		hc.update(context.Background())
+		select {
+		case <-hc.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// shutdown reports the service as not serving, so clients stop sending new requests, and stops periodic checks.
+func (hc *healthChecker) shutdown() {
+	hc.once.Do(func() {
+		close(hc.stop)
+		hc.health.Shutdown()
+	})
+}
+
+// registerHealthHandlers registers liveness and readiness endpoints at the HTTP gateway.
+func registerHealthHandlers(mux *runtime.ServeMux, hc *healthChecker) error {
+	err := mux.HandlePath(http.MethodGet, LivenessPath, func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": statusOK})
+	})
+	if err != nil {
+		return err
+	}
+
+	return mux.HandlePath(http.MethodGet, ReadinessPath, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		report := hc.check(r.Context())
+		status := http.StatusOK
+		if report.Status != statusReady {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, report)
+	})
+}
+
+// writeJSON writes the value as a JSON response with the provided status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		zlog.Error().Err(err).Msg("Failed to write response")
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func newTestHealthChecker(dbErr error) *healthChecker {
+	return &healthChecker{
+		checks: []healthCheck{
+			{name: "db", check: func(context.Context) error { return dbErr }},
+			{name: "rabbitmq", check: func(context.Context) error { return nil }},
+		},
+		health: health.NewServer(),
+		stop:   make(chan struct{}),
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	for name, tc := range map[string]struct {
+		dbErr      error
+		httpStatus int
+		grpcStatus healthpb.HealthCheckResponse_ServingStatus
+	}{
+		"ready":     {httpStatus: http.StatusOK, grpcStatus: healthpb.HealthCheckResponse_SERVING},
+		"not ready": {dbErr: fmt.Errorf("connection refused"), httpStatus: http.StatusServiceUnavailable, grpcStatus: healthpb.HealthCheckResponse_NOT_SERVING},
+	} {
+		t.Run(name, func(t *testing.T) {
+			hc := newTestHealthChecker(tc.dbErr)
+			mux := runtime.NewServeMux()
+			require.NoError(t, registerHealthHandlers(mux, hc))
+
+			// liveness doesn't depend on dependencies
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, LivenessPath, nil))
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			rec = httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+			assert.Equal(t, tc.httpStatus, rec.Code)
+			var report readinessReport
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+			assert.Equal(t, statusOK, report.Checks["rabbitmq"])
+			if tc.dbErr != nil {
+				assert.Equal(t, tc.dbErr.Error(), report.Checks["db"])
+			}
+
+			hc.update(context.Background())
+			resp, err := hc.health.Check(context.Background(), &healthpb.HealthCheckRequest{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.grpcStatus, resp.GetStatus())
+
+			// service is reported as not serving once it is being shut down
+			hc.shutdown()
+			resp, err = hc.health.Check(context.Background(), &healthpb.HealthCheckRequest{})
+			require.NoError(t, err)
+			assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+		})
+	}
+}
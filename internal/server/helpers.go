@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/eroshiva/cloudtalk/internal/ent"
 )
 
 // CreateProductRequest is a wrapper for CreateProductRequest struct.
@@ -86,8 +87,55 @@ func GetReviewsByProductIDRequest(id string) *apiv1.GetReviewsByProductIDRequest
 	}
 }
 
+// WatchProductReviewsRequest is a wrapper for WatchProductReviewsRequest struct.
+func WatchProductReviewsRequest(productID string) *apiv1.WatchProductReviewsRequest {
+	return &apiv1.WatchProductReviewsRequest{
+		ProductId: productID,
+	}
+}
+
+// BatchGetProductsRequest is a wrapper for BatchGetProductsRequest struct.
+func BatchGetProductsRequest(ids ...string) *apiv1.BatchGetProductsRequest {
+	return &apiv1.BatchGetProductsRequest{
+		Ids: ids,
+	}
+}
+
+// BatchCreateReviewsRequest is a wrapper for BatchCreateReviewsRequest struct.
+func BatchCreateReviewsRequest(reviews ...*apiv1.Review) *apiv1.BatchCreateReviewsRequest {
+	return &apiv1.BatchCreateReviewsRequest{
+		Reviews: reviews,
+	}
+}
+
+// BatchDeleteReviewsRequest is a wrapper for BatchDeleteReviewsRequest struct.
+func BatchDeleteReviewsRequest(ids ...string) *apiv1.BatchDeleteReviewsRequest {
+	return &apiv1.BatchDeleteReviewsRequest{
+		Ids: ids,
+	}
+}
+
+// ComposeWatchEvent function composes an event that is streamed to the watchers of the Product on any review change.
+func ComposeWatchEvent(action apiv1.ReviewAction, r *ent.Review, productID string, averageRating float64) *apiv1.WatchProductReviewsResponse {
+	review := ConvertReviewResourceToProtobuf(r)
+	review.Product = &apiv1.Product{
+		Id: productID,
+	}
+	return &apiv1.WatchProductReviewsResponse{
+		Action:        action,
+		Review:        review,
+		AverageRating: averageRating,
+	}
+}
+
 // ComposeEventOnReviewChange function composes a one-liner that is published to the RabbitMQ on any review event (addition, change, deletion).
 func ComposeEventOnReviewChange(action string, rating int32, name, lastName, productID string) string {
 	return fmt.Sprintf("%s: Review scoring %d from %s %s for product %s",
 		strings.ToUpper(action), rating, name, lastName, productID)
 }
+
+// ComposeEventOnProductSnapshot function composes a one-liner that is published to the RabbitMQ when product's state is re-emitted.
+func ComposeEventOnProductSnapshot(productID, name string, averageRating float64, reviewCount int) string {
+	return fmt.Sprintf("%s: Product %s (%s) has average rating %.2f from %d review(s)",
+		strings.ToUpper(actionSnapshot), productID, name, averageRating, reviewCount)
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// keys of the coalesced DB loads
+	productLoadKeyPrefix = "product:"
+	reviewsLoadKeyPrefix = "reviews:"
+	// primaryLoadKeyPrefix separates loads pinned to the primary DB, so they are not shared with loads from the read replica
+	primaryLoadKeyPrefix = "primary:"
+
+	// defaultLoadTimeout bounds a coalesced DB load, which is detached from the cancellation of the request started it.
+	defaultLoadTimeout = 10 * time.Second
+)
+
+// loadKey returns key of the load, which reads from the DB selected by the context.
+func loadKey(ctx context.Context, key string) string {
+	if db.ReadsFromPrimary(ctx) {
+		return primaryLoadKeyPrefix + key
+	}
+	return key
+}
+
+// load runs fn once for all concurrent callers asking for the same key and shares its result between them.
+// The load is detached from the cancellation of the caller, which has started it, so other callers don't fail
+// when that one goes away. Each caller still stops waiting once its own context is done.
+func load[T any](ctx context.Context, group *singleflight.Group, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	ch := group.DoChan(key, func() (any, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), defaultLoadTimeout)
+		defer cancel()
+		return fn(loadCtx)
+	})
+
+	var zero T
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case res := <-ch:
+		if res.Shared {
+			zlog.Debug().Ctx(ctx).Msgf("Load of %s was shared with concurrent requests", key)
+		}
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		v, ok := res.Val.(T)
+		if !ok {
+			return zero, fmt.Errorf("unexpected type %T of loaded %s", res.Val, key)
+		}
+		return v, nil
+	}
+}
+
+// productNotFoundError returns error reported when the product doesn't exist.
+func productNotFoundError(id string) error {
+	return fmt.Errorf("product with ID (%s) is not found", id)
+}
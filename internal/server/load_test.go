@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/singleflight"
+)
+
+func TestLoadCoalescesConcurrentMisses(t *testing.T) {
+	var group singleflight.Group
+	var calls atomic.Int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]string, callers)
+	for i := range callers {
+		wg.Go(func() {
+			v, err := load(context.Background(), &group, "product:1", func(_ context.Context) (string, error) {
+				if calls.Add(1) == 1 {
+					close(started)
+				}
+				<-release // holding the load until all callers are waiting for it
+				return "product", nil
+			})
+			assert.NoError(t, err)
+			results[i] = v
+		})
+	}
+
+	<-started
+	// cancelled caller stops waiting, but the load continues for the others
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := load(ctx, &group, "product:1", func(_ context.Context) (string, error) {
+		return "", nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+
+	// giving other callers time to join the load in flight
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	// load was performed only once
+	assert.Equal(t, int32(1), calls.Load())
+	for _, r := range results {
+		assert.Equal(t, "product", r)
+	}
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/eroshiva/cloudtalk/pkg/rabbitmq"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// MessagingRabbitMQ publishes events and broadcasts cache invalidations over RabbitMQ.
+	MessagingRabbitMQ = "rabbitmq"
+	// MessagingMemory keeps messaging within the process, i.e., events are dropped and invalidations are delivered
+	// to this replica only. It allows the service (and its tests) to run without RabbitMQ.
+	MessagingMemory = "memory"
+
+	// memoryInvalidationBuffer is a number of invalidations, which may be pending for each in-memory subscriber.
+	memoryInvalidationBuffer = 1024
+)
+
+var errMessagingClosed = errors.New("messaging is closed")
+
+// EventPublisher publishes events on changes of the resources to their consumers.
+type EventPublisher interface {
+	// PublishEvent publishes event with the routing key, main queue's routing key is used when empty.
+	PublishEvent(ctx context.Context, routingKey, text string) error
+}
+
+// InvalidationTransport broadcasts cache invalidations to all replicas.
+type InvalidationTransport interface {
+	// PublishInvalidation broadcasts serialized cache invalidation to all replicas.
+	PublishInvalidation(ctx context.Context, body []byte) error
+	// ConsumeInvalidations subscribes to invalidations broadcast by all replicas (including this one).
+	// Returned channel is closed once the transport is closed.
+	ConsumeInvalidations() (<-chan []byte, error)
+}
+
+// Messaging publishes events and transports cache invalidations. Server takes ownership of it and closes it once
+// the Server is stopped.
+type Messaging interface {
+	EventPublisher
+	InvalidationTransport
+	// Check reports an error, when messages can't be sent.
+	Check(ctx context.Context) error
+	// Close releases resources held by the messaging.
+	Close() error
+}
+
+// NewMessaging creates messaging with the backend selected by configuration. RabbitMQ must be configured beforehand.
+func NewMessaging(backend string) (Messaging, error) {
+	zlog.Info().Msgf("Using %s messaging backend", backend)
+	switch backend {
+	case MessagingRabbitMQ:
+		conn, ch, err := rabbitmq.Connect()
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		}
+		return NewRabbitMQMessaging(conn, ch), nil
+	case MessagingMemory:
+		return NewMemoryMessaging(), nil
+	default:
+		return nil, fmt.Errorf("unknown messaging backend %q, expected one of %q or %q", backend, MessagingRabbitMQ, MessagingMemory)
+	}
+}
+
+// rabbitMQMessaging publishes events and transports cache invalidations over RabbitMQ.
+type rabbitMQMessaging struct {
+	conn *amqp.Connection
+	ch   *amqp.Channel
+}
+
+// NewRabbitMQMessaging creates messaging over the RabbitMQ channel. Connection is optional, it is closed after
+// the channel.
+func NewRabbitMQMessaging(conn *amqp.Connection, ch *amqp.Channel) Messaging {
+	return &rabbitMQMessaging{conn: conn, ch: ch}
+}
+
+// PublishEvent publishes event to RabbitMQ.
+func (m *rabbitMQMessaging) PublishEvent(ctx context.Context, routingKey, text string) error {
+	if routingKey == "" {
+		return rabbitmq.PublishMessage(ctx, m.ch, text)
+	}
+	return rabbitmq.PublishMessageWithRoutingKey(ctx, m.ch, routingKey, text)
+}
+
+// PublishInvalidation broadcasts cache invalidation over the fanout exchange.
+func (m *rabbitMQMessaging) PublishInvalidation(ctx context.Context, body []byte) error {
+	return rabbitmq.PublishInvalidation(ctx, m.ch, body)
+}
+
+// ConsumeInvalidations subscribes to the fanout exchange with cache invalidations.
+func (m *rabbitMQMessaging) ConsumeInvalidations() (<-chan []byte, error) {
+	deliveries, err := rabbitmq.ConsumeInvalidations(m.ch)
+	if err != nil {
+		return nil, err
+	}
+	invalidations := make(chan []byte)
+	go func() {
+		defer close(invalidations)
+		for d := range deliveries {
+			invalidations <- d.Body
+		}
+	}()
+	return invalidations, nil
+}
+
+// Check reports an error, when the channel to RabbitMQ is closed.
+func (m *rabbitMQMessaging) Check(_ context.Context) error {
+	if m.ch == nil || m.ch.IsClosed() {
+		return fmt.Errorf("channel to RabbitMQ is closed")
+	}
+	return nil
+}
+
+// Close closes the channel and the connection to RabbitMQ. Closing the channel closes the subscription to cache
+// invalidations as well.
+func (m *rabbitMQMessaging) Close() error {
+	rabbitmq.CloseChannel(m.ch)
+	if m.conn != nil {
+		rabbitmq.CloseConnection(m.conn)
+	}
+	return nil
+}
+
+// memoryMessaging drops events and delivers cache invalidations to the subscribers within the process.
+type memoryMessaging struct {
+	mu          sync.Mutex
+	subscribers []chan []byte
+	closed      bool
+}
+
+// NewMemoryMessaging creates in-memory messaging, which suits a single replica without RabbitMQ (e.g., tests).
+func NewMemoryMessaging() Messaging {
+	return &memoryMessaging{}
+}
+
+// PublishEvent drops the event, there are no consumers outside the process.
+func (m *memoryMessaging) PublishEvent(ctx context.Context, routingKey, text string) error {
+	if err := m.Check(ctx); err != nil {
+		return err
+	}
+	zlog.Debug().Ctx(ctx).Msgf("Dropping event with routing key '%s', messaging is in-memory: '%s'", routingKey, text)
+	return nil
+}
+
+// PublishInvalidation delivers cache invalidation to all subscribers. Slow subscriber misses the invalidation,
+// the stale entry lives at most for its TTL then.
+func (m *memoryMessaging) PublishInvalidation(ctx context.Context, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return errMessagingClosed
+	}
+	for _, sub := range m.subscribers {
+		select {
+		case sub <- body:
+		default:
+			zlog.Warn().Ctx(ctx).Msg("Subscriber of cache invalidations is not keeping up, invalidation is dropped")
+		}
+	}
+	return nil
+}
+
+// ConsumeInvalidations subscribes to cache invalidations published within the process.
+func (m *memoryMessaging) ConsumeInvalidations() (<-chan []byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, errMessagingClosed
+	}
+	sub := make(chan []byte, memoryInvalidationBuffer)
+	m.subscribers = append(m.subscribers, sub)
+	return sub, nil
+}
+
+// Check reports an error, when the messaging is already closed.
+func (m *memoryMessaging) Check(_ context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return errMessagingClosed
+	}
+	return nil
+}
+
+// Close closes subscriptions to cache invalidations.
+func (m *memoryMessaging) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	for _, sub := range m.subscribers {
+		close(sub)
+	}
+	m.subscribers = nil
+	return nil
+}
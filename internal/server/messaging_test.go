@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryMessaging(t *testing.T) {
+	ctx := context.Background()
+	m, err := NewMessaging(MessagingMemory)
+	require.NoError(t, err)
+
+	first, err := m.ConsumeInvalidations()
+	require.NoError(t, err)
+	second, err := m.ConsumeInvalidations()
+	require.NoError(t, err)
+	require.NoError(t, m.Check(ctx))
+
+	// events are dropped, invalidations are delivered to all subscribers
+	require.NoError(t, m.PublishEvent(ctx, "", "review created"))
+	require.NoError(t, m.PublishInvalidation(ctx, []byte("invalidation")))
+	assert.Equal(t, []byte("invalidation"), <-first)
+	assert.Equal(t, []byte("invalidation"), <-second)
+
+	// subscriptions are closed together with the messaging
+	require.NoError(t, m.Close())
+	_, ok := <-first
+	assert.False(t, ok)
+	require.Error(t, m.Check(ctx))
+	require.Error(t, m.PublishInvalidation(ctx, []byte("invalidation")))
+	_, err = m.ConsumeInvalidations()
+	require.Error(t, err)
+	require.NoError(t, m.Close())
+}
+
+func TestUnknownMessagingBackend(t *testing.T) {
+	_, err := NewMessaging("kafka")
+	require.Error(t, err)
+
+	cfg := DefaultConfig()
+	cfg.MessagingBackend = "kafka"
+	require.ErrorContains(t, cfg.Validate(), "messaging_backend")
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// unknownRoute labels HTTP requests, which were not matched with any route pattern of the gateway.
+const unknownRoute = "unknown"
+
+// metrics holds Prometheus metrics of the gRPC server and the HTTP gateway.
+type metrics struct {
+	grpcHandled  *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+	httpHandled  *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+}
+
+// newMetrics creates metrics of the gRPC server and the HTTP gateway and registers them in the registry.
+func newMetrics(registry prometheus.Registerer) *metrics {
+	m := &metrics{
+		grpcHandled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Number of gRPC requests completed by the server by method and status code.",
+		}, []string{"grpc_method", "grpc_code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Latency of gRPC requests handled by the server by method and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"grpc_method", "grpc_code"}),
+		httpHandled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of HTTP requests completed by the gateway by method, route and status code.",
+		}, []string{"method", "route", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Latency of HTTP requests handled by the gateway by method, route and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "code"}),
+	}
+	registry.MustRegister(m.grpcHandled, m.grpcDuration, m.httpHandled, m.httpDuration)
+	return m
+}
+
+// observeGRPC records completed gRPC request.
+func (m *metrics) observeGRPC(method string, start time.Time, err error) {
+	code := status.Code(err).String()
+	m.grpcHandled.WithLabelValues(method, code).Inc()
+	m.grpcDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+}
+
+// unaryServerInterceptor measures unary gRPC requests.
+func (m *metrics) unaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observeGRPC(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// streamServerInterceptor measures streaming gRPC requests, latency covers the whole lifetime of the stream.
+func (m *metrics) streamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observeGRPC(info.FullMethod, start, err)
+		return err
+	}
+}
+
+// serverOptions returns gRPC server options, which enable collection of the metrics.
+func (m *metrics) serverOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(m.unaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(m.streamServerInterceptor()),
+	}
+}
+
+// gatewayMiddleware measures HTTP requests handled by the gateway.
+// Requests are labeled with the route pattern rather than the actual path to keep the number of series bounded.
+func (m *metrics) gatewayMiddleware(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r, pathParams)
+
+		route := unknownRoute
+		if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+			route = pattern.String()
+		}
+		code := strconv.Itoa(rec.status)
+		m.httpHandled.WithLabelValues(r.Method, route, code).Inc()
+		m.httpDuration.WithLabelValues(r.Method, route, code).Observe(time.Since(start).Seconds())
+	}
+}
+
+// statusRecorder remembers status code written to the HTTP response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records status code and writes it to the underlying response.
+func (rec *statusRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// Flush sends buffered data to the client, streamed responses rely on it.
+func (rec *statusRecorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying response writer, so http.ResponseController can reach it.
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCMetrics(t *testing.T) {
+	m := newMetrics(prometheus.NewRegistry())
+	interceptor := m.unaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.v1.ProductReviewsService/GetProductByID"}
+
+	_, err := interceptor(context.Background(), nil, info, func(_ context.Context, _ any) (any, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+	_, err = interceptor(context.Background(), nil, info, func(_ context.Context, _ any) (any, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+	require.Error(t, err)
+
+	assert.InDelta(t, 1, testutil.ToFloat64(m.grpcHandled.WithLabelValues(info.FullMethod, codes.OK.String())), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.grpcHandled.WithLabelValues(info.FullMethod, codes.NotFound.String())), 0)
+	assert.Equal(t, 2, testutil.CollectAndCount(m.grpcDuration))
+}
+
+func TestGatewayMetrics(t *testing.T) {
+	m := newMetrics(prometheus.NewRegistry())
+	mux := runtime.NewServeMux(runtime.WithMiddlewares(m.gatewayMiddleware))
+	require.NoError(t, mux.HandlePath(http.MethodGet, "/v1/products/{id}", func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	for _, id := range []string{"1", "2"} {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/products/"+id, nil))
+	}
+
+	// requests are labeled with the route pattern, not with the actual path
+	assert.InDelta(t, 2, testutil.ToFloat64(m.httpHandled.WithLabelValues(http.MethodGet, "/v1/products/{id=*}", "418")), 0)
+	assert.Equal(t, 1, testutil.CollectAndCount(m.httpDuration))
+}
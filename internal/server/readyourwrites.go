@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// readYourWritesHeader is a header (or gRPC metadata key) carrying read-your-writes token. The token is returned
+	// by each successful write and the client sends it back with the following requests.
+	readYourWritesHeader = "x-read-your-writes"
+	// defaultReadYourWritesWindow is a period, for which the client reads from the primary DB after its write.
+	defaultReadYourWritesWindow = 5 * time.Second
+)
+
+// readOnlyMethods are RPCs, which don't change any resource. Other RPCs are treated as writes.
+var readOnlyMethods = map[string]bool{
+	apiv1.ProductReviewsService_GetProductByID_FullMethodName:        true,
+	apiv1.ProductReviewsService_BatchGetProducts_FullMethodName:      true,
+	apiv1.ProductReviewsService_ListProducts_FullMethodName:          true,
+	apiv1.ProductReviewsService_GetReviewsByProductID_FullMethodName: true,
+}
+
+// readYourWritesToken returns token, which pins reads of the client to the primary DB until the deadline.
+func readYourWritesToken(deadline time.Time) string {
+	return strconv.FormatInt(deadline.UnixMilli(), 10)
+}
+
+// pinnedToPrimary reports whether the token pins reads to the primary DB at the time. Token, which pins reads for
+// longer than the window (i.e., it was not issued by the server), is ignored.
+func pinnedToPrimary(token string, now time.Time, window time.Duration) bool {
+	ms, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		return false
+	}
+	deadline := time.UnixMilli(ms)
+	return now.Before(deadline) && !deadline.After(now.Add(window))
+}
+
+// readYourWritesInterceptor serves requests of the client, which has recently written, from the primary DB,
+// so the client doesn't miss its own writes, which the read replica may not have applied yet. Each successful
+// write returns a new token, which pins the client to the primary DB for the read-your-writes window.
+func (srv *Server) readYourWritesInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	window := srv.cfg.ReadYourWritesWindow
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if tokens := md.Get(readYourWritesHeader); len(tokens) > 0 && pinnedToPrimary(tokens[0], time.Now(), window) {
+			ctx = db.WithPrimary(ctx)
+		}
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil || readOnlyMethods[info.FullMethod] {
+		return resp, err
+	}
+	token := readYourWritesToken(time.Now().Add(window))
+	if err := grpc.SetHeader(ctx, metadata.Pairs(readYourWritesHeader, token)); err != nil {
+		zlog.Warn().Ctx(ctx).Err(err).Msg("Failed to set read-your-writes token")
+	}
+	return resp, nil
+}
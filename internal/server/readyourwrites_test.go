@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// headerRecorder is a server transport stream, which records the header set by the interceptor.
+type headerRecorder struct {
+	header metadata.MD
+}
+
+func (r *headerRecorder) Method() string { return "" }
+
+func (r *headerRecorder) SetHeader(md metadata.MD) error {
+	r.header = metadata.Join(r.header, md)
+	return nil
+}
+
+func (r *headerRecorder) SendHeader(md metadata.MD) error { return r.SetHeader(md) }
+
+func (r *headerRecorder) SetTrailer(metadata.MD) error { return nil }
+
+func TestPinnedToPrimary(t *testing.T) {
+	now := time.Now()
+	window := 5 * time.Second
+	assert.True(t, pinnedToPrimary(readYourWritesToken(now.Add(time.Second)), now, window))
+	assert.True(t, pinnedToPrimary(readYourWritesToken(now.Add(window)), now, window))
+	// expired token
+	assert.False(t, pinnedToPrimary(readYourWritesToken(now.Add(-time.Second)), now, window))
+	// token pinning for longer than the window was not issued by the server
+	assert.False(t, pinnedToPrimary(readYourWritesToken(now.Add(time.Hour)), now, window))
+	assert.False(t, pinnedToPrimary("not a token", now, window))
+}
+
+func TestReadYourWritesInterceptor(t *testing.T) {
+	srv := &Server{cfg: DefaultConfig()}
+	var pinned bool
+	handler := func(ctx context.Context, _ any) (any, error) {
+		pinned = db.ReadsFromPrimary(ctx)
+		return nil, nil
+	}
+	write := &grpc.UnaryServerInfo{FullMethod: apiv1.ProductReviewsService_CreateReview_FullMethodName}
+	read := &grpc.UnaryServerInfo{FullMethod: apiv1.ProductReviewsService_GetProductByID_FullMethodName}
+
+	// write returns the token
+	rec := &headerRecorder{}
+	_, err := srv.readYourWritesInterceptor(grpc.NewContextWithServerTransportStream(context.Background(), rec), nil, write, handler)
+	require.NoError(t, err)
+	assert.False(t, pinned)
+	tokens := rec.header.Get(readYourWritesHeader)
+	require.Len(t, tokens, 1)
+
+	// the following read with the token is pinned to the primary DB, no new token is returned
+	rec = &headerRecorder{}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(readYourWritesHeader, tokens[0]))
+	_, err = srv.readYourWritesInterceptor(grpc.NewContextWithServerTransportStream(ctx, rec), nil, read, handler)
+	require.NoError(t, err)
+	assert.True(t, pinned)
+	assert.Empty(t, rec.header.Get(readYourWritesHeader))
+
+	// read without the token goes to the read replica
+	_, err = srv.readYourWritesInterceptor(context.Background(), nil, read, handler)
+	require.NoError(t, err)
+	assert.False(t, pinned)
+}
+
+func TestPinnedReadsSkipCache(t *testing.T) {
+	srv, store := newTestServer(t)
+	ctx := context.Background()
+
+	p, err := store.CreateProduct(ctx, "myAwesomeProduct", "Product description", "19.90")
+	require.NoError(t, err)
+	_, err = srv.GetProductByID(ctx, &apiv1.GetProductByIDRequest{Id: p.ID})
+	require.NoError(t, err)
+	_, err = srv.GetReviewsByProductID(ctx, &apiv1.GetReviewsByProductIDRequest{Id: p.ID})
+	require.NoError(t, err)
+
+	// cache entries are stale, e.g., they were loaded from the lagging read replica
+	_, err = store.CreateReview(ctx, "John", "Doe", "Product is good!", 5, p.ID)
+	require.NoError(t, err)
+	got, err := srv.GetProductByID(ctx, &apiv1.GetProductByIDRequest{Id: p.ID})
+	require.NoError(t, err)
+	assert.Empty(t, got.GetProduct().GetReviews())
+
+	// reads pinned to the primary DB see the change
+	pinned := db.WithPrimary(ctx)
+	got, err = srv.GetProductByID(pinned, &apiv1.GetProductByIDRequest{Id: p.ID})
+	require.NoError(t, err)
+	assert.Len(t, got.GetProduct().GetReviews(), 1)
+	reviews, err := srv.GetReviewsByProductID(pinned, &apiv1.GetReviewsByProductIDRequest{Id: p.ID})
+	require.NoError(t, err)
+	assert.Len(t, reviews.GetReviews(), 1)
+	batch, err := srv.BatchGetProducts(pinned, &apiv1.BatchGetProductsRequest{Ids: []string{p.ID}})
+	require.NoError(t, err)
+	assert.Len(t, batch.GetResults()[0].GetProduct().GetReviews(), 1)
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/eroshiva/cloudtalk/pkg/logger"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// maxRequestIDLength limits length of the request ID provided by the client.
+const maxRequestIDLength = 128
+
+// validRequestID reports whether request ID provided by the client is safe to be logged and propagated.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// requestIDFromMetadata returns valid request ID provided in the incoming metadata, or generates a new one.
+func requestIDFromMetadata(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(logger.RequestIDHeader); len(ids) > 0 && validRequestID(ids[0]) {
+			return ids[0]
+		}
+	}
+	return uuid.NewString()
+}
+
+// unaryRequestIDInterceptor assigns (or propagates) request ID, stores it in the context of the request,
+// so it appears in all log lines, and returns it to the client in the response header.
+func unaryRequestIDInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	id := requestIDFromMetadata(ctx)
+	ctx = logger.WithRequestID(ctx, id)
+	if err := grpc.SetHeader(ctx, metadata.Pairs(logger.RequestIDHeader, id)); err != nil {
+		zlog.Warn().Ctx(ctx).Err(err).Msg("Failed to set request ID in the response header")
+	}
+	return handler(ctx, req)
+}
+
+// streamRequestIDInterceptor assigns (or propagates) request ID of the streaming request.
+func streamRequestIDInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	id := requestIDFromMetadata(ss.Context())
+	ctx := logger.WithRequestID(ss.Context(), id)
+	if err := ss.SetHeader(metadata.Pairs(logger.RequestIDHeader, id)); err != nil {
+		zlog.Warn().Ctx(ctx).Err(err).Msg("Failed to set request ID in the response header")
+	}
+	return handler(srv, &requestIDStream{ServerStream: ss, ctx: ctx})
+}
+
+// requestIDStream overrides context of the server stream with the one carrying request ID.
+type requestIDStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns context of the stream carrying request ID.
+func (s *requestIDStream) Context() context.Context {
+	return s.ctx
+}
+
+// requestIDHandler assigns (or propagates) request ID of the HTTP request and returns it in the response header.
+// Request ID is forwarded to the gRPC server in the metadata (see incomingHeaderMatcher).
+func requestIDHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logger.RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+			r.Header.Set(logger.RequestIDHeader, id)
+		}
+		w.Header().Set(logger.RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logger.WithRequestID(r.Context(), id)))
+	})
+}
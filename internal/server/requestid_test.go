@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eroshiva/cloudtalk/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, validRequestID("0b7c6a2e-5f1d-4c39-9f0a-3c2d1e4b5a69"))
+	assert.False(t, validRequestID(""))
+	assert.False(t, validRequestID("injected\nline"))
+	assert.False(t, validRequestID(strings.Repeat("a", maxRequestIDLength+1)))
+}
+
+func TestUnaryRequestIDInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, _ any) (any, error) {
+		return logger.RequestIDFromContext(ctx), nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/test"}
+
+	// request ID provided by the client is propagated
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(logger.RequestIDHeader, "req-1"))
+	id, err := unaryRequestIDInterceptor(ctx, nil, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", id)
+
+	// request ID is generated, when it is missing
+	id, err = unaryRequestIDInterceptor(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+	assert.NotEmpty(t, id)
+}
+
+func TestRequestIDHandler(t *testing.T) {
+	var received string
+	h := requestIDHandler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(logger.RequestIDHeader)
+		assert.Equal(t, received, logger.RequestIDFromContext(r.Context()))
+	}))
+
+	// request ID provided by the client is propagated
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(logger.RequestIDHeader, "req-1")
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, "req-1", received)
+	assert.Equal(t, "req-1", rec.Header().Get(logger.RequestIDHeader))
+
+	// request ID is generated, when it is missing
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NotEmpty(t, received)
+	assert.Equal(t, received, rec.Header().Get(logger.RequestIDHeader))
+}
@@ -4,27 +4,43 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
-	"os"
-	"sync"
+	"sync/atomic"
+	"time"
 
 	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
-	"github.com/eroshiva/cloudtalk/internal/ent"
+	"github.com/eroshiva/cloudtalk/internal/cache"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
 	"github.com/eroshiva/cloudtalk/pkg/logger"
+	"github.com/eroshiva/cloudtalk/pkg/rabbitmq"
+	"github.com/eroshiva/cloudtalk/pkg/tlsconfig"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
-	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 const (
 	// server configuration-related constants
 	tcpNetwork               = "tcp"
 	defaultServerAddress     = "localhost:50051"
-	envServerAddress         = "GRPC_SERVER_ADDRESS" // must be in form address:port, e.g., localhost:50051.
-	envHTTPServerAddress     = "HTTP_SERVER_ADDRESS" // must be in form address:port, e.g., localhost:80.
 	defaultHTTPServerAddress = "localhost:50052"
+	defaultShutdownTimeout   = 10 * time.Second
+	// drainPollInterval is a period, in which the graceful shutdown checks whether in-flight RPCs are finished.
+	drainPollInterval = 10 * time.Millisecond
+
+	// admin server is not authenticated, thus it is reachable from the host only by default
+	defaultAdminServerAddress = "localhost:50053"
 )
 
 var zlog = logger.NewLogger("server")
@@ -33,166 +49,560 @@ var zlog = logger.NewLogger("server")
 type server struct {
 	apiv1.ProductReviewsServiceServer
 
-	// client for interactions with DB
-	dbClient *ent.Client
-	// client for pushing events to RabbitMQ
-	rabbitMQChannel *amqp.Channel
+	// stores of the resources, e.g., in the DB
+	products db.ProductStore
+	reviews  db.ReviewStore
+	// publisher of events on review changes
+	events EventPublisher
 	// cache for storing product reviews and average ratings
-	cache *Cache
+	cache cache.Cache
+	// broker for fanning out review changes to the watchers
+	broker *broker
+	// coalesces concurrent cache misses of the same resource into a single DB load
+	loads singleflight.Group
 }
 
 // Options structure defines server's features enablement.
 type Options struct {
-	EnableInterceptor bool
+	EnableInterceptor bool `yaml:"-"`
+	// EnableReflection registers gRPC server reflection, so clients (e.g., grpcurl) can discover the API.
+	EnableReflection bool `yaml:"enable_reflection" env:"ENABLE_GRPC_REFLECTION"`
+	// EnableOpenAPI serves OpenAPI document and Swagger UI at the HTTP gateway.
+	EnableOpenAPI bool `yaml:"enable_openapi" env:"ENABLE_OPENAPI"`
+	// TLS configures certificates of the gRPC server and the HTTP gateway. TLS is disabled, if no certificate is set.
+	TLS tlsconfig.Config `yaml:"tls"`
 }
 
-func getServerOptions(_ *Options) ([]grpc.ServerOption, error) {
-	// parse server options from configuration
-	optionsList := make([]grpc.ServerOption, 0)
-	return optionsList, nil
+// Config holds configuration of the Server together with its dependencies.
+// Server takes ownership of the dependencies and closes them once it is stopped.
+type Config struct {
+	// GRPCAddress is an address, where the gRPC server listens, unless the listener is provided with WithGRPCListener.
+	GRPCAddress string `yaml:"grpc_address" env:"GRPC_SERVER_ADDRESS"`
+	// HTTPAddress is an address, where the HTTP gateway listens, unless the listener is provided with WithHTTPListener.
+	HTTPAddress string `yaml:"http_address" env:"HTTP_SERVER_ADDRESS"`
+	// AdminAddress is an address, where administrative endpoints and metrics are served, unless the listener
+	// is provided with WithAdminListener. They are not authenticated, thus it must not be exposed publicly.
+	AdminAddress string `yaml:"admin_address" env:"ADMIN_SERVER_ADDRESS"`
+	// ShutdownTimeout bounds duration of the graceful shutdown, remaining requests are cancelled afterward.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// ReadYourWritesWindow applies, when the store reads from a read replica of the DB. For this period after a write,
+	// reads of the client, which sends back the returned X-Read-Your-Writes token, are served by the primary DB,
+	// and cache entries are evicted once more. It should be longer than the replication lag, zero disables it.
+	ReadYourWritesWindow time.Duration `yaml:"read_your_writes_window" env:"READ_YOUR_WRITES_WINDOW"`
+	// MessagingBackend selects messaging created with NewMessaging, i.e., MessagingRabbitMQ or MessagingMemory.
+	MessagingBackend string `yaml:"messaging_backend" env:"MESSAGING_BACKEND"`
+	Options          `yaml:",inline"`
+	Cache            cache.Config `yaml:"cache"`
+
+	// Store keeps Product and Review resources, e.g., db.NewEntStore or db.NewMemoryStore.
+	Store db.Store `yaml:"-"`
+	// Messaging publishes events and transports cache invalidations, e.g., NewRabbitMQMessaging or NewMemoryMessaging.
+	Messaging Messaging `yaml:"-"`
 }
 
-func serve(grpcAddress, httpAddress string, dbClient *ent.Client, rabbitMQ *amqp.Channel, wg *sync.WaitGroup,
-	serverOptions []grpc.ServerOption, termChan, readyChan, reverseProxyReadyChan, reverseProxyTermChan chan bool,
-) {
-	grpcReadyChan := make(chan bool, 1)
-	lis, err := net.Listen(tcpNetwork, grpcAddress)
-	if err != nil {
-		zlog.Fatal().Err(err).Msgf("Failed to listen on %s", grpcAddress)
+// DefaultConfig returns default configuration of the Server, all optional features are disabled.
+func DefaultConfig() Config {
+	return Config{
+		GRPCAddress:          defaultServerAddress,
+		HTTPAddress:          defaultHTTPServerAddress,
+		AdminAddress:         defaultAdminServerAddress,
+		ShutdownTimeout:      defaultShutdownTimeout,
+		ReadYourWritesWindow: defaultReadYourWritesWindow,
+		MessagingBackend:     MessagingRabbitMQ,
+		Options: Options{
+			TLS: tlsconfig.DefaultConfig(),
+		},
+		Cache: cache.DefaultConfig(),
 	}
+}
 
-	// Create a new gRPC server instance.
-	s := grpc.NewServer(serverOptions...)
+// Validate reports invalid values of the configuration. Dependencies are not validated.
+func (c Config) Validate() error {
+	var errs []error
+	for name, address := range map[string]string{
+		"grpc_address": c.GRPCAddress, "http_address": c.HTTPAddress, "admin_address": c.AdminAddress,
+	} {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			errs = append(errs, fmt.Errorf("%s must be in form host:port, got %q", name, address))
+		}
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("shutdown_timeout must be positive, got %s", c.ShutdownTimeout))
+	}
+	if c.ReadYourWritesWindow < 0 {
+		errs = append(errs, fmt.Errorf("read_your_writes_window must not be negative, got %s", c.ReadYourWritesWindow))
+	}
+	if c.MessagingBackend != MessagingRabbitMQ && c.MessagingBackend != MessagingMemory {
+		errs = append(errs, fmt.Errorf("messaging_backend must be one of %q or %q, got %q",
+			MessagingRabbitMQ, MessagingMemory, c.MessagingBackend))
+	}
+	if err := c.TLS.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("tls: %w", err))
+	}
+	if err := c.Cache.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("cache: %w", err))
+	}
+	return errors.Join(errs...)
+}
 
-	// creating cache
-	c, err := NewCache()
-	if err != nil {
-		zlog.Fatal().Err(err).Msg("Failed to create cache")
+// Option customizes the Server.
+type Option func(*Server)
+
+// WithGRPCListener makes the gRPC server to serve on the provided listener (e.g., bufconn) instead of GRPCAddress.
+func WithGRPCListener(lis net.Listener) Option {
+	return func(srv *Server) {
+		srv.grpcListener = lis
 	}
+}
 
-	gRPCServer := &server{
-		dbClient:        dbClient,
-		rabbitMQChannel: rabbitMQ,
-		cache:           c,
+// WithHTTPListener makes the HTTP gateway to serve on the provided listener instead of HTTPAddress.
+func WithHTTPListener(lis net.Listener) Option {
+	return func(srv *Server) {
+		srv.httpListener = lis
 	}
+}
 
-	// Register our server implementation with the gRPC server.
-	apiv1.RegisterProductReviewsServiceServer(s, gRPCServer)
+// WithAdminListener makes the admin server to serve on the provided listener instead of AdminAddress.
+func WithAdminListener(lis net.Listener) Option {
+	return func(srv *Server) {
+		srv.adminListener = lis
+	}
+}
+
+// WithGRPCDialer sets function, which the HTTP gateway uses to connect to the gRPC server,
+// e.g., DialContext of the bufconn listener provided with WithGRPCListener.
+func WithGRPCDialer(dial func(ctx context.Context, address string) (net.Conn, error)) Option {
+	return func(srv *Server) {
+		srv.grpcDialer = dial
+	}
+}
 
-	// Start the server.
-	zlog.Info().Msgf("gRPC server listening at %v", lis.Addr())
+// WithGRPCServerOptions appends options of the gRPC server.
+func WithGRPCServerOptions(opts ...grpc.ServerOption) Option {
+	return func(srv *Server) {
+		srv.grpcServerOptions = append(srv.grpcServerOptions, opts...)
+	}
+}
+
+// Server serves the gRPC API, the HTTP gateway and the admin server. It is created with New and served with Run.
+type Server struct {
+	cfg               Config
+	grpcListener      net.Listener
+	httpListener      net.Listener
+	adminListener     net.Listener
+	grpcDialer        func(ctx context.Context, address string) (net.Conn, error)
+	grpcServerOptions []grpc.ServerOption
+
+	api         *server
+	cache       *cache.BroadcastingCache
+	hc          *healthChecker
+	certs       *tlsconfig.Reloader
+	grpcServer  *grpc.Server
+	gatewayConn *grpc.ClientConn
+	gwServer    *http.Server
+	adminServer *http.Server
 
-	wg.Go(func() {
-		// On testing will be nil
-		if readyChan != nil {
-			readyChan <- true
-			grpcReadyChan <- true
+	// number of unary RPCs being served
+	inflight atomic.Int64
+	// closed once the subscription to cache invalidations is closed
+	invalidationsDone chan struct{}
+}
+
+// New creates the Server and binds its listeners, so clients may connect once it returns.
+// Run must be called afterward to serve requests and to release the resources held by the Server.
+func New(cfg Config, opts ...Option) (*Server, error) {
+	if cfg.Store == nil || cfg.Messaging == nil {
+		return nil, fmt.Errorf("store and messaging must be provided")
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		cfg.ShutdownTimeout = defaultShutdownTimeout
+	}
+	srv := &Server{cfg: cfg, invalidationsDone: make(chan struct{})}
+	for _, opt := range opts {
+		opt(srv)
+	}
+
+	if err := srv.listen(); err != nil {
+		return nil, err
+	}
+	if err := srv.build(); err != nil {
+		srv.closeListeners()
+		return nil, err
+	}
+	return srv, nil
+}
+
+// listen binds listeners, which were not provided by the options.
+func (srv *Server) listen() error {
+	var err error
+	if srv.grpcListener == nil {
+		srv.grpcListener, err = net.Listen(tcpNetwork, srv.cfg.GRPCAddress)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", srv.cfg.GRPCAddress, err)
 		}
-		if err := s.Serve(lis); err != nil {
-			zlog.Fatal().Err(err).Msgf("Failed to serve")
+	}
+	if srv.httpListener == nil {
+		srv.httpListener, err = net.Listen(tcpNetwork, srv.cfg.HTTPAddress)
+		if err != nil {
+			srv.closeListeners()
+			return fmt.Errorf("failed to listen on %s: %w", srv.cfg.HTTPAddress, err)
 		}
-	})
-
-	// starting reverse proxy
-	go startReverseProxy(grpcAddress, httpAddress, wg, grpcReadyChan, reverseProxyReadyChan, reverseProxyTermChan)
+	}
+	if srv.adminListener == nil {
+		srv.adminListener, err = net.Listen(tcpNetwork, srv.cfg.AdminAddress)
+		if err != nil {
+			srv.closeListeners()
+			return fmt.Errorf("failed to listen on %s: %w", srv.cfg.AdminAddress, err)
+		}
+	}
+	return nil
+}
 
-	// handle termination signals
-	<-termChan
-	zlog.Info().Msg("Gracefully stopping gRPC server")
-	s.Stop()
+// closeListeners closes listeners of the Server, which is not going to be served.
+func (srv *Server) closeListeners() {
+	for _, lis := range []net.Listener{srv.grpcListener, srv.httpListener, srv.adminListener} {
+		if lis != nil {
+			_ = lis.Close()
+		}
+	}
 }
 
-// startReverseProxy starts the gRPC reverse proxy server which is connected to the HTTP handler.
-func startReverseProxy(grpcServerAddress, httpServerAddress string, wg *sync.WaitGroup, grpcReadyChan, reverseProxyReadyChan, reverseProxyTermChan chan bool) {
-	// waiting for the gRPC server to start first
-	<-grpcReadyChan
-	zlog.Info().Msg("Starting reverse HTTP proxy")
+// build creates the gRPC server, the cache, the HTTP gateway and the admin server.
+func (srv *Server) build() error {
+	// registering metrics, gRPC requests are measured by the interceptors
+	registry := prometheus.NewRegistry()
+	m := newMetrics(registry)
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		db.NewCollector(srv.cfg.Store),
+		rabbitmq.NewCollector(),
+	)
 
-	// creating the gRPC-Gateway reverse proxy.
-	conn, err := grpc.NewClient(
-		grpcServerAddress, // The address of the gRPC server
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	serverOptions := srv.grpcServerOptions
+	if srv.cfg.TLS.Enabled() {
+		certs, err := tlsconfig.NewReloader(srv.cfg.TLS)
+		if err != nil {
+			return err
+		}
+		srv.certs = certs
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(certs.ServerConfig())))
+		zlog.Info().Msgf("TLS is enabled (mutual TLS: %t)", srv.cfg.TLS.MutualTLS())
+	}
+	// Create a new gRPC server instance, requests are traced and measured.
+	unaryInterceptors := []grpc.UnaryServerInterceptor{unaryRequestIDInterceptor, srv.inflightInterceptor}
+	var cacheOptions []cache.BroadcastingOption
+	if db.HasReadReplica(srv.cfg.Store) && srv.cfg.ReadYourWritesWindow > 0 {
+		zlog.Info().Msgf("Reading from the read replica, reads are pinned to the primary DB for %s after a write",
+			srv.cfg.ReadYourWritesWindow)
+		unaryInterceptors = append(unaryInterceptors, srv.readYourWritesInterceptor)
+		cacheOptions = append(cacheOptions, cache.WithRepeatedEvictions(srv.cfg.ReadYourWritesWindow))
+	}
+	serverOptions = append(serverOptions,
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamRequestIDInterceptor),
 	)
+	srv.grpcServer = grpc.NewServer(append(serverOptions, m.serverOptions()...)...)
+
+	// creating cache, backend is selected by configuration
+	localCache, err := cache.New(srv.cfg.Cache)
 	if err != nil {
-		zlog.Fatal().Err(err).Msg("Failed to dial to gRPC server")
+		return fmt.Errorf("failed to create cache: %w", err)
 	}
+	// evictions are broadcast to other replicas and evictions performed by other replicas are applied locally
+	srv.cache = cache.NewBroadcastingCache(localCache, srv.cfg.Messaging.PublishInvalidation, cacheOptions...)
+	if srv.cfg.Cache.WarmUpProducts > 0 {
+		warmUpCache(context.Background(), srv.cache, srv.cfg.Store, srv.cfg.Cache.WarmUpProducts)
+	}
+	registry.MustRegister(cache.NewCollector(srv.cache))
 
-	mux := runtime.NewServeMux()
+	srv.api = &server{
+		products: srv.cfg.Store,
+		reviews:  srv.cfg.Store,
+		events:   srv.cfg.Messaging,
+		cache:    srv.cache,
+		broker:   newBroker(defaultSubscriberBufferSize),
+	}
+
+	// Register our server implementation with the gRPC server.
+	apiv1.RegisterProductReviewsServiceServer(srv.grpcServer, srv.api)
+	// registering standard health service, its status reflects availability of the dependencies
+	srv.hc = newHealthChecker(srv.cfg.Store, srv.cfg.Messaging)
+	healthpb.RegisterHealthServer(srv.grpcServer, srv.hc.health)
+	if srv.cfg.EnableReflection {
+		zlog.Info().Msg("Enabling gRPC server reflection")
+		reflection.Register(srv.grpcServer)
+	}
+
+	if err = srv.buildGateway(m); err != nil {
+		return errors.Join(err, srv.cache.Close())
+	}
+	// administrative endpoints and metrics are kept off the HTTP gateway, since they are not authenticated
+	srv.adminServer = &http.Server{
+		Handler: newAdminHandler(srv.cache, registry),
+	}
+	if srv.certs != nil {
+		srv.adminServer.TLSConfig = srv.certs.ServerConfig()
+	}
+	return nil
+}
+
+// buildGateway creates the gRPC reverse proxy, i.e., the HTTP gateway connected to the gRPC server.
+func (srv *Server) buildGateway(m *metrics) error {
+	target := srv.cfg.GRPCAddress
+	if target == "" {
+		target = srv.grpcListener.Addr().String()
+	}
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(srv.gatewayTransportCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()), // trace context is propagated to the gRPC server
+	}
+	if srv.grpcDialer != nil {
+		// address is passed to the dialer as is, it doesn't have to be resolvable (e.g., bufconn)
+		target = "passthrough:///" + target
+		dialOptions = append(dialOptions, grpc.WithContextDialer(srv.grpcDialer))
+	}
+	conn, err := grpc.NewClient(target, dialOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to dial to gRPC server: %w", err)
+	}
+	srv.gatewayConn = conn
+
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+		runtime.WithMiddlewares(m.gatewayMiddleware, tracingMiddleware),
+	)
 
 	// Registering HTTP handler for our service and connecting the gateway to our gRPC server.
 	if err = apiv1.RegisterProductReviewsServiceHandler(context.Background(), mux, conn); err != nil {
-		zlog.Fatal().Err(err).Msg("Failed to register HTTP gateway")
+		return errors.Join(fmt.Errorf("failed to register HTTP gateway: %w", err), conn.Close())
+	}
+	// registering health endpoints
+	if err = registerHealthHandlers(mux, srv.hc); err != nil {
+		return errors.Join(fmt.Errorf("failed to register health endpoints: %w", err), conn.Close())
+	}
+	if srv.cfg.EnableOpenAPI {
+		zlog.Info().Msgf("Serving OpenAPI document at %s and Swagger UI at %s", OpenAPIPath, SwaggerUIPath)
+		if err = registerDocsHandlers(mux); err != nil {
+			return errors.Join(fmt.Errorf("failed to register documentation endpoints: %w", err), conn.Close())
+		}
 	}
 
-	// now, create and start the HTTP server (i.e., our gateway).
-	gwServer := &http.Server{
-		Addr:    httpServerAddress,
-		Handler: mux,
+	srv.gwServer = &http.Server{
+		Handler: otelhttp.NewHandler(requestIDHandler(mux), "gateway", otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method // renamed after the route pattern once the route is matched
+		})),
 	}
+	if srv.certs != nil {
+		srv.gwServer.TLSConfig = srv.certs.ServerConfig()
+	}
+	return nil
+}
 
-	zlog.Info().Msgf("HTTP reverse proxy gateway listening at %v", gwServer.Addr)
+// gatewayTransportCredentials returns credentials of the connection from the HTTP gateway to the gRPC server.
+func (srv *Server) gatewayTransportCredentials() credentials.TransportCredentials {
+	if srv.certs == nil {
+		return insecure.NewCredentials()
+	}
+	host, _, err := net.SplitHostPort(srv.cfg.GRPCAddress)
+	if err != nil || host == "" || host == "0.0.0.0" {
+		host = "localhost"
+	}
+	return credentials.NewTLS(srv.certs.ClientConfig(host))
+}
 
-	wg.Go(func() {
-		// On testing will be nil
-		if reverseProxyReadyChan != nil {
-			reverseProxyReadyChan <- true
-		}
-		// The ListenAndServe call now has its error checked specifically.
-		if err := gwServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			// Only log a fatal error if it's something other than the server being closed.
-			zlog.Fatal().Err(err).Msg("Failed to serve HTTP gateway")
-		} else {
-			// This will now be logged on a clean shutdown.
-			zlog.Debug().Msg("HTTP reverse proxy is stopped")
-		}
-	})
+// ReloadCertificates reloads TLS certificates from disk right away, without waiting for the periodic check.
+func (srv *Server) ReloadCertificates() error {
+	if srv.certs == nil {
+		return nil
+	}
+	return srv.certs.Reload()
+}
+
+// GRPCAddr returns address of the gRPC server listener.
+func (srv *Server) GRPCAddr() net.Addr {
+	return srv.grpcListener.Addr()
+}
+
+// HTTPAddr returns address of the HTTP gateway listener.
+func (srv *Server) HTTPAddr() net.Addr {
+	return srv.httpListener.Addr()
+}
 
-	// handle termination signals
-	<-reverseProxyTermChan
+// AdminAddr returns address of the admin server listener.
+func (srv *Server) AdminAddr() net.Addr {
+	return srv.adminListener.Addr()
+}
 
-	zlog.Info().Msg("Gracefully stopping HTTP server")
-	err = gwServer.Shutdown(context.Background())
+// Run serves the gRPC API, the HTTP gateway and the admin server until the context is cancelled or any of the servers fails.
+// Afterward, the Server is gracefully stopped and its dependencies are closed. Run may be called only once.
+func (srv *Server) Run(ctx context.Context) error {
+	invalidations, err := srv.cfg.Messaging.ConsumeInvalidations()
 	if err != nil {
-		zlog.Fatal().Err(err).Msg("Failed to gracefully shutdown HTTP gateway")
+		close(srv.invalidationsDone)
+		srv.closeListeners()
+		return errors.Join(fmt.Errorf("failed to subscribe to cache invalidations: %w", err), srv.closeDependencies(context.Background()))
+	}
+	go func() {
+		defer close(srv.invalidationsDone)
+		applyInvalidations(srv.cache, invalidations)
+	}()
+	go srv.hc.run()
+	// certificates are reloaded once they are replaced on disk
+	certsStop := make(chan struct{})
+	if srv.certs != nil {
+		go srv.certs.Run(certsStop)
 	}
+
+	// expired idempotency keys are purged in the background, so they don't slow down the creations
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	purgeDone := make(chan struct{})
+	go func() {
+		defer close(purgeDone)
+		db.PurgeIdempotencyKeysPeriodically(purgeCtx, srv.cfg.Store)
+	}()
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		zlog.Info().Msgf("gRPC server listening at %v", srv.grpcListener.Addr())
+		if err := srv.grpcServer.Serve(srv.grpcListener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			return fmt.Errorf("failed to serve gRPC API: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		zlog.Info().Msgf("HTTP reverse proxy gateway listening at %v", srv.httpListener.Addr())
+		if err := serveHTTP(srv.gwServer, srv.httpListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to serve HTTP gateway: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		zlog.Info().Msgf("Admin server listening at %v", srv.adminListener.Addr())
+		if err := serveHTTP(srv.adminServer, srv.adminListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to serve admin server: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		<-gctx.Done()
+		close(certsStop)
+		stopPurge()
+		<-purgeDone
+		return srv.shutdown()
+	})
+	return g.Wait()
 }
 
-// GetGRPCServerAddress function reads environmental variable and returns a gRPC server address.
-func GetGRPCServerAddress() string {
-	// read env variable, where gRPC server is running
-	serverAddress := os.Getenv(envServerAddress)
-	if serverAddress == "" {
-		zlog.Warn().Msgf("Environment variable \"%s\" is not set, using default gRPC server address: %s",
-			envServerAddress, defaultServerAddress)
-		serverAddress = defaultServerAddress
+// serveHTTP serves HTTP server over TLS, if it is configured, and over plain HTTP otherwise.
+func serveHTTP(s *http.Server, lis net.Listener) error {
+	if s.TLSConfig != nil {
+		// certificates are provided by the TLS configuration
+		return s.ServeTLS(lis, "", "")
 	}
-	return serverAddress
+	return s.Serve(lis)
 }
 
-// GetHTTPServerAddress function reads environmental variable and returns an HTTP server address.
-func GetHTTPServerAddress() string {
-	// read env variable, where HTTP server is running
-	httpServerAddress := os.Getenv(envHTTPServerAddress)
-	if httpServerAddress == "" {
-		zlog.Warn().Msgf("Environment variable \"%s\" is not set, using default address: %s",
-			envHTTPServerAddress, defaultHTTPServerAddress)
-		httpServerAddress = defaultHTTPServerAddress
+// inflightInterceptor counts unary RPCs being served, so the graceful shutdown waits for them before closing watch streams.
+func (srv *Server) inflightInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	srv.inflight.Add(1)
+	defer srv.inflight.Add(-1)
+	return handler(ctx, req)
+}
+
+// shutdown gracefully stops the Server within the shutdown timeout. New requests are refused first,
+// in-flight RPCs are finished, pending events are delivered to the watchers and the dependencies are closed at last.
+func (srv *Server) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), srv.cfg.ShutdownTimeout)
+	defer cancel()
+	zlog.Info().Msgf("Gracefully stopping servers (timeout %s)", srv.cfg.ShutdownTimeout)
+
+	// readiness is reported as not serving, so clients stop sending new requests
+	srv.hc.shutdown()
+	// all servers stop accepting new requests, in-flight requests are finished
+	httpStopped := make(chan error, 1)
+	go func() {
+		httpStopped <- srv.gwServer.Shutdown(ctx)
+	}()
+	adminStopped := make(chan error, 1)
+	go func() {
+		adminStopped <- srv.adminServer.Shutdown(ctx)
+	}()
+	grpcStopped := make(chan struct{})
+	go func() {
+		srv.grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	srv.waitForRPCs(ctx)
+	// watchers receive events of the finished RPCs, which are still pending, and their streams are closed
+	srv.api.broker.Close()
+
+	var errs []error
+	if err := <-httpStopped; err != nil {
+		zlog.Warn().Err(err).Msg("HTTP gateway didn't stop in time, closing remaining connections")
+		errs = append(errs, fmt.Errorf("failed to gracefully stop HTTP gateway: %w", err), srv.gwServer.Close())
+	}
+	if err := <-adminStopped; err != nil {
+		zlog.Warn().Err(err).Msg("Admin server didn't stop in time, closing remaining connections")
+		errs = append(errs, fmt.Errorf("failed to gracefully stop admin server: %w", err), srv.adminServer.Close())
 	}
-	return httpServerAddress
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		zlog.Warn().Msg("gRPC server didn't stop in time, cancelling remaining RPCs")
+		srv.grpcServer.Stop()
+		<-grpcStopped
+		errs = append(errs, fmt.Errorf("failed to gracefully stop gRPC server: %w", ctx.Err()))
+	}
+
+	errs = append(errs, srv.closeDependencies(ctx))
+	zlog.Info().Msg("Servers are stopped")
+	return errors.Join(errs...)
 }
 
-// StartServer function configures and brings up gRPC server.
-func StartServer(gRPCServerAddress, httpServerAddress string, dbClient *ent.Client, rabbitMQ *amqp.Channel,
-	wg *sync.WaitGroup, termChan, readyChan, reverseProxyReadyChan, reverseProxyTermChan chan bool,
-) {
-	zlog.Info().Msgf("Starting gRPC server...")
+// waitForRPCs waits until all in-flight unary RPCs are finished or the context is done.
+func (srv *Server) waitForRPCs(ctx context.Context) {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for srv.inflight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
 
-	// get server options
-	serverOptions, err := getServerOptions(nil)
-	if err != nil {
-		zlog.Fatal().Err(err).Msg("Failed to get server options")
+// closeDependencies closes the cache, the connection of the HTTP gateway, the messaging and the DB, in this order.
+func (srv *Server) closeDependencies(ctx context.Context) error {
+	var errs []error
+	if err := srv.cache.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close cache: %w", err))
+	}
+	if err := srv.gatewayConn.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close connection of HTTP gateway: %w", err))
+	}
+	// closing the messaging closes the subscription to cache invalidations as well
+	if err := srv.cfg.Messaging.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close messaging: %w", err))
+	}
+	select {
+	case <-srv.invalidationsDone:
+	case <-ctx.Done():
 	}
+	if err := srv.cfg.Store.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close store: %w", err))
+	}
+	return errors.Join(errs...)
+}
 
-	// start server
-	go serve(gRPCServerAddress, httpServerAddress, dbClient, rabbitMQ, wg, serverOptions, termChan, readyChan, reverseProxyReadyChan, reverseProxyTermChan)
+// applyInvalidations evicts entries invalidated by other replicas until the subscription is closed.
+func applyInvalidations(c *cache.BroadcastingCache, invalidations <-chan []byte) {
+	for body := range invalidations {
+		// malformed invalidation is logged by the cache, there is nothing else to do about it
+		_ = c.Apply(context.Background(), body)
+	}
+	zlog.Debug().Msg("Subscription to cache invalidations is closed")
 }
@@ -3,16 +3,29 @@ package server_test
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net"
+	"net/http"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
 	"github.com/eroshiva/cloudtalk/internal/ent"
 	"github.com/eroshiva/cloudtalk/internal/server"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
 	prs_testing "github.com/eroshiva/cloudtalk/pkg/testing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -36,27 +49,49 @@ const (
 	reviewer1Rating   = 5
 	reviewer2Rating   = 4
 	reviewer3Rating   = 2
+
+	// gRPC server, HTTP gateway and admin server addresses used by prs_testing.SetupFull by default
+	grpcServerAddress = "localhost:50051"
+	httpServerURL     = "http://localhost:50052"
+	adminServerURL    = "http://localhost:50053"
 )
 
 var (
 	client     *ent.Client
 	grpcClient apiv1.ProductReviewsServiceClient
+	messaging  server.Messaging
 )
 
 func TestMain(m *testing.M) {
 	var err error
-	entClient, rabbitMQConn, rabbitMQCh, serverClient, wg, termChan, reverseProxyTermChan, err := prs_testing.SetupFull("", "")
+	// enabling optional features, so they can be tested as well
+	_ = os.Setenv("ENABLE_GRPC_REFLECTION", "true")
+	_ = os.Setenv("ENABLE_OPENAPI", "true")
+	sqliteDir := ""
+	if os.Getenv("DB_DRIVER") == db.DriverSQLite && os.Getenv("DB_SQLITE_FILE") == "" {
+		// embedded server shares the DB with the started one, while in-memory DB is private to its connection pool
+		sqliteDir, err = os.MkdirTemp("", "server-test")
+		if err != nil {
+			panic(err)
+		}
+		_ = os.Setenv("DB_SQLITE_FILE", filepath.Join(sqliteDir, "server-test.db"))
+	}
+	env, err := prs_testing.SetupFull("", "")
 	if err != nil {
 		panic(err)
 	}
-	client = entClient
-	grpcClient = serverClient
+	client = env.DBClient
+	grpcClient = env.Client
+	messaging = env.Messaging
 
 	// running tests
 	code := m.Run()
 
 	// all tests were run, stopping servers gracefully
-	prs_testing.TeardownFull(client, rabbitMQConn, rabbitMQCh, wg, termChan, reverseProxyTermChan)
+	prs_testing.TeardownFull(env)
+	if sqliteDir != "" {
+		_ = os.RemoveAll(sqliteDir)
+	}
 	os.Exit(code)
 }
 
@@ -233,6 +268,20 @@ func TestListProducts(t *testing.T) {
 	// there is no reliable way to assert precise number of products in the system, because other tests concurrently add/remove other product resources.
 	// at least two products added inside this test should be in the system in any case.
 	assert.GreaterOrEqual(t, len(products.Products), 2)
+
+	// list is cached now, editing product must invalidate it
+	_, err = grpcClient.EditProduct(ctx, server.EditProductRequest(res1.GetProduct().GetId(), "", productDescription2, ""))
+	require.NoError(t, err)
+	products, err = grpcClient.ListProducts(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	found := false
+	for _, p := range products.GetProducts() {
+		if p.GetId() == res1.GetProduct().GetId() {
+			found = true
+			assert.Equal(t, productDescription2, p.GetDescription())
+		}
+	}
+	assert.True(t, found)
 }
 
 func TestCreateReview(t *testing.T) {
@@ -502,3 +551,453 @@ func TestAverageRatingComputations(t *testing.T) {
 	require.NotNil(t, product)
 	t.Logf("Average rating is %.2f\n", product.GetProduct().GetAverageRating())
 }
+
+func TestEmitSnapshot(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), prs_testing.DefaultTestTimeout)
+	t.Cleanup(cancel)
+
+	// creating product
+	res, err := grpcClient.CreateProduct(ctx, server.CreateProductRequest(productName1, productDescription1, productPrice1))
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	t.Cleanup(func() {
+		// cleaning up product resource at the end of the test
+		_, err = grpcClient.DeleteProduct(ctx, server.DeleteProductRequest(res.GetProduct().GetId()))
+		assert.NoError(t, err)
+	})
+
+	// adding two reviews
+	reviewIDs := make([]string, 0, 2)
+	for _, req := range []*apiv1.CreateReviewRequest{
+		server.CreateReviewRequest(reviewer1Name, reviewer1LastName, reviewer1Text, reviewer1Rating, res.GetProduct().GetId()),
+		server.CreateReviewRequest(reviewer2Name, reviewer2LastName, reviewer2Text, reviewer2Rating, res.GetProduct().GetId()),
+	} {
+		rev, err := grpcClient.CreateReview(ctx, req)
+		require.NoError(t, err)
+		reviewIDs = append(reviewIDs, rev.GetReview().GetId())
+		t.Cleanup(func() {
+			// cleaning up review resource at the end of the test
+			_, err = grpcClient.DeleteReview(ctx, server.DeleteReviewRequest(rev.GetReview().GetId()))
+			assert.NoError(t, err)
+		})
+	}
+
+	// re-emitting state of the product - one event for the product and one per each review
+	n, err := server.EmitSnapshot(ctx, db.NewEntStore(client), messaging, server.SnapshotOptions{
+		ProductIDs: []string{res.GetProduct().GetId()},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	// re-emitting selected review only
+	n, err = server.EmitSnapshot(ctx, db.NewEntStore(client), messaging, server.SnapshotOptions{
+		ReviewIDs: reviewIDs[:1],
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	// throttling rate may exceed one event per nanosecond
+	n, err = server.EmitSnapshot(ctx, db.NewEntStore(client), messaging, server.SnapshotOptions{
+		ReviewIDs: reviewIDs,
+		Rate:      math.MaxInt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// unknown product or review can't be re-emitted
+	_, err = server.EmitSnapshot(ctx, db.NewEntStore(client), messaging, server.SnapshotOptions{
+		ProductIDs: []string{"product-unknown"},
+	})
+	assert.Error(t, err)
+	_, err = server.EmitSnapshot(ctx, db.NewEntStore(client), messaging, server.SnapshotOptions{
+		ReviewIDs: []string{"review-unknown"},
+	})
+	assert.Error(t, err)
+}
+
+func TestWatchProductReviews(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), prs_testing.DefaultTestTimeout)
+	t.Cleanup(cancel)
+
+	// creating product
+	res, err := grpcClient.CreateProduct(ctx, server.CreateProductRequest(productName1, productDescription1, productPrice1))
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	t.Cleanup(func() {
+		// cleaning up product resource at the end of the test
+		_, err = grpcClient.DeleteProduct(ctx, server.DeleteProductRequest(res.GetProduct().GetId()))
+		assert.NoError(t, err)
+	})
+
+	// subscribing to the changes of product's reviews
+	watchCtx, watchCancel := context.WithCancel(ctx)
+	t.Cleanup(watchCancel)
+	stream, err := grpcClient.WatchProductReviews(watchCtx, server.WatchProductReviewsRequest(res.GetProduct().GetId()))
+	require.NoError(t, err)
+	// waiting for the headers - server sends them once the subscription is established
+	_, err = stream.Header()
+	require.NoError(t, err)
+
+	// creating review
+	rev, err := grpcClient.CreateReview(ctx, server.CreateReviewRequest(reviewer1Name, reviewer1LastName,
+		reviewer1Text, reviewer1Rating, res.GetProduct().GetId()))
+	require.NoError(t, err)
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, apiv1.ReviewAction_REVIEW_ACTION_CREATED, event.GetAction())
+	assert.Equal(t, rev.GetReview().GetId(), event.GetReview().GetId())
+	assert.Equal(t, float64(reviewer1Rating), event.GetAverageRating())
+
+	// editing review
+	_, err = grpcClient.EditReview(ctx, server.EditReviewRequest(rev.GetReview().GetId(), "", "", reviewer2Text, reviewer2Rating))
+	require.NoError(t, err)
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, apiv1.ReviewAction_REVIEW_ACTION_MODIFIED, event.GetAction())
+	assert.Equal(t, float64(reviewer2Rating), event.GetAverageRating())
+
+	// deleting review
+	_, err = grpcClient.DeleteReview(ctx, server.DeleteReviewRequest(rev.GetReview().GetId()))
+	require.NoError(t, err)
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, apiv1.ReviewAction_REVIEW_ACTION_DELETED, event.GetAction())
+	assert.Equal(t, float64(0), event.GetAverageRating())
+}
+
+func TestIdempotentCreateReview(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), prs_testing.DefaultTestTimeout)
+	t.Cleanup(cancel)
+
+	// creating product with the idempotency key set in the request
+	req := server.CreateProductRequest(productName1, productDescription1, productPrice1)
+	req.IdempotencyKey = "idempotent-product"
+	res, err := grpcClient.CreateProduct(ctx, req)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		// cleaning up product resource at the end of the test
+		_, err = grpcClient.DeleteProduct(ctx, server.DeleteProductRequest(res.GetProduct().GetId()))
+		assert.NoError(t, err)
+	})
+	retried, err := grpcClient.CreateProduct(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, res.GetProduct().GetId(), retried.GetProduct().GetId())
+
+	// creating review with the idempotency key set in the header (gRPC metadata)
+	mdCtx := metadata.AppendToOutgoingContext(ctx, "idempotency-key", "idempotent-review")
+	revReq := server.CreateReviewRequest(reviewer1Name, reviewer1LastName, reviewer1Text, reviewer1Rating, res.GetProduct().GetId())
+	rev, err := grpcClient.CreateReview(mdCtx, revReq)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		// cleaning up review resource at the end of the test
+		_, err = grpcClient.DeleteReview(ctx, server.DeleteReviewRequest(rev.GetReview().GetId()))
+		assert.NoError(t, err)
+	})
+	retriedRev, err := grpcClient.CreateReview(mdCtx, revReq)
+	require.NoError(t, err)
+	assert.Equal(t, rev.GetReview().GetId(), retriedRev.GetReview().GetId())
+
+	// only one review is created
+	reviews, err := grpcClient.GetReviewsByProductID(ctx, server.GetReviewsByProductIDRequest(res.GetProduct().GetId()))
+	require.NoError(t, err)
+	assert.Len(t, reviews.GetReviews(), 1)
+}
+
+func TestBatchRPCs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), prs_testing.DefaultTestTimeout)
+	t.Cleanup(cancel)
+
+	// creating product
+	res, err := grpcClient.CreateProduct(ctx, server.CreateProductRequest(productName1, productDescription1, productPrice1))
+	require.NoError(t, err)
+	productID := res.GetProduct().GetId()
+	t.Cleanup(func() {
+		// cleaning up product resource at the end of the test
+		_, err = grpcClient.DeleteProduct(ctx, server.DeleteProductRequest(productID))
+		assert.NoError(t, err)
+	})
+
+	// creating reviews in a batch, the last one refers to non-existing product
+	created, err := grpcClient.BatchCreateReviews(ctx, server.BatchCreateReviewsRequest(
+		server.CreateReviewRequest(reviewer1Name, reviewer1LastName, reviewer1Text, reviewer1Rating, productID).GetReview(),
+		server.CreateReviewRequest(reviewer2Name, reviewer2LastName, reviewer2Text, reviewer2Rating, productID).GetReview(),
+		server.CreateReviewRequest(reviewer3Name, reviewer3LastName, reviewer3Text, reviewer3Rating, "non-existing").GetReview(),
+	))
+	require.NoError(t, err)
+	require.Len(t, created.GetResults(), 3)
+	assert.Empty(t, created.GetResults()[0].GetError())
+	assert.Empty(t, created.GetResults()[1].GetError())
+	assert.NotEmpty(t, created.GetResults()[2].GetError())
+
+	// retrieving products in a batch, average rating reflects the whole batch
+	got, err := grpcClient.BatchGetProducts(ctx, server.BatchGetProductsRequest(productID, "non-existing"))
+	require.NoError(t, err)
+	require.Len(t, got.GetResults(), 2)
+	assert.InDelta(t, float64(reviewer1Rating+reviewer2Rating)/2, got.GetResults()[0].GetProduct().GetAverageRating(), 0.001)
+	assert.NotEmpty(t, got.GetResults()[1].GetError())
+
+	// removing reviews in a batch
+	deleted, err := grpcClient.BatchDeleteReviews(ctx, server.BatchDeleteReviewsRequest(
+		created.GetResults()[0].GetReview().GetId(), created.GetResults()[1].GetReview().GetId()))
+	require.NoError(t, err)
+	require.Len(t, deleted.GetResults(), 2)
+	assert.Empty(t, deleted.GetResults()[0].GetError())
+	assert.Empty(t, deleted.GetResults()[1].GetError())
+
+	// empty batch is refused
+	_, err = grpcClient.BatchDeleteReviews(ctx, server.BatchDeleteReviewsRequest())
+	assert.Error(t, err)
+}
+
+func TestGetNonExistingProduct(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), prs_testing.DefaultTestTimeout)
+	t.Cleanup(cancel)
+
+	// first lookup goes to the DB, second one is answered by the remembered "not found" result
+	for range 2 {
+		res, err := grpcClient.GetProductByID(ctx, server.GetProductByIDRequest("non-existing"))
+		require.Error(t, err)
+		assert.Nil(t, res)
+	}
+}
+
+func TestCacheAdminEndpoints(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), prs_testing.DefaultTestTimeout)
+	t.Cleanup(cancel)
+
+	// creating product and looking it up twice, so there is at least one miss and one hit
+	res, err := grpcClient.CreateProduct(ctx, server.CreateProductRequest(productName1, productDescription1, productPrice1))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		// cleaning up product resource at the end of the test
+		_, err = grpcClient.DeleteProduct(ctx, server.DeleteProductRequest(res.GetProduct().GetId()))
+		assert.NoError(t, err)
+	})
+	for range 2 {
+		_, err = grpcClient.GetProductByID(ctx, server.GetProductByIDRequest(res.GetProduct().GetId()))
+		require.NoError(t, err)
+	}
+
+	// retrieving statistics
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, adminServerURL+server.AdminCacheStatsPath, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck // this code doesn't go production
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	stats := struct {
+		Hits   int64 `json:"hits"`
+		Misses int64 `json:"misses"`
+	}{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+	assert.Positive(t, stats.Hits)
+	assert.Positive(t, stats.Misses)
+
+	// statistics are exposed as metrics as well
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, adminServerURL+server.MetricsPath, nil)
+	require.NoError(t, err)
+	metricsResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer metricsResp.Body.Close() //nolint:errcheck // this code doesn't go production
+	metrics, err := io.ReadAll(metricsResp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(metrics), "cache_hits_total")
+	assert.Contains(t, string(metrics), "grpc_server_handled_total")
+	assert.Contains(t, string(metrics), "products_count")
+
+	// flushing cache
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, adminServerURL+server.AdminCacheFlushPath, nil)
+	require.NoError(t, err)
+	flushResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer flushResp.Body.Close() //nolint:errcheck // this code doesn't go production
+	assert.Equal(t, http.StatusNoContent, flushResp.StatusCode)
+
+	// administrative endpoints are not reachable at the HTTP gateway
+	for path, method := range map[string]string{
+		server.MetricsPath:         http.MethodGet,
+		server.AdminCacheStatsPath: http.MethodGet,
+		server.AdminCacheFlushPath: http.MethodPost,
+	} {
+		req, err = http.NewRequestWithContext(ctx, method, httpServerURL+path, nil)
+		require.NoError(t, err)
+		publicResp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, publicResp.StatusCode, path)
+		assert.NoError(t, publicResp.Body.Close())
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), prs_testing.DefaultTestTimeout)
+	t.Cleanup(cancel)
+
+	// request ID provided by the gRPC client is returned in the response header
+	var header metadata.MD
+	_, err := grpcClient.ListProducts(metadata.AppendToOutgoingContext(ctx, "x-request-id", "grpc-request-1"),
+		&emptypb.Empty{}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"grpc-request-1"}, header.Get("x-request-id"))
+
+	// request ID provided by the HTTP client is returned in the response header exactly once
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServerURL+"/v1/product/all", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Request-Id", "http-request-1")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck // this code doesn't go production
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"http-request-1"}, resp.Header.Values("X-Request-Id"))
+
+	// request ID is generated, when it is not provided
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, httpServerURL+"/v1/product/all", nil)
+	require.NoError(t, err)
+	generatedResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer generatedResp.Body.Close() //nolint:errcheck // this code doesn't go production
+	assert.NotEmpty(t, generatedResp.Header.Get("X-Request-Id"))
+}
+
+func TestHealth(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), prs_testing.DefaultTestTimeout)
+	t.Cleanup(cancel)
+
+	// standard gRPC health service reports that the service is serving
+	conn, err := grpc.NewClient(grpcServerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, conn.Close())
+	})
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	// liveness and readiness endpoints of the gateway
+	for _, path := range []string{server.LivenessPath, server.ReadinessPath} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServerURL+path, nil)
+		require.NoError(t, err)
+		httpResp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, httpResp.StatusCode, path)
+		assert.NoError(t, httpResp.Body.Close())
+	}
+}
+
+func TestAPIDiscovery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), prs_testing.DefaultTestTimeout)
+	t.Cleanup(cancel)
+
+	// gRPC server reflection lists the service
+	conn, err := grpc.NewClient(grpcServerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, conn.Close())
+	})
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	services := make([]string, 0)
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		services = append(services, s.GetName())
+	}
+	assert.Contains(t, services, apiv1.ProductReviewsService_ServiceDesc.ServiceName)
+	require.NoError(t, stream.CloseSend())
+
+	// OpenAPI document and Swagger UI are served by the gateway
+	for _, path := range []string{server.OpenAPIPath, server.SwaggerUIPath} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServerURL+path, nil)
+		require.NoError(t, err)
+		httpResp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, httpResp.StatusCode, path)
+		assert.NoError(t, httpResp.Body.Close())
+	}
+}
+
+func TestEmbeddedServer(t *testing.T) {
+	// embedded server owns its own dependencies and closes them once it is stopped
+	dbClient, err := prs_testing.Setup()
+	require.NoError(t, err)
+	embeddedMessaging, err := prs_testing.NewMessaging()
+	require.NoError(t, err)
+
+	grpcListener := bufconn.Listen(1024 * 1024)
+	httpListener := bufconn.Listen(1024 * 1024)
+	adminListener := bufconn.Listen(1024 * 1024)
+	dialGRPC := func(ctx context.Context, _ string) (net.Conn, error) {
+		return grpcListener.DialContext(ctx)
+	}
+	cfg := server.DefaultConfig()
+	cfg.ShutdownTimeout = 5 * time.Second
+	cfg.Store = db.NewEntStore(dbClient)
+	cfg.Messaging = embeddedMessaging
+	srv, err := server.New(cfg, server.WithGRPCListener(grpcListener), server.WithHTTPListener(httpListener),
+		server.WithAdminListener(adminListener), server.WithGRPCDialer(dialGRPC))
+	require.NoError(t, err)
+	runCtx, stop := context.WithCancel(context.Background())
+	t.Cleanup(stop)
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Run(runCtx)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn", grpc.WithContextDialer(dialGRPC),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	embeddedClient := apiv1.NewProductReviewsServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*prs_testing.DefaultTestTimeout)
+	t.Cleanup(cancel)
+	res, err := embeddedClient.CreateProduct(ctx, server.CreateProductRequest(productName1, productDescription1, productPrice1))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		// cleaning up product resource through the shared server, embedded one is stopped already
+		_, err = grpcClient.DeleteProduct(ctx, server.DeleteProductRequest(res.GetProduct().GetId()))
+		assert.NoError(t, err)
+	})
+
+	// HTTP gateway is served over the injected listener as well
+	httpClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return httpListener.DialContext(ctx)
+		},
+	}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://bufconn/v1/product/get/"+res.GetProduct().GetId(), nil)
+	require.NoError(t, err)
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// watcher receives events of the RPCs finished before the shutdown, then its stream is closed
+	stream, err := embeddedClient.WatchProductReviews(ctx, server.WatchProductReviewsRequest(res.GetProduct().GetId()))
+	require.NoError(t, err)
+	_, err = stream.Header()
+	require.NoError(t, err)
+	rev, err := embeddedClient.CreateReview(ctx, server.CreateReviewRequest(reviewer1Name, reviewer1LastName,
+		reviewer1Text, reviewer1Rating, res.GetProduct().GetId()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err = grpcClient.DeleteReview(ctx, server.DeleteReviewRequest(rev.GetReview().GetId()))
+		assert.NoError(t, err)
+	})
+
+	stop()
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, rev.GetReview().GetId(), event.GetReview().GetId())
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+	require.NoError(t, <-done)
+
+	// server doesn't accept new requests once it is stopped
+	_, err = embeddedClient.GetProductByID(ctx, server.GetProductByIDRequest(res.GetProduct().GetId()))
+	require.Error(t, err)
+}
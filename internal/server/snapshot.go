@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/eroshiva/cloudtalk/internal/ent"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+	"github.com/eroshiva/cloudtalk/pkg/rabbitmq"
+)
+
+const actionSnapshot = "snapshot"
+
+// SnapshotOptions defines which resources are re-emitted and how.
+type SnapshotOptions struct {
+	// ProductIDs limits snapshot to the specified products. All products are re-emitted when both ProductIDs
+	// and ReviewIDs are empty.
+	ProductIDs []string
+	// ReviewIDs selects reviews to re-emit on their own, i.e., without their products and other reviews.
+	ReviewIDs []string
+	// RoutingKey is a routing key to publish snapshot events with. Main queue's routing key is used when empty.
+	RoutingKey string
+	// Rate limits number of published events per second. Events are not throttled when 0 or negative.
+	Rate int
+}
+
+// EmitSnapshot publishes synthetic snapshot events reflecting current state of products and their reviews.
+// It allows newly subscribed consumers to catch up with the state prior to their subscription.
+// Returns the number of published events.
+func EmitSnapshot(ctx context.Context, store db.Store, events EventPublisher, opts SnapshotOptions) (int, error) {
+	routingKey := opts.RoutingKey
+	if routingKey == "" {
+		routingKey = rabbitmq.DefaultRoutingKey()
+	}
+
+	// retrieving products together with their reviews
+	var ps []*ent.Product
+	if len(opts.ProductIDs) == 0 && len(opts.ReviewIDs) == 0 {
+		var err error
+		ps, err = store.ListProducts(ctx)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		for _, id := range opts.ProductIDs {
+			p, err := store.GetProductByID(ctx, id)
+			if err != nil {
+				return 0, err
+			}
+			ps = append(ps, p)
+		}
+	}
+	// retrieving selected reviews together with their products
+	rs := make([]*ent.Review, 0, len(opts.ReviewIDs))
+	for _, id := range opts.ReviewIDs {
+		r, err := store.GetReviewByID(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		rs = append(rs, r)
+	}
+	zlog.Info().Ctx(ctx).Msgf("Emitting snapshot of %d product(s) and %d review(s) with routing key %s", len(ps), len(rs), routingKey)
+
+	// throttling publishing, if requested
+	var throttle <-chan time.Time
+	if opts.Rate > 0 {
+		// rates above one event per nanosecond are not throttled any further
+		ticker := time.NewTicker(max(time.Second/time.Duration(opts.Rate), time.Nanosecond))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+	published := 0
+	publish := func(text string) error {
+		if throttle != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-throttle:
+			}
+		}
+		err := events.PublishEvent(ctx, routingKey, text)
+		if err != nil {
+			return err
+		}
+		published++
+		return nil
+	}
+
+	for _, p := range ps {
+		err := publish(ComposeEventOnProductSnapshot(p.ID, p.Name, p.AverageRating, len(p.Edges.Reviews)))
+		if err != nil {
+			return published, err
+		}
+		for _, r := range p.Edges.Reviews {
+			err = publish(ComposeEventOnReviewChange(actionSnapshot, r.Rating, r.FirstName, r.LastName, p.ID))
+			if err != nil {
+				return published, err
+			}
+		}
+	}
+	for _, r := range rs {
+		productID := ""
+		if r.Edges.Product != nil {
+			productID = r.Edges.Product.ID
+		}
+		err := publish(ComposeEventOnReviewChange(actionSnapshot, r.Rating, r.FirstName, r.LastName, productID))
+		if err != nil {
+			return published, err
+		}
+	}
+	zlog.Info().Ctx(ctx).Msgf("Snapshot is emitted, %d event(s) were published", published)
+	return published, nil
+}
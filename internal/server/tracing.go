@@ -0,0 +1,19 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingMiddleware names the span of the HTTP request after the matched route pattern of the gateway.
+// Span is started by the otelhttp handler before the route is known, thus it is named only by the method until now.
+func tracingMiddleware(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+			trace.SpanFromContext(r.Context()).SetName(r.Method + " " + pattern.String())
+		}
+		next(w, r, pathParams)
+	}
+}
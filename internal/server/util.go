@@ -1,10 +1,27 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"strings"
+
 	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
 	"github.com/eroshiva/cloudtalk/internal/ent"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+	"github.com/eroshiva/cloudtalk/pkg/logger"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
+// idempotencyKeyHeader is a header (or gRPC metadata key) carrying idempotency key of the create request.
+const idempotencyKeyHeader = "idempotency-key"
+
+// allProductsQuery identifies unfiltered and not paginated variant of the product list in the cache.
+// Filtered or paginated variants must be cached under queries composed of their parameters.
+const allProductsQuery = "all"
+
 // ConvertReviewResourceToProtobuf converts Review resource to Protobuf notation.
 func ConvertReviewResourceToProtobuf(r *ent.Review) *apiv1.Review {
 	return &apiv1.Review{
@@ -58,3 +75,53 @@ func ConvertReviewProtobufToReview(r *apiv1.Review) *ent.Review {
 		Rating:     r.GetRating(),
 	}
 }
+
+// getIdempotencyKey returns idempotency key specified in the request. If it is not specified, Idempotency-Key header is used.
+func getIdempotencyKey(ctx context.Context, requestKey string) string {
+	if requestKey != "" {
+		return requestKey
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(idempotencyKeyHeader); len(keys) > 0 {
+			return keys[0]
+		}
+	}
+	return ""
+}
+
+// idempotencyStatus reports idempotency key reused with a different request as FailedPrecondition, so the client
+// doesn't take the originally created resource for its own. Other errors are returned unchanged.
+func idempotencyStatus(err error) error {
+	if errors.Is(err, db.ErrIdempotencyKeyReused) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
+
+// incomingHeaderMatcher forwards Idempotency-Key, X-Request-Id and X-Read-Your-Writes headers to the gRPC server
+// in addition to the default set of headers.
+func incomingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, idempotencyKeyHeader) {
+		return idempotencyKeyHeader, true
+	}
+	if strings.EqualFold(key, readYourWritesHeader) {
+		return readYourWritesHeader, true
+	}
+	if strings.EqualFold(key, logger.RequestIDHeader) {
+		return logger.RequestIDHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaderMatcher forwards gRPC response metadata to the HTTP response as Grpc-Metadata-* headers,
+// except of the request ID, which is already set in the X-Request-Id header by the gateway (see requestIDHandler),
+// and of the read-your-writes token, which is sent back by the client in the same header.
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, logger.RequestIDHeader) {
+		return "", false
+	}
+	if strings.EqualFold(key, readYourWritesHeader) {
+		return readYourWritesHeader, true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
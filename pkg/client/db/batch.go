@@ -0,0 +1,227 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/eroshiva/cloudtalk/internal/ent"
+	"github.com/eroshiva/cloudtalk/internal/ent/product"
+	"github.com/eroshiva/cloudtalk/internal/ent/review"
+	"github.com/google/uuid"
+)
+
+// ReviewInput holds parameters of the Review resource to be created in a batch.
+type ReviewInput struct {
+	FirstName string
+	LastName  string
+	Text      string
+	Rating    int32
+	ProductID string
+}
+
+// ReviewResult holds result of a batch operation for a single Review resource.
+// Review resource carries Product resource with updated average rating.
+type ReviewResult struct {
+	Review *ent.Review
+	Err    error
+}
+
+// ProductResult holds result of a batch operation for a single Product resource.
+type ProductResult struct {
+	Product *ent.Product
+	Err     error
+}
+
+// GetProductsByIDs retrieves multiple Product resources by their IDs with a single query.
+// Results are reported in the same order as IDs were provided.
+func GetProductsByIDs(ctx context.Context, client *ent.Client, ids []string) ([]ProductResult, error) {
+	zlog.Debug().Ctx(ctx).Msgf("Retrieving %d products by IDs", len(ids))
+	ps, err := client.Product.Query().
+		Where(product.IDIn(ids...)).
+		// eager-loading reviews as well
+		WithReviews().
+		All(ctx)
+	if err != nil {
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to retrieve products by IDs")
+		return nil, err
+	}
+	found := make(map[string]*ent.Product, len(ps))
+	for _, p := range ps {
+		found[p.ID] = p
+	}
+
+	results := make([]ProductResult, len(ids))
+	for i, id := range ids {
+		if p, ok := found[id]; ok {
+			results[i].Product = p
+		} else {
+			results[i].Err = fmt.Errorf("product with ID (%s) is not found", id)
+		}
+	}
+	return results, nil
+}
+
+// CreateReviews creates multiple Review resources within a single transaction.
+// Average rating is recomputed once per affected Product resource.
+// Results are reported in the same order as Review resources were provided,
+// invalid Review resources don't prevent others from being created.
+func CreateReviews(ctx context.Context, client *ent.Client, inputs []ReviewInput) ([]ReviewResult, error) {
+	zlog.Debug().Ctx(ctx).Msgf("Creating %d reviews in a batch", len(inputs))
+	results := make([]ReviewResult, len(inputs))
+
+	// input parameters sanity check
+	productIDs := make([]string, 0)
+	for i, in := range inputs {
+		results[i].Err = validateReview(ctx, in.FirstName, in.LastName, in.Text, in.Rating, in.ProductID)
+		if results[i].Err == nil {
+			productIDs = append(productIDs, in.ProductID)
+		}
+	}
+	if len(productIDs) == 0 {
+		return results, nil
+	}
+
+	// transaction is retried as a whole, when it conflicts with a concurrent one
+	var existing map[string]bool
+	var created []int // indexes of reviews, which are being created
+	var rs []*ent.Review
+	var updated map[string]*ent.Product
+	err := runRatingTx(ctx, client, "create_reviews", func(tx *ent.Tx) error {
+		// locking all affected products at once, so the average rating is not changed concurrently
+		var err error
+		existing, err = lockProductsForRatingTx(ctx, tx, productIDs)
+		if err != nil {
+			return err
+		}
+
+		// creating all valid reviews with a single statement
+		builders := make([]*ent.ReviewCreate, 0, len(inputs))
+		created = make([]int, 0, len(inputs))
+		affected := make([]string, 0, len(inputs))
+		for i, in := range inputs {
+			if results[i].Err != nil || !existing[in.ProductID] {
+				continue
+			}
+			builders = append(builders, tx.Review.Create().
+				SetID(reviewPrefix+uuid.NewString()).
+				SetFirstName(in.FirstName).
+				SetLastName(in.LastName).
+				SetReviewText(in.Text).
+				SetRating(in.Rating).
+				SetProductID(in.ProductID))
+			created = append(created, i)
+			affected = append(affected, in.ProductID)
+		}
+		rs, err = tx.Review.CreateBulk(builders...).Save(ctx)
+		if err != nil {
+			zlog.Err(err).Ctx(ctx).Msgf("Failed to create reviews in a batch")
+			return err
+		}
+
+		// recalculate average rating of each affected product during the same transaction, missing products are skipped
+		updated, err = updateProductsAverageRating(ctx, tx, affected)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, in := range inputs {
+		if results[i].Err == nil && !existing[in.ProductID] {
+			results[i].Err = fmt.Errorf("product with ID (%s) is not found", in.ProductID)
+		}
+	}
+	for j, i := range created {
+		r := rs[j].Unwrap() // review is returned outside of the transaction
+		r.Edges.Product = updated[inputs[i].ProductID]
+		results[i].Review = r
+	}
+	return results, nil
+}
+
+// DeleteReviewsByIDs removes multiple Review resources within a single transaction.
+// Average rating is recomputed once per affected Product resource.
+// Results are reported in the same order as IDs were provided, each removed Review resource is returned.
+func DeleteReviewsByIDs(ctx context.Context, client *ent.Client, ids []string) ([]ReviewResult, error) {
+	zlog.Debug().Ctx(ctx).Msgf("Deleting %d reviews in a batch", len(ids))
+	// transaction is retried as a whole, when it conflicts with a concurrent one
+	var results []ReviewResult
+	var updated map[string]*ent.Product
+	err := runRatingTx(ctx, client, "delete_reviews", func(tx *ent.Tx) error {
+		results = make([]ReviewResult, len(ids))
+		rs, err := tx.Review.Query().
+			Where(review.IDIn(ids...)).
+			WithProduct(). // eager-loading Product resource
+			All(ctx)
+		if err != nil {
+			zlog.Err(err).Ctx(ctx).Msgf("Failed to retrieve reviews by IDs")
+			return err
+		}
+		found := make(map[string]*ent.Review, len(rs))
+		productIDs := make([]string, 0, len(rs))
+		for _, r := range rs {
+			found[r.ID] = r
+			if r.Edges.Product != nil {
+				productIDs = append(productIDs, r.Edges.Product.ID)
+			}
+		}
+
+		// locking all affected products at once, so the average rating is not changed concurrently
+		if _, err = lockProductsForRatingTx(ctx, tx, productIDs); err != nil {
+			return err
+		}
+
+		deleted := make([]string, 0, len(rs))
+		for i, id := range ids {
+			r, ok := found[id]
+			switch {
+			case !ok:
+				results[i].Err = fmt.Errorf("review with ID (%s) is not found", id)
+			case r == nil:
+				results[i].Err = fmt.Errorf("review with ID (%s) is requested to be deleted more than once", id)
+			default:
+				deleted = append(deleted, id)
+				results[i].Review = r
+				found[id] = nil // marking review as already deleted
+			}
+		}
+		_, err = tx.Review.Delete().Where(review.IDIn(deleted...)).Exec(ctx)
+		if err != nil {
+			zlog.Err(err).Ctx(ctx).Msgf("Failed to delete reviews in a batch")
+			return err
+		}
+
+		// recalculate average rating of each affected product during the same transaction
+		updated, err = updateProductsAverageRating(ctx, tx, productIDs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		if r := results[i].Review; r != nil {
+			r = r.Unwrap() // review is returned outside of the transaction
+			if r.Edges.Product != nil {
+				r.Edges.Product = updated[r.Edges.Product.ID]
+			}
+			results[i].Review = r
+		}
+	}
+	return results, nil
+}
+
+// updateProductsAverageRating performs recalculation of average rating of each Product resource once during the same transaction.
+// Returns updated Product resources by their IDs.
+func updateProductsAverageRating(ctx context.Context, tx *ent.Tx, ids []string) (map[string]*ent.Product, error) {
+	updated := make(map[string]*ent.Product)
+	for _, id := range ids {
+		if _, ok := updated[id]; ok {
+			continue
+		}
+		p, err := updateProductAverageRating(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		updated[id] = p.Unwrap() // product is returned outside of the transaction
+	}
+	return updated, nil
+}
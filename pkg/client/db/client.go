@@ -4,7 +4,9 @@ package db
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"entgo.io/ent/dialect/sql"
 	"github.com/eroshiva/cloudtalk/internal/ent"
 	"github.com/eroshiva/cloudtalk/internal/ent/product"
 	"github.com/eroshiva/cloudtalk/internal/ent/review"
@@ -21,28 +23,28 @@ var zlog = logger.NewLogger("db-client")
 
 // CreateProduct creates Product resource.
 func CreateProduct(ctx context.Context, client *ent.Client, name, description, price string) (*ent.Product, error) {
+	return createProduct(ctx, client, "", name, description, price)
+}
+
+// createProduct creates Product resource. When idempotency key is specified, it is stored within the same transaction.
+func createProduct(ctx context.Context, client *ent.Client, idempotencyKey, name, description, price string) (*ent.Product, error) {
 	// input parameters sanity check
-	if name == "" {
-		err := fmt.Errorf("product name is not specified")
-		zlog.Error().Err(err).Send()
-		return nil, err
-	}
-	if description == "" {
-		err := fmt.Errorf("product description is not specified")
-		zlog.Error().Err(err).Send()
-		return nil, err
-	}
-	if price == "" {
-		err := fmt.Errorf("product price is not specified")
-		zlog.Error().Err(err).Send()
+	if err := validateProduct(ctx, name, description, price); err != nil {
 		return nil, err
 	}
-	zlog.Debug().Msgf("Creating product %s", name)
+	zlog.Debug().Ctx(ctx).Msgf("Creating product %s", name)
 
 	// generating random ID for the Product resource
 	id := productPrefix + uuid.NewString()
 
-	p, err := client.Product.Create().
+	// get transaction
+	tx, err := beginTx(ctx, client, "create_product", nil)
+	if err != nil {
+		zlog.Error().Ctx(ctx).Err(err).Msgf("Failed to create transaction")
+		return nil, err
+	}
+
+	p, err := tx.Product.Create().
 		SetID(id).
 		SetName(name).
 		SetDescription(description).
@@ -50,16 +52,50 @@ func CreateProduct(ctx context.Context, client *ent.Client, name, description, p
 		SetAverageRating(0). // created product doesn't have any reviews yet, setting ratings value to 0
 		Save(ctx)
 	if err != nil {
-		zlog.Err(err).Msgf("Failed to create product %s", name)
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to create product %s", name)
+		return nil, rollback(tx, err)
+	}
+
+	// storing idempotency key during the same transaction
+	if idempotencyKey != "" {
+		if err = saveIdempotencyKeyTx(ctx, tx, idempotencyKey, productRequestHash(name, description, price), id); err != nil {
+			return nil, rollback(tx, err)
+		}
+	}
+
+	// if all operations succeed, commit the transaction.
+	if err = tx.Commit(); err != nil {
+		zlog.Error().Ctx(ctx).Err(err).Msgf("Failed to commit transaction")
 		return nil, err
 	}
+	p = p.Unwrap() // product is returned outside of the transaction
 
 	return p, nil
 }
 
+// validateProduct performs sanity check of the Product resource's parameters.
+func validateProduct(ctx context.Context, name, description, price string) error {
+	if name == "" {
+		err := fmt.Errorf("product name is not specified")
+		zlog.Error().Ctx(ctx).Err(err).Send()
+		return err
+	}
+	if description == "" {
+		err := fmt.Errorf("product description is not specified")
+		zlog.Error().Ctx(ctx).Err(err).Send()
+		return err
+	}
+	if price == "" {
+		err := fmt.Errorf("product price is not specified")
+		zlog.Error().Ctx(ctx).Err(err).Send()
+		return err
+	}
+	return nil
+}
+
 // GetProductByID retrieves Product resource by its ID.
 func GetProductByID(ctx context.Context, client *ent.Client, id string) (*ent.Product, error) {
-	zlog.Debug().Msgf("Retrieving product by ID (%s)", id)
+	zlog.Debug().Ctx(ctx).Msgf("Retrieving product by ID (%s)", id)
 	p, err := client.Product.
 		Query().
 		Where(product.ID(id)).
@@ -67,7 +103,7 @@ func GetProductByID(ctx context.Context, client *ent.Client, id string) (*ent.Pr
 		WithReviews().
 		Only(ctx)
 	if err != nil {
-		zlog.Err(err).Msgf("Failed to retrieve product with ID (%s)", id)
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to retrieve product with ID (%s)", id)
 		return nil, err
 	}
 	return p, nil
@@ -76,15 +112,14 @@ func GetProductByID(ctx context.Context, client *ent.Client, id string) (*ent.Pr
 // GetProductByIDTx retrieves Product resource by its ID. Does not commit transaction!
 // This function should NOT be used in the production.
 func GetProductByIDTx(ctx context.Context, tx *ent.Tx, id string) (*ent.Product, error) {
-	zlog.Debug().Msgf("Retrieving product by ID (%s)", id)
-	p, err := tx.Product.Query().
+	zlog.Debug().Ctx(ctx).Msgf("Retrieving product by ID (%s)", id)
+	q := tx.Product.Query().
 		Where(product.ID(id)).
 		// eager-loading reviews as well
-		WithReviews().
-		ForUpdate(). // locking
-		Only(ctx)
+		WithReviews()
+	p, err := ProductsForUpdate(q).Only(ctx) // locking
 	if err != nil {
-		zlog.Err(err).Msgf("Failed to retrieve product with ID (%s)", id)
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to retrieve product with ID (%s)", id)
 		return nil, rollback(tx, err)
 	}
 	return p, nil
@@ -92,7 +127,7 @@ func GetProductByIDTx(ctx context.Context, tx *ent.Tx, id string) (*ent.Product,
 
 // EditProduct updates all provided non-nil fields in Product resource.
 func EditProduct(ctx context.Context, client *ent.Client, id string, name, description, price string) (*ent.Product, error) {
-	zlog.Debug().Msgf("Editing product (%s)", id)
+	zlog.Debug().Ctx(ctx).Msgf("Editing product (%s)", id)
 
 	p, err := GetProductByID(ctx, client, id)
 	if err != nil {
@@ -115,14 +150,14 @@ func EditProduct(ctx context.Context, client *ent.Client, id string, name, descr
 		SetPrice(p.Price).
 		Save(ctx)
 	if err != nil {
-		zlog.Err(err).Msgf("Failed to edit product")
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to edit product")
 		return nil, err
 	}
 
 	if numAfNodes != 1 {
 		// something bad has happened, returning error
 		newErr := fmt.Errorf("update of product didn't return error, number of affected nodes is %d", numAfNodes)
-		zlog.Error().Err(newErr).Send()
+		zlog.Error().Ctx(ctx).Err(newErr).Send()
 		return nil, err
 	}
 	return p, nil
@@ -130,14 +165,31 @@ func EditProduct(ctx context.Context, client *ent.Client, id string, name, descr
 
 // ListProducts retrieves all Products available in the system.
 func ListProducts(ctx context.Context, client *ent.Client) ([]*ent.Product, error) {
-	zlog.Debug().Msgf("Retrieving all products")
+	zlog.Debug().Ctx(ctx).Msgf("Retrieving all products")
 
 	ps, err := client.Product.Query().
 		// Eager-loading edges
 		WithReviews().
 		All(ctx)
 	if err != nil {
-		zlog.Err(err).Msgf("Failed to retrieve all products")
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to retrieve all products")
+		return nil, err
+	}
+	return ps, nil
+}
+
+// ListTopProducts retrieves at most limit Product resources with the highest number of reviews.
+func ListTopProducts(ctx context.Context, client *ent.Client, limit int) ([]*ent.Product, error) {
+	zlog.Debug().Ctx(ctx).Msgf("Retrieving %d products with the most reviews", limit)
+
+	ps, err := client.Product.Query().
+		Order(product.ByReviewsCount(sql.OrderDesc())).
+		Limit(limit).
+		// Eager-loading edges
+		WithReviews().
+		All(ctx)
+	if err != nil {
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to retrieve %d products with the most reviews", limit)
 		return nil, err
 	}
 	return ps, nil
@@ -145,10 +197,10 @@ func ListProducts(ctx context.Context, client *ent.Client) ([]*ent.Product, erro
 
 // DeleteProductByID removes Product resource with provided ID from the DB.
 func DeleteProductByID(ctx context.Context, client *ent.Client, id string) error {
-	zlog.Debug().Msgf("Deleting product with ID (%s)", id)
+	zlog.Debug().Ctx(ctx).Msgf("Deleting product with ID (%s)", id)
 	_, err := client.Product.Delete().Where(product.ID(id)).Exec(ctx)
 	if err != nil {
-		zlog.Err(err).Msgf("Failed to delete product with ID (%s)", id)
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to delete product with ID (%s)", id)
 		return err
 	}
 	return nil
@@ -165,42 +217,28 @@ func rollback(tx *ent.Tx, err error) error {
 }
 
 // CreateReview creates a Review resource.
+// Returned Review resource carries Product resource with updated average rating.
 func CreateReview(ctx context.Context, client *ent.Client, name, lastName, text string, rating int32, productID string) (
 	*ent.Review, error,
 ) {
+	return createReview(ctx, client, "", name, lastName, text, rating, productID)
+}
+
+// createReview creates a Review resource. When idempotency key is specified, it is stored within the same transaction.
+func createReview(ctx context.Context, client *ent.Client, idempotencyKey, name, lastName, text string, rating int32,
+	productID string,
+) (*ent.Review, error) {
 	// input parameters sanity check
-	if name == "" {
-		err := fmt.Errorf("reviewer's name is not specified")
-		zlog.Error().Err(err).Send()
-		return nil, err
-	}
-	if lastName == "" {
-		err := fmt.Errorf("reviewer's last name is not specified")
-		zlog.Error().Err(err).Send()
-		return nil, err
-	}
-	if text == "" {
-		err := fmt.Errorf("text of the review is not specified")
-		zlog.Error().Err(err).Send()
-		return nil, err
-	}
-	if rating < 1 || rating > 5 {
-		err := fmt.Errorf("review's rating is out of range")
-		zlog.Error().Err(err).Msgf("Review's rating must be between 1 and 5, but has %d", rating)
-		return nil, err
-	}
-	if productID == "" {
-		err := fmt.Errorf("review's product is not specified")
-		zlog.Error().Err(err).Send()
+	if err := validateReview(ctx, name, lastName, text, rating, productID); err != nil {
 		return nil, err
 	}
 
-	zlog.Debug().Msgf("Creating review by %s %s for product with ID (%s)", name, lastName, productID)
+	zlog.Debug().Ctx(ctx).Msgf("Creating review by %s %s for product with ID (%s)", name, lastName, productID)
 
 	// retrieving full Product resource first
 	p, err := GetProductByID(ctx, client, productID)
 	if err != nil {
-		zlog.Err(err).Msgf("Failed to retrieve product with ID (%s)", productID)
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to retrieve product with ID (%s)", productID)
 		return nil, err
 	}
 	p.Edges.Reviews = nil // no need to carry over inner references
@@ -208,51 +246,80 @@ func CreateReview(ctx context.Context, client *ent.Client, name, lastName, text
 	// generating random ID for the Review resource
 	id := reviewPrefix + uuid.NewString()
 
-	// get transaction
-	tx, err := client.Tx(ctx)
+	// transaction is retried as a whole, when it conflicts with a concurrent one
+	var r *ent.Review
+	err = runRatingTx(ctx, client, "create_review", func(tx *ent.Tx) error {
+		// create review in the transaction
+		r, err = tx.Review.Create().
+			SetID(id).
+			SetFirstName(name).
+			SetLastName(lastName).
+			SetReviewText(text).
+			SetRating(rating).
+			SetProduct(p).
+			Save(ctx)
+		if err != nil {
+			zlog.Err(err).Ctx(ctx).Msgf("Failed to create review by %s %s for product with ID (%s)", name, lastName, productID)
+			return err
+		}
+
+		// recalculate average rating during the same transaction, review carries over the updated product
+		r.Edges.Product, err = updateProductAverageRating(ctx, tx, productID)
+		if err != nil {
+			return err
+		}
+
+		// storing idempotency key during the same transaction
+		if idempotencyKey != "" {
+			return saveIdempotencyKeyTx(ctx, tx, idempotencyKey, reviewRequestHash(name, lastName, text, rating, productID), id)
+		}
+		return nil
+	})
 	if err != nil {
-		zlog.Error().Err(err).Msgf("Failed to create transaction")
 		return nil, err
 	}
+	return r, nil
+}
 
-	// create review in the transaction
-	r, err := tx.Review.Create().
-		SetID(id).
-		SetFirstName(name).
-		SetLastName(lastName).
-		SetReviewText(text).
-		SetRating(rating).
-		SetProduct(p).
-		Save(ctx)
-	if err != nil {
-		zlog.Err(err).Msgf("Failed to create review by %s %s for product with ID (%s)", name, lastName, productID)
-		return nil, rollback(tx, err)
+// validateReview performs sanity check of the Review resource's parameters.
+func validateReview(ctx context.Context, name, lastName, text string, rating int32, productID string) error {
+	if name == "" {
+		err := fmt.Errorf("reviewer's name is not specified")
+		zlog.Error().Ctx(ctx).Err(err).Send()
+		return err
 	}
-
-	// recalculate average rating during the same transaction
-	_, err = updateProductAverageRating(ctx, tx, productID)
-	if err != nil {
-		return nil, rollback(tx, err)
+	if lastName == "" {
+		err := fmt.Errorf("reviewer's last name is not specified")
+		zlog.Error().Ctx(ctx).Err(err).Send()
+		return err
 	}
-
-	// if all operations succeed, commit the transaction.
-	if err = tx.Commit(); err != nil {
-		zlog.Error().Err(err).Msgf("Failed to commit transaction")
-		return nil, err
+	if text == "" {
+		err := fmt.Errorf("text of the review is not specified")
+		zlog.Error().Ctx(ctx).Err(err).Send()
+		return err
 	}
-
-	return r, nil
+	if rating < 1 || rating > 5 {
+		err := fmt.Errorf("review's rating is out of range")
+		zlog.Error().Ctx(ctx).Err(err).Msgf("Review's rating must be between 1 and 5, but has %d", rating)
+		return err
+	}
+	if productID == "" {
+		err := fmt.Errorf("review's product is not specified")
+		zlog.Error().Ctx(ctx).Err(err).Send()
+		return err
+	}
+	return nil
 }
 
 // GetReviewByID retrieves Review resource by its ID.
 func GetReviewByID(ctx context.Context, client *ent.Client, id string) (*ent.Review, error) {
-	zlog.Debug().Msgf("Retrieving review by ID (%s)", id)
+	zlog.Debug().Ctx(ctx).Msgf("Retrieving review by ID (%s)", id)
 	r, err := client.Review.Query().
 		Where(review.ID(id)).
 		WithProduct(). // eager-loading Product resource
 		Only(ctx)
 	if err != nil {
-		zlog.Err(err).Msgf("Failed to retrieve review by ID (%s)", id)
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to retrieve review by ID (%s)", id)
 		return nil, err
 	}
 	return r, nil
@@ -260,20 +327,21 @@ func GetReviewByID(ctx context.Context, client *ent.Client, id string) (*ent.Rev
 
 // GetReviewsByProductID retrieves review resource by provided Product resource ID.
 func GetReviewsByProductID(ctx context.Context, client *ent.Client, id string) ([]*ent.Review, error) {
-	zlog.Debug().Msgf("Retrieving all reviews for Product with ID (%s)", id)
+	zlog.Debug().Ctx(ctx).Msgf("Retrieving all reviews for Product with ID (%s)", id)
 	rs, err := client.Review.Query().
 		Where(review.HasProductWith(product.ID(id))).
 		All(ctx)
 	if err != nil {
-		zlog.Err(err).Msgf("Failed to retrieve all reviews for Product with ID (%s)", id)
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to retrieve all reviews for Product with ID (%s)", id)
 		return nil, err
 	}
 	return rs, nil
 }
 
 // EditReview updates all provided non-nil fields of Review resource.
+// Returned Review resource carries Product resource with updated average rating.
 func EditReview(ctx context.Context, client *ent.Client, id string, name, lastName, text string, rating int32) (*ent.Review, error) {
-	zlog.Debug().Msgf("Editing review (%s)", id)
+	zlog.Debug().Ctx(ctx).Msgf("Editing review (%s)", id)
 	r, err := GetReviewByID(ctx, client, id) // Product resource is eager-loaded
 	if err != nil {
 		return nil, err
@@ -292,113 +360,116 @@ func EditReview(ctx context.Context, client *ent.Client, id string, name, lastNa
 	}
 	// product is not allowed to be manipulated
 
-	// get transaction
-	tx, err := client.Tx(ctx)
-	if err != nil {
-		zlog.Error().Err(err).Msgf("Failed to create transaction")
-		return nil, err
-	}
-
-	// update review resource
-	numAfNodes, err := tx.Review.Update().
-		Where(review.ID(id)).
-		SetFirstName(r.FirstName).
-		SetLastName(r.LastName).
-		SetReviewText(r.ReviewText).
-		SetRating(r.Rating).
-		Save(ctx)
-	if err != nil {
-		zlog.Err(err).Msgf("Failed to edit review")
-		return nil, rollback(tx, err)
-	}
-
-	if numAfNodes != 1 {
-		// something bad has happened, returning error
-		newErr := fmt.Errorf("update of review didn't return error, number of affected nodes is %d", numAfNodes)
-		zlog.Error().Err(newErr).Send()
-		return nil, rollback(tx, err)
-	}
-
-	// recalculate average rating during the same transaction
-	_, err = updateProductAverageRating(ctx, tx, r.Edges.Product.ID)
+	// transaction is retried as a whole, when it conflicts with a concurrent one
+	var updP *ent.Product
+	err = runRatingTx(ctx, client, "edit_review", func(tx *ent.Tx) error {
+		// update review resource
+		numAfNodes, err := tx.Review.Update().
+			Where(review.ID(id)).
+			SetFirstName(r.FirstName).
+			SetLastName(r.LastName).
+			SetReviewText(r.ReviewText).
+			SetRating(r.Rating).
+			Save(ctx)
+		if err != nil {
+			zlog.Err(err).Ctx(ctx).Msgf("Failed to edit review")
+			return err
+		}
+
+		if numAfNodes != 1 {
+			// something bad has happened, returning error
+			err = fmt.Errorf("update of review didn't return error, number of affected nodes is %d", numAfNodes)
+			zlog.Error().Ctx(ctx).Err(err).Send()
+			return err
+		}
+
+		// recalculate average rating during the same transaction
+		updP, err = updateProductAverageRating(ctx, tx, r.Edges.Product.ID)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	// if all operations succeed, commit the transaction.
-	if err = tx.Commit(); err != nil {
-		zlog.Error().Err(err).Msgf("Failed to commit transaction")
-		return nil, err
-	}
+	r.Edges.Product = updP // carrying over Product resource with updated average rating
 	return r, nil
 }
 
 // DeleteReviewByID removes Review resource with provided ID from the DB.
-func DeleteReviewByID(ctx context.Context, client *ent.Client, id, productID string) error {
-	zlog.Debug().Msgf("Deleting review with ID (%s)", id)
-	// get transaction
-	tx, err := client.Tx(ctx)
-	if err != nil {
-		zlog.Error().Err(err).Msgf("Failed to create transaction")
+// Returns Product resource with updated average rating.
+func DeleteReviewByID(ctx context.Context, client *ent.Client, id, productID string) (*ent.Product, error) {
+	zlog.Debug().Ctx(ctx).Msgf("Deleting review with ID (%s)", id)
+	// transaction is retried as a whole, when it conflicts with a concurrent one
+	var updP *ent.Product
+	err := runRatingTx(ctx, client, "delete_review", func(tx *ent.Tx) error {
+		// delete of Review resource
+		_, err := tx.Review.Delete().Where(review.ID(id)).Exec(ctx)
+		if err != nil {
+			zlog.Err(err).Ctx(ctx).Msgf("Failed to delete review with ID (%s)", id)
+			return err
+		}
+
+		// recalculate average rating during the same transaction
+		updP, err = updateProductAverageRating(ctx, tx, productID)
 		return err
-	}
-
-	// delete of Review resource
-	_, err = tx.Review.Delete().Where(review.ID(id)).Exec(ctx)
+	})
 	if err != nil {
-		zlog.Err(err).Msgf("Failed to delete review with ID (%s)", id)
-		return rollback(tx, err)
+		return nil, err
 	}
+	return updP, nil
+}
 
-	// recalculate average rating during the same transaction
-	_, err = updateProductAverageRating(ctx, tx, productID)
+// updateProductAverageRating performs recalculation of average rating during the same transaction. Product is guarded
+// against concurrent updates of its average rating as the configured strategy does, its version is incremented.
+// Reports errConcurrentUpdate, when the product was updated since it was read (i.e., no lock is taken). Does not commit
+// transaction!
+func updateProductAverageRating(ctx context.Context, tx *ent.Tx, productID string) (*ent.Product, error) {
+	zlog.Info().Ctx(ctx).Msgf("Updating average product rating for product (%s)", productID)
+	// fetch the Product resource by ID during provided transaction, locking it, if the strategy does so
+	start := time.Now()
+	q, err := guardRatingTx(ctx, tx, tx.Product.Query().Where(product.ID(productID)), []string{productID})
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	// if all operations succeed, commit the transaction.
-	if err = tx.Commit(); err != nil {
-		zlog.Error().Err(err).Msgf("Failed to commit transaction")
-		return err
+	p, err := q.Only(ctx)
+	observeLockWait(start) // reviews are loaded once the product is locked, thus they are not timed as the lock wait
+	if err != nil {
+		zlog.Error().Ctx(ctx).Err(err).Msgf("Failed to retrieve product with ID (%s)", productID)
+		return nil, err
 	}
-	return nil
-}
-
-// updateProductAverageRating performs recalculation of average rating during the same transaction.
-func updateProductAverageRating(ctx context.Context, tx *ent.Tx, productID string) (*ent.Product, error) {
-	zlog.Info().Msgf("Updating average product rating for product (%s)", productID)
-	// fetch the Product resource by ID during provided transaction and perform a database lock.
-	p, err := tx.Product.Query().
-		Where(product.ID(productID)).
-		WithReviews(). // eager-loading all reviews
-		ForUpdate().   // pessimistic locking => lock the product row for the duration of this transaction
-		Only(ctx)
+	reviews, err := tx.Product.QueryReviews(p).All(ctx)
 	if err != nil {
-		zlog.Error().Err(err).Msgf("Failed to retrieve product with ID (%s)", productID)
-		return nil, rollback(tx, err)
+		zlog.Error().Ctx(ctx).Err(err).Msgf("Failed to retrieve reviews of product with ID (%s)", productID)
+		return nil, err
 	}
 
 	// calculate the sum of ratings and the total count.
 	var totalRating int32
-	for _, r := range p.Edges.Reviews {
+	for _, r := range reviews {
 		totalRating += r.Rating
 	}
 
-	reviewCount := len(p.Edges.Reviews) // total number of reviews
+	reviewCount := len(reviews) // total number of reviews
 	// computing average rating
 	newAverage := 0.0
 	if reviewCount > 0 {
 		newAverage = float64(totalRating) / float64(reviewCount)
 	}
 
-	// updating Product resource with the newly calculated values
-	updatedProduct, err := tx.Product.UpdateOne(p).
+	// updating Product resource with the newly calculated values, unless it was changed since it was read
+	n, err := tx.Product.Update().
+		Where(product.ID(productID), product.Version(p.Version)).
 		SetAverageRating(newAverage).
+		AddVersion(1).
 		Save(ctx)
 	if err != nil {
-		zlog.Error().Err(err).Msgf("Failed to update average rating for product with ID (%s)", productID)
-		return nil, rollback(tx, err)
+		zlog.Error().Ctx(ctx).Err(err).Msgf("Failed to update average rating for product with ID (%s)", productID)
+		return nil, err
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("product with ID (%s): %w", productID, errConcurrentUpdate)
 	}
 
-	return updatedProduct, nil
+	p.AverageRating = newAverage
+	p.Version++
+	return p, nil
 }
@@ -10,7 +10,9 @@ import (
 
 	"github.com/eroshiva/cloudtalk/internal/ent"
 	"github.com/eroshiva/cloudtalk/internal/ent/product"
+	"github.com/eroshiva/cloudtalk/internal/ent/review"
 	"github.com/eroshiva/cloudtalk/pkg/client/db"
+	"github.com/eroshiva/cloudtalk/pkg/client/db/storetest"
 	prs_testing "github.com/eroshiva/cloudtalk/pkg/testing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -111,7 +113,7 @@ func TestReviewCRUD(t *testing.T) {
 	require.NotNil(t, r1)
 	// cleaning up review
 	t.Cleanup(func() {
-		err = db.DeleteReviewByID(ctx, client, r1.ID, p.ID)
+		_, err = db.DeleteReviewByID(ctx, client, r1.ID, p.ID)
 		assert.NoError(t, err)
 	})
 	assert.Equal(t, reviewer1Name, r1.FirstName)
@@ -125,7 +127,7 @@ func TestReviewCRUD(t *testing.T) {
 	require.NotNil(t, r2)
 	// cleaning up review
 	t.Cleanup(func() {
-		err = db.DeleteReviewByID(ctx, client, r2.ID, p.ID)
+		_, err = db.DeleteReviewByID(ctx, client, r2.ID, p.ID)
 		assert.NoError(t, err)
 	})
 	assert.Equal(t, reviewer2Name, r2.FirstName)
@@ -148,7 +150,7 @@ func TestReviewCRUD(t *testing.T) {
 	require.NotNil(t, r3)
 	// cleaning up review
 	t.Cleanup(func() {
-		err = db.DeleteReviewByID(ctx, client, r3.ID, p.ID)
+		_, err = db.DeleteReviewByID(ctx, client, r3.ID, p.ID)
 		assert.NoError(t, err)
 	})
 	assert.Equal(t, reviewer3Name, r3.FirstName)
@@ -198,7 +200,7 @@ func TestLockingOnConcurrentReviewCreation(t *testing.T) {
 		defer tx.Rollback() //nolint:errcheck // this code doesn't go production
 
 		// Acquire the lock
-		_, err = tx.Product.Query().Where(product.ID(p.ID)).ForUpdate().Only(ctx)
+		_, err = db.ProductsForUpdate(tx.Product.Query().Where(product.ID(p.ID))).Only(ctx)
 		require.NoError(t, err)
 
 		lockHeldChan <- true // signal that the lock is now held
@@ -222,7 +224,7 @@ func TestLockingOnConcurrentReviewCreation(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, r)
 		t.Cleanup(func() {
-			err = db.DeleteReviewByID(ctx, client, r.ID, p.ID)
+			_, err = db.DeleteReviewByID(ctx, client, r.ID, p.ID)
 			assert.NoError(t, err)
 		})
 	}()
@@ -247,3 +249,236 @@ func TestLockingOnConcurrentReviewCreation(t *testing.T) {
 	assert.Equal(t, 1, len(retP.Edges.Reviews))                   // only 1 review was added
 	assert.Equal(t, float64(reviewer1Rating), retP.AverageRating) // average rating must be equal to the only review's rating
 }
+
+// lockingStrategies are compared by TestConcurrentReviewCreationStrategies and BenchmarkConcurrentReviewCreation.
+var lockingStrategies = []string{db.LockingRowLock, db.LockingAdvisoryLock, db.LockingOptimistic, db.LockingSerializable}
+
+// useLockingStrategy switches strategy of average rating updates for the test, strategies not supported
+// by the driver are skipped.
+func useLockingStrategy(tb testing.TB, strategy string) {
+	tb.Helper()
+	previous := db.LockingStrategy()
+	if err := db.SetLockingStrategy(strategy); err != nil {
+		tb.Skip(err)
+	}
+	tb.Cleanup(func() {
+		require.NoError(tb, db.SetLockingStrategy(previous))
+	})
+}
+
+// createContendedProduct creates product, whose reviews are removed together with it once the test is finished.
+func createContendedProduct(ctx context.Context, tb testing.TB) *ent.Product {
+	tb.Helper()
+	p, err := db.CreateProduct(ctx, client, productName1, productDescription1, productPrice1)
+	require.NoError(tb, err)
+	tb.Cleanup(func() {
+		_, err := client.Review.Delete().Where(review.HasProductWith(product.ID(p.ID))).Exec(context.Background())
+		assert.NoError(tb, err)
+		assert.NoError(tb, db.DeleteProductByID(context.Background(), client, p.ID))
+	})
+	return p
+}
+
+// assertAverageRating verifies that no update of the average rating was lost.
+func assertAverageRating(ctx context.Context, tb testing.TB, productID string, expectedCount int) {
+	tb.Helper()
+	p, err := db.GetProductByID(ctx, client, productID)
+	require.NoError(tb, err)
+	require.Len(tb, p.Edges.Reviews, expectedCount)
+	var total int32
+	for _, r := range p.Edges.Reviews {
+		total += r.Rating
+	}
+	assert.InDelta(tb, float64(total)/float64(expectedCount), p.AverageRating, 0.0001)
+}
+
+// TestConcurrentReviewCreationStrategies extends TestLockingOnConcurrentReviewCreation to all locking strategies:
+// many reviews of the same product are created concurrently and none of the average rating updates may be lost.
+func TestConcurrentReviewCreationStrategies(t *testing.T) {
+	const workers, reviewsPerWorker = 8, 5
+	for _, strategy := range lockingStrategies {
+		t.Run(strategy, func(t *testing.T) {
+			useLockingStrategy(t, strategy)
+			ctx, cancel := context.WithTimeout(context.Background(), prs_testing.DefaultTestTimeout*10)
+			t.Cleanup(cancel)
+			p := createContendedProduct(ctx, t)
+
+			var wg sync.WaitGroup
+			for w := range workers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := range reviewsPerWorker {
+						rating := int32((w+i)%5 + 1)
+						_, err := db.CreateReview(ctx, client, reviewer1Name, reviewer1LastName, reviewer1Text, rating, p.ID)
+						assert.NoError(t, err)
+					}
+				}()
+			}
+			wg.Wait()
+			assertAverageRating(ctx, t, p.ID, workers*reviewsPerWorker)
+		})
+	}
+}
+
+// BenchmarkConcurrentReviewCreation compares throughput of the locking strategies, while all goroutines create reviews
+// of the same product, e.g.:
+//
+//	go test -run '^$' -bench BenchmarkConcurrentReviewCreation ./pkg/client/db/
+func BenchmarkConcurrentReviewCreation(b *testing.B) {
+	for _, strategy := range lockingStrategies {
+		b.Run(strategy, func(b *testing.B) {
+			useLockingStrategy(b, strategy)
+			ctx := context.Background()
+			p := createContendedProduct(ctx, b)
+
+			// several writers per CPU, so the product is always contended
+			b.SetParallelism(4)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := db.CreateReview(ctx, client, reviewer1Name, reviewer1LastName, reviewer1Text,
+						reviewer1Rating, p.ID); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.StopTimer()
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "reviews/s")
+			if !b.Failed() {
+				assertAverageRating(ctx, b, p.ID, b.N)
+			}
+		})
+	}
+}
+
+func TestIdempotentCreation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), prs_testing.DefaultTestTimeout)
+	t.Cleanup(cancel)
+
+	// creating product with idempotency key
+	p, created, err := db.CreateProductIdempotent(ctx, client, "product-key", productName1, productDescription1, productPrice1)
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	assert.True(t, created)
+	t.Cleanup(func() {
+		err = db.DeleteProductByID(ctx, client, p.ID)
+		assert.NoError(t, err)
+	})
+
+	// retrying with the same key returns the same product
+	retP, created, err := db.CreateProductIdempotent(ctx, client, "product-key", productName1, productDescription1, productPrice1)
+	require.NoError(t, err)
+	require.NotNil(t, retP)
+	assert.False(t, created)
+	assert.Equal(t, p.ID, retP.ID)
+
+	// creating review with idempotency key
+	r, created, err := db.CreateReviewIdempotent(ctx, client, "review-key", reviewer1Name, reviewer1LastName, reviewer1Text,
+		reviewer1Rating, p.ID)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	assert.True(t, created)
+	t.Cleanup(func() {
+		_, err = db.DeleteReviewByID(ctx, client, r.ID, p.ID)
+		assert.NoError(t, err)
+	})
+
+	// retrying with the same key returns the same review and doesn't create a new one
+	retR, created, err := db.CreateReviewIdempotent(ctx, client, "review-key", reviewer1Name, reviewer1LastName, reviewer1Text,
+		reviewer1Rating, p.ID)
+	require.NoError(t, err)
+	require.NotNil(t, retR)
+	assert.False(t, created)
+	assert.Equal(t, r.ID, retR.ID)
+
+	rs, err := db.GetReviewsByProductID(ctx, client, p.ID)
+	require.NoError(t, err)
+	assert.Len(t, rs, 1)
+}
+
+func TestBatchReviews(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), prs_testing.DefaultTestTimeout)
+	t.Cleanup(cancel)
+
+	// creating products
+	p1, err := db.CreateProduct(ctx, client, productName1, productDescription1, productPrice1)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err = db.DeleteProductByID(ctx, client, p1.ID)
+		assert.NoError(t, err)
+	})
+	p2, err := db.CreateProduct(ctx, client, productName2, productDescription2, productPrice2)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err = db.DeleteProductByID(ctx, client, p2.ID)
+		assert.NoError(t, err)
+	})
+
+	// retrieving products in a batch, one of them doesn't exist
+	ps, err := db.GetProductsByIDs(ctx, client, []string{p1.ID, "non-existing", p2.ID})
+	require.NoError(t, err)
+	require.Len(t, ps, 3)
+	assert.NoError(t, ps[0].Err)
+	assert.Equal(t, p1.ID, ps[0].Product.ID)
+	assert.Error(t, ps[1].Err)
+	assert.NoError(t, ps[2].Err)
+	assert.Equal(t, p2.ID, ps[2].Product.ID)
+
+	// creating reviews in a batch, one of them is invalid
+	rs, err := db.CreateReviews(ctx, client, []db.ReviewInput{
+		{FirstName: reviewer1Name, LastName: reviewer1LastName, Text: reviewer1Text, Rating: reviewer1Rating, ProductID: p1.ID},
+		{FirstName: reviewer2Name, LastName: reviewer2LastName, Text: reviewer2Text, Rating: reviewer2Rating, ProductID: p1.ID},
+		{FirstName: reviewer3Name, LastName: reviewer3LastName, Text: reviewer3Text, Rating: 10, ProductID: p2.ID},
+		{FirstName: reviewer3Name, LastName: reviewer3LastName, Text: reviewer3Text, Rating: reviewer3Rating, ProductID: p2.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, rs, 4)
+	assert.NoError(t, rs[0].Err)
+	assert.NoError(t, rs[1].Err)
+	assert.Error(t, rs[2].Err)
+	assert.NoError(t, rs[3].Err)
+	assert.InDelta(t, float64(reviewer1Rating+reviewer2Rating)/2, rs[1].Review.Edges.Product.AverageRating, 0.001)
+	assert.InDelta(t, float64(reviewer3Rating), rs[3].Review.Edges.Product.AverageRating, 0.001)
+
+	// removing reviews in a batch, including duplicated and non-existing IDs
+	ds, err := db.DeleteReviewsByIDs(ctx, client, []string{rs[0].Review.ID, rs[0].Review.ID, "non-existing", rs[1].Review.ID, rs[3].Review.ID})
+	require.NoError(t, err)
+	require.Len(t, ds, 5)
+	assert.NoError(t, ds[0].Err)
+	assert.Error(t, ds[1].Err)
+	assert.Error(t, ds[2].Err)
+	assert.NoError(t, ds[3].Err)
+	assert.NoError(t, ds[4].Err)
+
+	// average rating is reset once all reviews are gone
+	retP, err := db.GetProductByID(ctx, client, p1.ID)
+	require.NoError(t, err)
+	assert.Empty(t, retP.Edges.Reviews)
+	assert.Equal(t, float64(0), retP.AverageRating)
+}
+
+func TestSchemaStatus(t *testing.T) {
+	if db.Driver() == db.DriverSQLite {
+		t.Skip("migrations are written for Postgres")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*prs_testing.DefaultTestTimeout)
+	defer cancel()
+
+	// testing DB is migrated by the setup, migrating it again is no-op
+	applied, err := db.ApplyMigrations(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	status, err := db.GetSchemaStatus(ctx)
+	require.NoError(t, err)
+	require.NoError(t, status.Err())
+	assert.Equal(t, status.Latest, status.Current)
+	assert.Empty(t, status.Pending)
+	assert.Empty(t, status.Drift)
+}
+
+func TestEntStoreConformance(t *testing.T) {
+	storetest.Run(t, db.NewEntStore(client))
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+)
+
+// deadlineDriver bounds statements of each transaction by the deadline of its context (e.g., deadline of the gRPC request),
+// so Postgres aborts them instead of holding row locks for a client, which is no longer waiting. Statements outside
+// of transactions are cancelled by the SQL driver once their context is done.
+type deadlineDriver struct {
+	*entsql.Driver
+}
+
+// newDeadlineDriver wraps the driver, so statement timeout of each transaction follows its context.
+func newDeadlineDriver(drv *entsql.Driver) dialect.Driver {
+	return &deadlineDriver{Driver: drv}
+}
+
+// Tx starts a transaction, whose statements are bounded by the deadline of the context.
+func (d *deadlineDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	return d.BeginTx(ctx, nil)
+}
+
+// BeginTx starts a transaction with the provided options, whose statements are bounded by the deadline of the context.
+func (d *deadlineDriver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	timeout, ok, err := statementTimeout(ctx, cfg.StatementTimeout)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := d.Driver.BeginTx(ctx, opts)
+	if err != nil || !ok {
+		return tx, err
+	}
+	// SET LOCAL lasts until the end of the transaction, session timeout applies to the following ones again
+	if err = tx.Exec(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds()), []any{}, nil); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to set statement timeout: %w", err), tx.Rollback())
+	}
+	return tx, nil
+}
+
+// statementTimeout returns timeout of statements, which follows the deadline of the context. Returns false, if the
+// context has no deadline or the configured timeout is shorter, i.e., the timeout of the session applies.
+func statementTimeout(ctx context.Context, configured time.Duration) (time.Duration, bool, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false, nil
+	}
+	remaining := time.Until(deadline)
+	// zero timeout would disable the timeout in Postgres
+	if remaining < time.Millisecond {
+		return 0, false, context.DeadlineExceeded
+	}
+	if configured > 0 && configured <= remaining {
+		return 0, false, nil
+	}
+	return remaining, true, nil
+}
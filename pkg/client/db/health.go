@@ -0,0 +1,28 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/eroshiva/cloudtalk/internal/ent"
+)
+
+// schemaMigrated reports whether the DB schema was migrated by this process.
+var schemaMigrated atomic.Bool
+
+// Ping verifies that the DB is reachable and able to execute statements.
+func Ping(ctx context.Context, client *ent.Client) error {
+	if _, err := client.ExecContext(ctx, "SELECT 1"); err != nil {
+		return fmt.Errorf("failed to ping DB: %w", err)
+	}
+	return nil
+}
+
+// MigrationStatus returns an error, unless the DB schema was successfully migrated at startup.
+func MigrationStatus() error {
+	if !schemaMigrated.Load() {
+		return fmt.Errorf("DB schema is not migrated")
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/eroshiva/cloudtalk/internal/ent"
+	"github.com/eroshiva/cloudtalk/internal/ent/idempotencykey"
+)
+
+const (
+	// operation scopes, so the same idempotency key used for different operations doesn't collide
+	operationCreateProduct = "create-product:"
+	operationCreateReview  = "create-review:"
+
+	// expired keys are purged in batches, so the purge doesn't hold locks of many rows at once
+	idempotencyKeyPurgeBatch = 1000
+)
+
+// errIdempotencyKeyExists is reported (wrapped), when the idempotency key was stored by a concurrent request
+// in the meantime, i.e., the request is a replay.
+var errIdempotencyKeyExists = errors.New("idempotency key already exists")
+
+// ErrIdempotencyKeyReused is reported (wrapped), when the idempotency key is reused with a request different from
+// the one, which created the resource.
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used with a different request")
+
+// requestHash returns hash of the fields of the create request.
+func requestHash(fields ...string) string {
+	h := sha256.New()
+	for _, f := range fields {
+		// length prefix keeps the fields apart, i.e., ("ab", "c") and ("a", "bc") differ
+		_, _ = fmt.Fprintf(h, "%d:%s", len(f), f)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// productRequestHash returns hash of the request creating Product resource.
+func productRequestHash(name, description, price string) string {
+	return requestHash(name, description, price)
+}
+
+// reviewRequestHash returns hash of the request creating Review resource.
+func reviewRequestHash(name, lastName, text string, rating int32, productID string) string {
+	return requestHash(name, lastName, text, strconv.Itoa(int(rating)), productID)
+}
+
+// verifyRequestHash reports, whether the idempotency key is reused with a different request. Keys stored before
+// the hashes were introduced are not verified.
+func verifyRequestHash(key, storedHash, hash string) error {
+	if storedHash != "" && storedHash != hash {
+		return fmt.Errorf("%w (%s)", ErrIdempotencyKeyReused, key)
+	}
+	return nil
+}
+
+// getIdempotentResourceID returns ID of the resource created with the idempotency key by the request with the hash.
+// Empty string is returned when the key is unknown or has already expired.
+func getIdempotentResourceID(ctx context.Context, client *ent.Client, key, hash string) (string, error) {
+	k, err := client.IdempotencyKey.Query().
+		Where(idempotencykey.ID(key), idempotencykey.ExpiresAtGT(time.Now())).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to retrieve idempotency key (%s)", key)
+		return "", err
+	}
+	if err = verifyRequestHash(key, k.RequestHash, hash); err != nil {
+		zlog.Warn().Ctx(ctx).Err(err).Msg("Idempotency key is reused with a different request")
+		return "", err
+	}
+	return k.ResourceID, nil
+}
+
+// saveIdempotencyKeyTx stores idempotency key together with the hash of the request and the ID of the created resource
+// during the transaction. Expired key, which was not purged yet, is replaced. Key stored by a concurrent request
+// is reported with errIdempotencyKeyExists. Does not commit transaction!
+func saveIdempotencyKeyTx(ctx context.Context, tx *ent.Tx, key, hash, resourceID string) error {
+	_, err := tx.IdempotencyKey.Delete().
+		Where(idempotencykey.ID(key), idempotencykey.ExpiresAtLTE(time.Now())).
+		Exec(ctx)
+	if err != nil {
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to remove expired idempotency key (%s)", key)
+		return err
+	}
+	_, err = tx.IdempotencyKey.Create().
+		SetID(key).
+		SetRequestHash(hash).
+		SetResourceID(resourceID).
+		SetExpiresAt(time.Now().Add(cfg.IdempotencyKeyTTL)).
+		Save(ctx)
+	if ent.IsConstraintError(err) {
+		// only constraint of the idempotency key table is its primary key
+		return fmt.Errorf("%w (%s): %w", errIdempotencyKeyExists, key, err)
+	}
+	if err != nil {
+		zlog.Err(err).Ctx(ctx).Msgf("Failed to store idempotency key (%s)", key)
+		return err
+	}
+	return nil
+}
+
+// PurgeExpiredIdempotencyKeys removes expired idempotency keys. Returns the number of removed keys.
+func PurgeExpiredIdempotencyKeys(ctx context.Context, client *ent.Client) (int, error) {
+	purged := 0
+	for {
+		ids, err := client.IdempotencyKey.Query().
+			Where(idempotencykey.ExpiresAtLTE(time.Now())).
+			Limit(idempotencyKeyPurgeBatch).
+			IDs(ctx)
+		if err != nil {
+			zlog.Err(err).Ctx(ctx).Msgf("Failed to retrieve expired idempotency keys")
+			return purged, err
+		}
+		if len(ids) == 0 {
+			return purged, nil
+		}
+		n, err := client.IdempotencyKey.Delete().Where(idempotencykey.IDIn(ids...)).Exec(ctx)
+		if err != nil {
+			zlog.Err(err).Ctx(ctx).Msgf("Failed to purge expired idempotency keys")
+			return purged, err
+		}
+		purged += n
+		if len(ids) < idempotencyKeyPurgeBatch {
+			return purged, nil
+		}
+	}
+}
+
+// PurgeIdempotencyKeysPeriodically purges expired idempotency keys of the store in intervals of
+// IdempotencyKeyPurgeInterval until the context is cancelled.
+func PurgeIdempotencyKeysPeriodically(ctx context.Context, store Store) {
+	if cfg.IdempotencyKeyPurgeInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(cfg.IdempotencyKeyPurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		n, err := store.PurgeExpiredIdempotencyKeys(ctx)
+		if err != nil {
+			// keys are purged with the next tick, expired keys are ignored in the meantime
+			zlog.Warn().Err(err).Msg("Failed to purge expired idempotency keys")
+			continue
+		}
+		zlog.Debug().Msgf("Purged %d expired idempotency key(s)", n)
+	}
+}
+
+// CreateProductIdempotent creates Product resource unless it was already created with the same idempotency key.
+// In such case, originally created Product resource is returned and the returned flag is false.
+// Key reused with a different request is rejected with ErrIdempotencyKeyReused.
+// When the idempotency key is empty, it behaves as CreateProduct.
+func CreateProductIdempotent(ctx context.Context, client *ent.Client, idempotencyKey, name, description, price string) (
+	*ent.Product, bool, error,
+) {
+	if idempotencyKey == "" {
+		p, err := CreateProduct(ctx, client, name, description, price)
+		return p, err == nil, err
+	}
+	key := operationCreateProduct + idempotencyKey
+	hash := productRequestHash(name, description, price)
+
+	// checking whether the product was already created
+	p, err := getProductByIdempotencyKey(ctx, client, key, hash)
+	if err != nil || p != nil {
+		return p, false, err
+	}
+
+	p, err = createProduct(ctx, client, key, name, description, price)
+	if errors.Is(err, errIdempotencyKeyExists) {
+		// concurrent request with the same key has won the race, returning its product
+		zlog.Debug().Ctx(ctx).Msgf("Product with idempotency key (%s) was created concurrently", idempotencyKey)
+		p, err = getProductByIdempotencyKey(ctx, client, key, hash)
+		if err == nil && p == nil {
+			// key has expired in the meantime
+			err = fmt.Errorf("product created with idempotency key (%s) was not found", idempotencyKey)
+		}
+		return p, false, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return p, true, nil
+}
+
+// getProductByIdempotencyKey retrieves Product resource created with the idempotency key by the request with the hash.
+// Returns nil, if there is none.
+func getProductByIdempotencyKey(ctx context.Context, client *ent.Client, key, hash string) (*ent.Product, error) {
+	id, err := getIdempotentResourceID(ctx, client, key, hash)
+	if err != nil || id == "" {
+		return nil, err
+	}
+	zlog.Debug().Ctx(ctx).Msgf("Product (%s) was already created with idempotency key (%s)", id, key)
+	return GetProductByID(ctx, client, id)
+}
+
+// CreateReviewIdempotent creates Review resource unless it was already created with the same idempotency key.
+// In such case, originally created Review resource is returned and the returned flag is false.
+// Key reused with a different request is rejected with ErrIdempotencyKeyReused.
+// When the idempotency key is empty, it behaves as CreateReview.
+func CreateReviewIdempotent(ctx context.Context, client *ent.Client, idempotencyKey, name, lastName, text string, rating int32,
+	productID string,
+) (*ent.Review, bool, error) {
+	if idempotencyKey == "" {
+		r, err := CreateReview(ctx, client, name, lastName, text, rating, productID)
+		return r, err == nil, err
+	}
+	key := operationCreateReview + idempotencyKey
+	hash := reviewRequestHash(name, lastName, text, rating, productID)
+
+	// checking whether the review was already created
+	r, err := getReviewByIdempotencyKey(ctx, client, key, hash)
+	if err != nil || r != nil {
+		return r, false, err
+	}
+
+	r, err = createReview(ctx, client, key, name, lastName, text, rating, productID)
+	if errors.Is(err, errIdempotencyKeyExists) {
+		// concurrent request with the same key has won the race, returning its review
+		zlog.Debug().Ctx(ctx).Msgf("Review with idempotency key (%s) was created concurrently", idempotencyKey)
+		r, err = getReviewByIdempotencyKey(ctx, client, key, hash)
+		if err == nil && r == nil {
+			// key has expired in the meantime
+			err = fmt.Errorf("review created with idempotency key (%s) was not found", idempotencyKey)
+		}
+		return r, false, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return r, true, nil
+}
+
+// getReviewByIdempotencyKey retrieves Review resource created with the idempotency key by the request with the hash.
+// Returns nil, if there is none.
+func getReviewByIdempotencyKey(ctx context.Context, client *ent.Client, key, hash string) (*ent.Review, error) {
+	id, err := getIdempotentResourceID(ctx, client, key, hash)
+	if err != nil || id == "" {
+		return nil, err
+	}
+	zlog.Debug().Ctx(ctx).Msgf("Review (%s) was already created with idempotency key (%s)", id, key)
+	return GetReviewByID(ctx, client, id)
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHash(t *testing.T) {
+	assert.Equal(t, productRequestHash("name", "description", "1.00"), productRequestHash("name", "description", "1.00"))
+	assert.NotEqual(t, productRequestHash("name", "description", "1.00"), productRequestHash("name", "description", "2.00"))
+	// fields are kept apart
+	assert.NotEqual(t, requestHash("ab", "c"), requestHash("a", "bc"))
+
+	require.NoError(t, verifyRequestHash("key", "", "hash")) // stored before the hashes were introduced
+	require.NoError(t, verifyRequestHash("key", "hash", "hash"))
+	require.ErrorIs(t, verifyRequestHash("key", "hash", "other"), ErrIdempotencyKeyReused)
+}
+
+func TestPurgeExpiredIdempotencyKeys(t *testing.T) {
+	defer Configure(cfg)
+
+	c := DefaultConfig()
+	c.Driver = DriverSQLite
+	c.IdempotencyKeyTTL = time.Millisecond
+	Configure(c)
+	client, err := RunSchemaMigration()
+	require.NoError(t, err)
+	entStore := NewEntStore(client)
+	t.Cleanup(func() {
+		assert.NoError(t, entStore.Close())
+	})
+
+	for name, store := range map[string]Store{"memory": NewMemoryStore(), "ent": entStore} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			_, created, err := store.CreateProductIdempotent(ctx, "product-key", "myAwesomeProduct", "Product description", "19.90")
+			require.NoError(t, err)
+			require.True(t, created)
+			time.Sleep(5 * time.Millisecond)
+
+			n, err := store.PurgeExpiredIdempotencyKeys(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 1, n)
+			n, err = store.PurgeExpiredIdempotencyKeys(ctx)
+			require.NoError(t, err)
+			assert.Zero(t, n)
+
+			// purged key may be used by another request
+			_, created, err = store.CreateProductIdempotent(ctx, "product-key", "myOtherProduct", "Product description", "19.90")
+			require.NoError(t, err)
+			assert.True(t, created)
+		})
+	}
+}
+
+func TestExpiredIdempotencyKeyIsReplaced(t *testing.T) {
+	defer Configure(cfg)
+
+	c := DefaultConfig()
+	c.Driver = DriverSQLite
+	c.IdempotencyKeyTTL = time.Millisecond
+	Configure(c)
+	client, err := RunSchemaMigration()
+	require.NoError(t, err)
+	entStore := NewEntStore(client)
+	t.Cleanup(func() {
+		assert.NoError(t, entStore.Close())
+	})
+
+	for name, store := range map[string]Store{"memory": NewMemoryStore(), "ent": entStore} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			p, created, err := store.CreateProductIdempotent(ctx, "expiring-key", "myAwesomeProduct", "Product description", "19.90")
+			require.NoError(t, err)
+			require.True(t, created)
+			r, created, err := store.CreateReviewIdempotent(ctx, "expiring-key", "John", "Doe", "Product is good!", 5, p.ID)
+			require.NoError(t, err)
+			require.True(t, created)
+			time.Sleep(5 * time.Millisecond)
+
+			// expired keys are not purged yet, they are replaced by the new requests
+			other, created, err := store.CreateProductIdempotent(ctx, "expiring-key", "myOtherProduct", "Product description", "19.90")
+			require.NoError(t, err)
+			require.True(t, created)
+			assert.NotEqual(t, p.ID, other.ID)
+			otherReview, created, err := store.CreateReviewIdempotent(ctx, "expiring-key", "John", "Doe", "Product is good!", 5, p.ID)
+			require.NoError(t, err)
+			require.True(t, created)
+			assert.NotEqual(t, r.ID, otherReview.ID)
+		})
+	}
+}
+
+func TestIdempotencyKeyConflict(t *testing.T) {
+	defer Configure(cfg)
+
+	c := DefaultConfig()
+	c.Driver = DriverSQLite
+	Configure(c)
+	client, err := RunSchemaMigration()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, client.Close())
+	})
+	ctx := context.Background()
+	p, err := CreateProduct(ctx, client, "myAwesomeProduct", "Product description", "19.90")
+	require.NoError(t, err)
+
+	// key stored by a concurrent request is reported as such
+	tx, err := client.Tx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, saveIdempotencyKeyTx(ctx, tx, "key", "hash", p.ID))
+	require.NoError(t, tx.Commit())
+	tx, err = client.Tx(ctx)
+	require.NoError(t, err)
+	err = saveIdempotencyKeyTx(ctx, tx, "key", "hash", p.ID)
+	require.ErrorIs(t, err, errIdempotencyKeyExists)
+	require.NoError(t, tx.Rollback())
+}
@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/eroshiva/cloudtalk/internal/ent"
+	"github.com/eroshiva/cloudtalk/internal/ent/product"
+	"github.com/lib/pq"
+)
+
+const (
+	// LockingRowLock locks the product row with "SELECT ... FOR UPDATE" until the end of the transaction.
+	LockingRowLock = "row_lock"
+	// LockingAdvisoryLock takes transaction-level advisory lock keyed on the product ID, the product row itself
+	// stays unlocked for other (e.g., product editing) transactions.
+	LockingAdvisoryLock = "advisory_lock"
+	// LockingOptimistic takes no lock, average rating is updated only if the version of the product is unchanged
+	// since it was read. Otherwise, the whole transaction is retried.
+	LockingOptimistic = "optimistic"
+	// LockingSerializable takes no lock and runs the transaction with SERIALIZABLE isolation, the transaction
+	// is retried on serialization failure.
+	LockingSerializable = "serializable"
+
+	defaultMaxTxRetries = 10
+	// backoff of the retried transactions, a random jitter is added to it
+	initialTxRetryBackoff = 5 * time.Millisecond
+	maxTxRetryBackoff     = 200 * time.Millisecond
+
+	// Postgres error codes of transactions, which may succeed when retried
+	pqSerializationFailure = "40001"
+	pqDeadlockDetected     = "40P01"
+)
+
+// errConcurrentUpdate is reported, when average rating was updated by a concurrent transaction since it was read.
+var errConcurrentUpdate = errors.New("average rating was updated concurrently")
+
+// validateLockingStrategy reports unknown strategy or strategy, which is not supported by the driver.
+func validateLockingStrategy(strategy, driver string) error {
+	switch strategy {
+	case LockingRowLock, LockingOptimistic:
+		return nil
+	case LockingAdvisoryLock, LockingSerializable:
+		if driver != DriverPostgres {
+			return fmt.Errorf("locking_strategy %s is supported only with %s driver", strategy, DriverPostgres)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown locking_strategy %q, expected %s, %s, %s or %s",
+			strategy, LockingRowLock, LockingAdvisoryLock, LockingOptimistic, LockingSerializable)
+	}
+}
+
+// LockingStrategy returns the configured strategy of average rating updates.
+func LockingStrategy() string {
+	return cfg.LockingStrategy
+}
+
+// SetLockingStrategy changes strategy of average rating updates, e.g., to compare the strategies in benchmarks.
+// It must not be called concurrently with changes of reviews.
+func SetLockingStrategy(strategy string) error {
+	if err := validateLockingStrategy(strategy, cfg.Driver); err != nil {
+		return err
+	}
+	cfg.LockingStrategy = strategy
+	return nil
+}
+
+// runRatingTx runs fn within a transaction, which changes reviews and updates average rating of their products.
+// Transaction, which conflicts with a concurrent one (i.e., optimistic update, serialization failure or deadlock),
+// is retried from the start with a jittered exponential backoff for up to MaxTxRetries times.
+func runRatingTx(ctx context.Context, client *ent.Client, operation string, fn func(tx *ent.Tx) error) error {
+	var opts *sql.TxOptions
+	if cfg.LockingStrategy == LockingSerializable {
+		opts = &sql.TxOptions{Isolation: sql.LevelSerializable}
+	}
+	backoff := initialTxRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := runTx(ctx, client, operation, opts, fn)
+		if err == nil || !retryableTxError(err) || attempt > cfg.MaxTxRetries {
+			return err
+		}
+		txRetries.WithLabelValues(operation).Inc()
+		zlog.Debug().Ctx(ctx).Err(err).Msgf("Transaction %s conflicts with a concurrent one, retrying (attempt %d)", operation, attempt)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (retry is cancelled: %w)", err, ctx.Err())
+		case <-time.After(backoff/2 + rand.N(backoff)): //nolint:gosec // jitter doesn't have to be cryptographically secure
+		}
+		backoff = min(2*backoff, maxTxRetryBackoff)
+	}
+}
+
+// runTx runs fn within a transaction, which is committed unless fn fails.
+func runTx(ctx context.Context, client *ent.Client, operation string, opts *sql.TxOptions, fn func(tx *ent.Tx) error) error {
+	tx, err := beginTx(ctx, client, operation, opts)
+	if err != nil {
+		zlog.Error().Ctx(ctx).Err(err).Msgf("Failed to create transaction")
+		return err
+	}
+	if err = fn(tx); err != nil {
+		return rollback(tx, err)
+	}
+	if err = tx.Commit(); err != nil {
+		zlog.Error().Ctx(ctx).Err(err).Msgf("Failed to commit transaction")
+		return err
+	}
+	return nil
+}
+
+// retryableTxError reports whether the transaction failed only due to a concurrent one, thus it may succeed when retried.
+func retryableTxError(err error) bool {
+	if errors.Is(err, errConcurrentUpdate) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == pqSerializationFailure || pqErr.Code == pqDeadlockDetected
+	}
+	return false
+}
+
+// lockProductsForRatingTx guards average rating of Product resources with provided IDs against concurrent updates
+// for the duration of the transaction. Products are locked in a stable order to avoid deadlocks between concurrent
+// batches. Returns set of IDs of existing Product resources. Does not commit transaction!
+func lockProductsForRatingTx(ctx context.Context, tx *ent.Tx, ids []string) (map[string]bool, error) {
+	start := time.Now()
+	q, err := guardRatingTx(ctx, tx, tx.Product.Query().
+		Where(product.IDIn(ids...)).
+		Order(ent.Asc(product.FieldID)), ids)
+	if err != nil {
+		return nil, err
+	}
+	found, err := q.IDs(ctx)
+	observeLockWait(start)
+	if err != nil {
+		zlog.Error().Ctx(ctx).Err(err).Msgf("Failed to lock products")
+		return nil, err
+	}
+	existing := make(map[string]bool, len(found))
+	for _, id := range found {
+		existing[id] = true
+	}
+	return existing, nil
+}
+
+// guardRatingTx guards average rating of the products against concurrent updates until the end of the transaction,
+// as the configured strategy does. Returns query of the products, which locks their rows, if the strategy needs it.
+// Optimistic and serializable strategies take no lock, conflicts are detected once the rating is updated or committed.
+func guardRatingTx(ctx context.Context, tx *ent.Tx, q *ent.ProductQuery, ids []string) (*ent.ProductQuery, error) {
+	switch cfg.LockingStrategy {
+	case LockingRowLock:
+		// pessimistic locking => lock the product rows for the duration of this transaction
+		return ProductsForUpdate(q), nil
+	case LockingAdvisoryLock:
+		return q, advisoryLockTx(ctx, tx, ids)
+	default:
+		return q, nil
+	}
+}
+
+// advisoryLockTx takes transaction-level advisory locks keyed on the product IDs, they are released at the end
+// of the transaction. Locks are taken in order of their keys, so concurrent transactions don't deadlock.
+func advisoryLockTx(ctx context.Context, tx *ent.Tx, ids []string) error {
+	keys := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, advisoryLockKey(id))
+	}
+	slices.Sort(keys)
+	for _, key := range slices.Compact(keys) {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", key); err != nil {
+			return fmt.Errorf("failed to take advisory lock: %w", err)
+		}
+	}
+	return nil
+}
+
+// advisoryLockKey returns key of the advisory lock of the product.
+func advisoryLockKey(productID string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(productID))
+	return int64(h.Sum64()) //nolint:gosec // overflow is intended, the key only has to be stable
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryableTxError(t *testing.T) {
+	assert.True(t, retryableTxError(fmt.Errorf("product with ID (1): %w", errConcurrentUpdate)))
+	assert.True(t, retryableTxError(fmt.Errorf("commit: %w", &pq.Error{Code: pqSerializationFailure})))
+	assert.True(t, retryableTxError(&pq.Error{Code: pqDeadlockDetected}))
+	assert.False(t, retryableTxError(&pq.Error{Code: "23505"})) // unique violation
+	assert.False(t, retryableTxError(errors.New("connection refused")))
+}
+
+func TestAdvisoryLockKey(t *testing.T) {
+	// key is stable, so all replicas of the service lock the same product with the same key
+	assert.Equal(t, advisoryLockKey("product-1"), advisoryLockKey("product-1"))
+	assert.NotEqual(t, advisoryLockKey("product-1"), advisoryLockKey("product-2"))
+}
+
+func TestSetLockingStrategy(t *testing.T) {
+	defer Configure(cfg)
+
+	c := DefaultConfig()
+	c.Driver = DriverSQLite
+	Configure(c)
+	require.Equal(t, LockingRowLock, LockingStrategy())
+	require.NoError(t, SetLockingStrategy(LockingOptimistic))
+	assert.Equal(t, LockingOptimistic, LockingStrategy())
+
+	// unsupported strategy is rejected and the current one is kept
+	require.ErrorContains(t, SetLockingStrategy(LockingSerializable), "supported only with postgres driver")
+	assert.Equal(t, LockingOptimistic, LockingStrategy())
+}
@@ -0,0 +1,474 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/eroshiva/cloudtalk/internal/ent"
+	"github.com/google/uuid"
+)
+
+// memoryStore is a thread-safe Store, which keeps resources in memory. Changes hold the write lock of the whole
+// store, thus they are serialized the same way as transactions locking the product rows in the DB.
+// Stored resources are never handed out, callers get copies.
+type memoryStore struct {
+	mu sync.RWMutex
+	// products and reviews hold resources without their edges
+	products map[string]*ent.Product
+	reviews  map[string]*ent.Review
+	// productIDs keeps products in order of their creation
+	productIDs []string
+	// productReviews keeps IDs of reviews of each product in order of their creation
+	productReviews map[string][]string
+	// reviewProduct holds ID of the product of each review, it is empty once the product is removed
+	reviewProduct map[string]string
+	keys          map[string]memoryIdempotencyKey
+}
+
+// memoryIdempotencyKey holds ID of the resource created with the idempotency key and hash of the create request.
+type memoryIdempotencyKey struct {
+	resourceID  string
+	requestHash string
+	expiresAt   time.Time
+}
+
+// NewMemoryStore creates an empty Store, which keeps resources in memory, e.g., for unit tests.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		products:       make(map[string]*ent.Product),
+		reviews:        make(map[string]*ent.Review),
+		productReviews: make(map[string][]string),
+		reviewProduct:  make(map[string]string),
+		keys:           make(map[string]memoryIdempotencyKey),
+	}
+}
+
+// productNotFoundError returns error reported for missing Product resource.
+func productNotFoundError(id string) error {
+	return fmt.Errorf("product with ID (%s) is %w", id, ErrNotFound)
+}
+
+// reviewNotFoundError returns error reported for missing Review resource.
+func reviewNotFoundError(id string) error {
+	return fmt.Errorf("review with ID (%s) is %w", id, ErrNotFound)
+}
+
+// product returns a copy of the product, which carries copies of its reviews. Caller must hold the lock.
+func (s *memoryStore) product(id string) *ent.Product {
+	p := *s.products[id]
+	p.Edges.Reviews = make([]*ent.Review, 0, len(s.productReviews[id]))
+	for _, reviewID := range s.productReviews[id] {
+		r := *s.reviews[reviewID]
+		p.Edges.Reviews = append(p.Edges.Reviews, &r)
+	}
+	return &p
+}
+
+// review returns a copy of the review, which carries copy of its product (without reviews). Caller must hold the lock.
+func (s *memoryStore) review(id string) *ent.Review {
+	r := *s.reviews[id]
+	if productID := s.reviewProduct[id]; productID != "" {
+		p := *s.products[productID]
+		r.Edges.Product = &p
+	}
+	return &r
+}
+
+// updateAverageRating recalculates average rating of the product. Caller must hold the write lock.
+func (s *memoryStore) updateAverageRating(productID string) {
+	var totalRating int32
+	for _, id := range s.productReviews[productID] {
+		totalRating += s.reviews[id].Rating
+	}
+	newAverage := 0.0
+	if reviewCount := len(s.productReviews[productID]); reviewCount > 0 {
+		newAverage = float64(totalRating) / float64(reviewCount)
+	}
+	s.products[productID].AverageRating = newAverage
+}
+
+// resourceID returns ID of the resource created with the idempotency key by the request with the hash. Empty string
+// is returned when the key is unknown or has already expired. Caller must hold the lock.
+func (s *memoryStore) resourceID(key, hash string) (string, error) {
+	k, ok := s.keys[key]
+	if !ok || !k.expiresAt.After(time.Now()) {
+		return "", nil
+	}
+	if err := verifyRequestHash(key, k.requestHash, hash); err != nil {
+		return "", err
+	}
+	return k.resourceID, nil
+}
+
+// saveIdempotencyKey stores idempotency key. Caller must hold the write lock.
+func (s *memoryStore) saveIdempotencyKey(key, hash, resourceID string) {
+	s.keys[key] = memoryIdempotencyKey{resourceID: resourceID, requestHash: hash, expiresAt: time.Now().Add(cfg.IdempotencyKeyTTL)}
+}
+
+// CreateProduct creates Product resource.
+func (s *memoryStore) CreateProduct(ctx context.Context, name, description, price string) (*ent.Product, error) {
+	p, _, err := s.CreateProductIdempotent(ctx, "", name, description, price)
+	return p, err
+}
+
+// CreateProductIdempotent creates Product resource unless it was already created with the same idempotency key.
+func (s *memoryStore) CreateProductIdempotent(ctx context.Context, idempotencyKey, name, description, price string) (
+	*ent.Product, bool, error,
+) {
+	if err := validateProduct(ctx, name, description, price); err != nil {
+		return nil, false, err
+	}
+	key := operationCreateProduct + idempotencyKey
+
+	hash := productRequestHash(name, description, price)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if idempotencyKey != "" {
+		id, err := s.resourceID(key, hash)
+		if err != nil {
+			return nil, false, err
+		}
+		if id != "" {
+			if _, ok := s.products[id]; !ok {
+				return nil, false, productNotFoundError(id)
+			}
+			return s.product(id), false, nil
+		}
+	}
+
+	id := productPrefix + uuid.NewString()
+	s.products[id] = &ent.Product{ID: id, Name: name, Description: description, Price: price}
+	s.productIDs = append(s.productIDs, id)
+	if idempotencyKey != "" {
+		s.saveIdempotencyKey(key, hash, id)
+	}
+	p := s.product(id)
+	p.Edges.Reviews = nil // created product doesn't have any reviews yet
+	return p, true, nil
+}
+
+// GetProductByID retrieves Product resource by its ID.
+func (s *memoryStore) GetProductByID(_ context.Context, id string) (*ent.Product, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.products[id]; !ok {
+		return nil, productNotFoundError(id)
+	}
+	return s.product(id), nil
+}
+
+// GetProductsByIDs retrieves multiple Product resources.
+func (s *memoryStore) GetProductsByIDs(_ context.Context, ids []string) ([]ProductResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	results := make([]ProductResult, len(ids))
+	for i, id := range ids {
+		if _, ok := s.products[id]; ok {
+			results[i].Product = s.product(id)
+		} else {
+			results[i].Err = productNotFoundError(id)
+		}
+	}
+	return results, nil
+}
+
+// ListProducts retrieves all Product resources in order of their creation.
+func (s *memoryStore) ListProducts(_ context.Context) ([]*ent.Product, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ps := make([]*ent.Product, 0, len(s.productIDs))
+	for _, id := range s.productIDs {
+		ps = append(ps, s.product(id))
+	}
+	return ps, nil
+}
+
+// ListTopProducts retrieves at most limit Product resources with the highest number of reviews.
+func (s *memoryStore) ListTopProducts(ctx context.Context, limit int) ([]*ent.Product, error) {
+	ps, err := s.ListProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(ps, func(i, j int) bool {
+		return len(ps[i].Edges.Reviews) > len(ps[j].Edges.Reviews)
+	})
+	return ps[:min(limit, len(ps))], nil
+}
+
+// EditProduct updates all provided non-empty fields of Product resource.
+func (s *memoryStore) EditProduct(_ context.Context, id, name, description, price string) (*ent.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.products[id]
+	if !ok {
+		return nil, productNotFoundError(id)
+	}
+	if name != "" {
+		p.Name = name
+	}
+	if description != "" {
+		p.Description = description
+	}
+	if price != "" {
+		p.Price = price
+	}
+	return s.product(id), nil
+}
+
+// DeleteProductByID removes Product resource, its reviews are kept without the product.
+func (s *memoryStore) DeleteProductByID(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.products[id]; !ok {
+		return nil
+	}
+	for _, reviewID := range s.productReviews[id] {
+		s.reviewProduct[reviewID] = ""
+	}
+	delete(s.productReviews, id)
+	delete(s.products, id)
+	s.productIDs = slices.DeleteFunc(s.productIDs, func(productID string) bool { return productID == id })
+	return nil
+}
+
+// CountProducts returns number of stored Product resources.
+func (s *memoryStore) CountProducts(_ context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.products), nil
+}
+
+// CreateReview creates Review resource and recalculates average rating of its product.
+func (s *memoryStore) CreateReview(ctx context.Context, name, lastName, text string, rating int32, productID string) (
+	*ent.Review, error,
+) {
+	r, _, err := s.CreateReviewIdempotent(ctx, "", name, lastName, text, rating, productID)
+	return r, err
+}
+
+// CreateReviewIdempotent creates Review resource unless it was already created with the same idempotency key.
+func (s *memoryStore) CreateReviewIdempotent(ctx context.Context, idempotencyKey, name, lastName, text string, rating int32,
+	productID string,
+) (*ent.Review, bool, error) {
+	if err := validateReview(ctx, name, lastName, text, rating, productID); err != nil {
+		return nil, false, err
+	}
+	key := operationCreateReview + idempotencyKey
+
+	hash := reviewRequestHash(name, lastName, text, rating, productID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if idempotencyKey != "" {
+		id, err := s.resourceID(key, hash)
+		if err != nil {
+			return nil, false, err
+		}
+		if id != "" {
+			if _, ok := s.reviews[id]; !ok {
+				return nil, false, reviewNotFoundError(id)
+			}
+			return s.review(id), false, nil
+		}
+	}
+	if _, ok := s.products[productID]; !ok {
+		return nil, false, productNotFoundError(productID)
+	}
+
+	id := s.createReview(ReviewInput{FirstName: name, LastName: lastName, Text: text, Rating: rating, ProductID: productID})
+	s.updateAverageRating(productID)
+	if idempotencyKey != "" {
+		s.saveIdempotencyKey(key, hash, id)
+	}
+	return s.review(id), true, nil
+}
+
+// createReview stores Review resource of the existing product. Caller must hold the write lock.
+func (s *memoryStore) createReview(in ReviewInput) string {
+	id := reviewPrefix + uuid.NewString()
+	s.reviews[id] = &ent.Review{ID: id, FirstName: in.FirstName, LastName: in.LastName, ReviewText: in.Text, Rating: in.Rating}
+	s.reviewProduct[id] = in.ProductID
+	s.productReviews[in.ProductID] = append(s.productReviews[in.ProductID], id)
+	return id
+}
+
+// CreateReviews creates multiple Review resources at once, average rating is recomputed once per affected product.
+func (s *memoryStore) CreateReviews(ctx context.Context, inputs []ReviewInput) ([]ReviewResult, error) {
+	results := make([]ReviewResult, len(inputs))
+	for i, in := range inputs {
+		results[i].Err = validateReview(ctx, in.FirstName, in.LastName, in.Text, in.Rating, in.ProductID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	created := make(map[int]string, len(inputs))
+	for i, in := range inputs {
+		if results[i].Err != nil {
+			continue
+		}
+		if _, ok := s.products[in.ProductID]; !ok {
+			results[i].Err = productNotFoundError(in.ProductID)
+			continue
+		}
+		created[i] = s.createReview(in)
+	}
+	for _, id := range created {
+		s.updateAverageRating(s.reviewProduct[id])
+	}
+	for i, id := range created {
+		results[i].Review = s.review(id)
+	}
+	return results, nil
+}
+
+// GetReviewByID retrieves Review resource by its ID together with its product.
+func (s *memoryStore) GetReviewByID(_ context.Context, id string) (*ent.Review, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.reviews[id]; !ok {
+		return nil, reviewNotFoundError(id)
+	}
+	return s.review(id), nil
+}
+
+// GetReviewsByProductID retrieves all Review resources of the product in order of their creation.
+func (s *memoryStore) GetReviewsByProductID(_ context.Context, productID string) ([]*ent.Review, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rs := make([]*ent.Review, 0, len(s.productReviews[productID]))
+	for _, id := range s.productReviews[productID] {
+		r := *s.reviews[id]
+		rs = append(rs, &r)
+	}
+	return rs, nil
+}
+
+// EditReview updates Review resource and recalculates average rating of its product.
+func (s *memoryStore) EditReview(_ context.Context, id, name, lastName, text string, rating int32) (*ent.Review, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.reviews[id]
+	if !ok {
+		return nil, reviewNotFoundError(id)
+	}
+	if name != "" {
+		r.FirstName = name
+	}
+	if lastName != "" {
+		r.LastName = lastName
+	}
+	if text != "" {
+		r.ReviewText = text
+	}
+	if rating >= 1 && rating <= 5 {
+		r.Rating = rating
+	}
+	if productID := s.reviewProduct[id]; productID != "" {
+		s.updateAverageRating(productID)
+	}
+	return s.review(id), nil
+}
+
+// DeleteReviewByID removes Review resource and recalculates average rating of the product.
+func (s *memoryStore) DeleteReviewByID(_ context.Context, id, productID string) (*ent.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.products[productID]; !ok {
+		return nil, productNotFoundError(productID)
+	}
+	if _, ok := s.reviews[id]; ok {
+		s.deleteReview(id)
+	}
+	s.updateAverageRating(productID)
+	p := s.product(productID)
+	p.Edges.Reviews = nil
+	return p, nil
+}
+
+// deleteReview removes Review resource. Caller must hold the write lock.
+func (s *memoryStore) deleteReview(id string) {
+	if productID := s.reviewProduct[id]; productID != "" {
+		s.productReviews[productID] = slices.DeleteFunc(s.productReviews[productID], func(reviewID string) bool {
+			return reviewID == id
+		})
+	}
+	delete(s.reviewProduct, id)
+	delete(s.reviews, id)
+}
+
+// DeleteReviewsByIDs removes multiple Review resources at once, average rating is recomputed once per affected product.
+func (s *memoryStore) DeleteReviewsByIDs(_ context.Context, ids []string) ([]ReviewResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := make([]ReviewResult, len(ids))
+	deleted := make(map[string]bool, len(ids))
+	affected := make(map[string]bool)
+	for i, id := range ids {
+		_, ok := s.reviews[id]
+		switch {
+		case deleted[id]:
+			results[i].Err = fmt.Errorf("review with ID (%s) is requested to be deleted more than once", id)
+		case !ok:
+			results[i].Err = reviewNotFoundError(id)
+		default:
+			results[i].Review = s.review(id)
+			if productID := s.reviewProduct[id]; productID != "" {
+				affected[productID] = true
+			}
+			s.deleteReview(id)
+			deleted[id] = true
+		}
+	}
+	for productID := range affected {
+		s.updateAverageRating(productID)
+	}
+	for i := range results {
+		if r := results[i].Review; r != nil && r.Edges.Product != nil {
+			p := *s.products[r.Edges.Product.ID]
+			r.Edges.Product = &p
+		}
+	}
+	return results, nil
+}
+
+// CountReviews returns number of stored Review resources.
+func (s *memoryStore) CountReviews(_ context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.reviews), nil
+}
+
+// Ping always succeeds, memory is always reachable.
+func (s *memoryStore) Ping(_ context.Context) error {
+	return nil
+}
+
+// MigrationStatus always succeeds, there is no schema to migrate.
+func (s *memoryStore) MigrationStatus() error {
+	return nil
+}
+
+// PurgeExpiredIdempotencyKeys removes expired idempotency keys.
+func (s *memoryStore) PurgeExpiredIdempotencyKeys(_ context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	purged := 0
+	for k, v := range s.keys {
+		if !v.expiresAt.After(now) {
+			delete(s.keys, k)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// Close does nothing, resources are released with the store.
+func (s *memoryStore) Close() error {
+	return nil
+}
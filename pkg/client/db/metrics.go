@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eroshiva/cloudtalk/internal/ent"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// metricsTimeout bounds the time spent on counting resources during a single scrape.
+	metricsTimeout = 5 * time.Second
+
+	// transaction outcomes
+	outcomeCommitted  = "committed"
+	outcomeRolledBack = "rolled_back"
+)
+
+var (
+	txDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_transaction_duration_seconds",
+		Help:    "Duration of DB transactions from their start until commit or rollback.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "outcome"})
+	lockWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "db_row_lock_wait_seconds",
+		Help:    "Time spent acquiring locks (row or advisory) of Product resources, whose average rating is being updated.",
+		Buckets: prometheus.DefBuckets,
+	})
+	txRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_transaction_retries_total",
+		Help: "Number of DB transactions retried due to a conflict with a concurrent transaction.",
+	}, []string{"operation"})
+
+	productsDesc = prometheus.NewDesc("products_count", "Number of Product resources stored in the DB.", nil, nil)
+	reviewsDesc  = prometheus.NewDesc("reviews_count", "Number of Review resources stored in the DB.", nil, nil)
+)
+
+// beginTx starts a new transaction, whose duration is observed under the provided operation name.
+func beginTx(ctx context.Context, client *ent.Client, operation string, opts *sql.TxOptions) (*ent.Tx, error) {
+	tx, err := client.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	tx.OnCommit(func(next ent.Committer) ent.Committer {
+		return ent.CommitFunc(func(ctx context.Context, tx *ent.Tx) error {
+			err := next.Commit(ctx, tx)
+			outcome := outcomeCommitted
+			if err != nil {
+				outcome = outcomeRolledBack
+			}
+			txDuration.WithLabelValues(operation, outcome).Observe(time.Since(start).Seconds())
+			return err
+		})
+	})
+	tx.OnRollback(func(next ent.Rollbacker) ent.Rollbacker {
+		return ent.RollbackFunc(func(ctx context.Context, tx *ent.Tx) error {
+			err := next.Rollback(ctx, tx)
+			txDuration.WithLabelValues(operation, outcomeRolledBack).Observe(time.Since(start).Seconds())
+			return err
+		})
+	})
+	return tx, nil
+}
+
+// observeLockWait records time spent acquiring locks since the provided start, unless the strategy takes no locks.
+func observeLockWait(start time.Time) {
+	if cfg.LockingStrategy == LockingRowLock || cfg.LockingStrategy == LockingAdvisoryLock {
+		lockWait.Observe(time.Since(start).Seconds())
+	}
+}
+
+// collector exposes DB-related metrics, i.e., transaction and lock timings together with number of stored resources.
+type collector struct {
+	store Store
+}
+
+// NewCollector returns Prometheus collector of DB-related metrics.
+// Number of Product and Review resources is counted in the store on each scrape.
+func NewCollector(store Store) prometheus.Collector {
+	return &collector{store: store}
+}
+
+// Describe sends descriptors of all metrics to the channel.
+func (col *collector) Describe(ch chan<- *prometheus.Desc) {
+	txDuration.Describe(ch)
+	lockWait.Describe(ch)
+	txRetries.Describe(ch)
+	ch <- productsDesc
+	ch <- reviewsDesc
+}
+
+// Collect counts resources in the DB and sends all metrics to the channel.
+func (col *collector) Collect(ch chan<- prometheus.Metric) {
+	txDuration.Collect(ch)
+	lockWait.Collect(ch)
+	txRetries.Collect(ch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), metricsTimeout)
+	defer cancel()
+	products, err := col.store.CountProducts(ctx)
+	if err != nil {
+		zlog.Warn().Err(err).Msg("Failed to count products")
+	} else {
+		ch <- prometheus.MustNewConstMetric(productsDesc, prometheus.GaugeValue, float64(products))
+	}
+	reviews, err := col.store.CountReviews(ctx)
+	if err != nil {
+		zlog.Warn().Err(err).Msg("Failed to count reviews")
+	} else {
+		ch <- prometheus.MustNewConstMetric(reviewsDesc, prometheus.GaugeValue, float64(reviews))
+	}
+}
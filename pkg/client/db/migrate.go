@@ -0,0 +1,407 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	entmigrate "github.com/eroshiva/cloudtalk/internal/ent/migrate"
+)
+
+// Modes of handling the DB schema at startup.
+const (
+	// MigrationsVerify refuses to start, unless all migrations are applied and none of them has changed since.
+	MigrationsVerify = "verify"
+	// MigrationsApply applies pending migrations at startup.
+	MigrationsApply = "apply"
+	// MigrationsIgnore starts even with outdated or drifted schema, problems are only logged.
+	MigrationsIgnore = "ignore"
+)
+
+const (
+	migrationsDir = "migrations"
+	// migrationLock is a name of the advisory lock, which serializes migrations started by multiple replicas.
+	migrationLock        = "product-reviews-migrations"
+	migrationLockTimeout = time.Minute
+	operatorVersion      = "product-reviews"
+
+	// revisions are stored in the same table as by atlas CLI connected to the public schema,
+	// so history of the already migrated DBs is preserved
+	revisionsSchema = "public"
+	revisionsTable  = "atlas_schema_revisions"
+)
+
+// migrationDir returns the migration directory embedded in the binary.
+func migrationDir() (migrate.Dir, error) {
+	entries, err := fs.ReadDir(entmigrate.Migrations, migrationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+	dir := &migrate.MemDir{}
+	for _, e := range entries {
+		content, err := fs.ReadFile(entmigrate.Migrations, path.Join(migrationsDir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embedded migration %s: %w", e.Name(), err)
+		}
+		if err = dir.WriteFile(e.Name(), content); err != nil {
+			return nil, err
+		}
+	}
+	return dir, nil
+}
+
+// ValidateMigrations verifies that the embedded migration files match their checksum file, i.e., none of them was
+// edited, added or removed without regenerating atlas.sum.
+func ValidateMigrations() error {
+	dir, err := migrationDir()
+	if err != nil {
+		return err
+	}
+	if err = migrate.Validate(dir); err != nil {
+		return fmt.Errorf("invalid migration directory: %w", err)
+	}
+	return nil
+}
+
+// SchemaStatus describes state of the DB schema compared to the embedded migrations.
+type SchemaStatus struct {
+	// Current is a version of the last applied migration, empty for a DB without migration history.
+	Current string
+	// Latest is a version of the last embedded migration.
+	Latest string
+	// Pending holds names of the migration files, which are not applied yet.
+	Pending []string
+	// Unknown holds versions of the applied migrations, which are not embedded in this binary (e.g., applied by a newer release).
+	Unknown []string
+	// Drift holds discrepancies between the migration history and the embedded migrations.
+	Drift []string
+}
+
+// Err returns an error, if the schema is behind the embedded migrations or has drifted from them.
+// Migrations unknown to this binary don't cause an error, since newer releases are expected to keep the schema compatible.
+func (s *SchemaStatus) Err() error {
+	var errs []error
+	if len(s.Drift) > 0 {
+		errs = append(errs, fmt.Errorf("DB schema has drifted: %s", strings.Join(s.Drift, "; ")))
+	}
+	if len(s.Pending) > 0 {
+		errs = append(errs, fmt.Errorf("DB schema is behind by %d migration(s): %s", len(s.Pending), strings.Join(s.Pending, ", ")))
+	}
+	return errors.Join(errs...)
+}
+
+// GetSchemaStatus connects to the DB and compares its migration history with the embedded migrations.
+func GetSchemaStatus(ctx context.Context) (*SchemaStatus, error) {
+	if err := requirePostgres(); err != nil {
+		return nil, err
+	}
+	drv, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer drv.Close() //nolint:errcheck // nothing was written
+	return schemaStatus(ctx, drv.DB())
+}
+
+// ApplyMigrations connects to the DB and applies pending migrations. Returns names of the applied migration files.
+func ApplyMigrations(ctx context.Context) ([]string, error) {
+	if err := requirePostgres(); err != nil {
+		return nil, err
+	}
+	drv, err := open()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := applyMigrations(ctx, drv.DB())
+	return applied, errors.Join(err, drv.Close())
+}
+
+// requirePostgres reports an error, if the configured DB is not Postgres, which the migrations are written for.
+func requirePostgres() error {
+	if cfg.Driver != DriverPostgres {
+		return fmt.Errorf("migrations are supported only with %s driver, %s schema is created at startup", DriverPostgres, cfg.Driver)
+	}
+	return nil
+}
+
+// schemaStatus compares migration history of the DB with the embedded migrations.
+func schemaStatus(ctx context.Context, db *sql.DB) (*SchemaStatus, error) {
+	dir, err := migrationDir()
+	if err != nil {
+		return nil, err
+	}
+	status := &SchemaStatus{}
+	if err = migrate.Validate(dir); err != nil {
+		// history can't be compared with the files, which don't match their checksums
+		status.Drift = append(status.Drift, fmt.Sprintf("embedded migration directory is invalid: %v", err))
+		return status, nil
+	}
+	files, err := dir.Files()
+	if err != nil {
+		return nil, err
+	}
+	if len(files) > 0 {
+		status.Latest = files[len(files)-1].Version()
+	}
+	sums, err := dir.Checksum()
+	if err != nil {
+		return nil, err
+	}
+
+	rrw := &revisions{conn: db}
+	revs, err := rrw.ReadRevisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[string]migrate.File, len(files))
+	for _, f := range files {
+		byVersion[f.Version()] = f
+	}
+	for _, r := range revs {
+		status.Current = r.Version
+		if r.Error != "" || r.Applied < r.Total {
+			status.Drift = append(status.Drift, fmt.Sprintf("migration %s is applied partially (%d of %d statements): %s", r.Version, r.Applied, r.Total, r.Error))
+		}
+		f, ok := byVersion[r.Version]
+		if !ok {
+			status.Unknown = append(status.Unknown, r.Version)
+			continue
+		}
+		if r.Type.Has(migrate.RevisionTypeBaseline) {
+			continue
+		}
+		if sum, err := sums.SumByName(f.Name()); err == nil && r.Hash != sum {
+			status.Drift = append(status.Drift, fmt.Sprintf("migration %s was changed after it was applied", f.Name()))
+		}
+	}
+
+	drv, err := postgres.Open(db)
+	if err != nil {
+		return nil, err
+	}
+	ex, err := migrate.NewExecutor(drv, dir, rrw)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := ex.Pending(ctx)
+	var nonLinear *migrate.HistoryNonLinearError
+	var notClean *migrate.NotCleanError
+	switch {
+	case errors.Is(err, migrate.ErrNoPendingFiles):
+	case errors.As(err, &nonLinear):
+		for _, f := range nonLinear.OutOfOrder {
+			status.Drift = append(status.Drift, fmt.Sprintf("migration %s was added out of order", f.Name()))
+		}
+		pending = nonLinear.Pending
+	case errors.As(err, &notClean):
+		status.Drift = append(status.Drift, fmt.Sprintf("DB has no migration history, but it is not empty (%s)", notClean.Reason))
+	case err != nil:
+		return nil, fmt.Errorf("failed to determine pending migrations: %w", err)
+	}
+	for _, f := range pending {
+		status.Pending = append(status.Pending, f.Name())
+	}
+	return status, nil
+}
+
+// applyMigrations applies pending migrations under the advisory lock, so concurrently started replicas
+// don't apply them twice. Each migration file is applied in its own transaction.
+func applyMigrations(ctx context.Context, db *sql.DB) ([]string, error) {
+	dir, err := migrationDir()
+	if err != nil {
+		return nil, err
+	}
+	drv, err := postgres.Open(db)
+	if err != nil {
+		return nil, err
+	}
+	locker, ok := drv.(schema.Locker)
+	if !ok {
+		return nil, fmt.Errorf("DB doesn't support advisory locks")
+	}
+	unlock, err := locker.Lock(ctx, migrationLock, migrationLockTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			zlog.Error().Err(err).Msg("Failed to release migration lock")
+		}
+	}()
+
+	rrw := &revisions{conn: db}
+	if err = rrw.create(ctx); err != nil {
+		return nil, err
+	}
+	ex, err := migrate.NewExecutor(drv, dir, rrw)
+	if err != nil {
+		return nil, err
+	}
+	// pending migrations are determined under the lock, so migrations applied by another replica are skipped
+	pending, err := ex.Pending(ctx)
+	if errors.Is(err, migrate.ErrNoPendingFiles) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine pending migrations: %w", err)
+	}
+
+	var applied []string
+	for _, f := range pending {
+		if err = applyMigration(ctx, db, dir, f); err != nil {
+			return applied, err
+		}
+		applied = append(applied, f.Name())
+	}
+	return applied, nil
+}
+
+// applyMigration applies the migration file together with its revision in a single transaction.
+func applyMigration(ctx context.Context, db *sql.DB, dir migrate.Dir, f migrate.File) error {
+	zlog.Info().Msgf("Applying migration %s", f.Name())
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	drv, err := postgres.Open(tx)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	ex, err := migrate.NewExecutor(drv, dir, &revisions{conn: tx}, migrate.WithOperatorVersion(operatorVersion))
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	if err = ex.Execute(ctx, f); err != nil {
+		return errors.Join(fmt.Errorf("failed to apply migration %s: %w", f.Name(), err), tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+// revisions stores migration history in the table used by atlas CLI.
+type revisions struct {
+	conn schema.ExecQuerier
+}
+
+// Ident returns identifier of the revision table, the table is not considered when checking whether the DB is empty.
+func (r *revisions) Ident() *migrate.TableIdent {
+	return &migrate.TableIdent{Schema: revisionsSchema, Name: revisionsTable}
+}
+
+// create creates the revision table, unless it exists already.
+func (r *revisions) create(ctx context.Context) error {
+	_, err := r.conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS "`+revisionsSchema+`"."`+revisionsTable+`" (
+  "version" character varying NOT NULL,
+  "description" character varying NOT NULL,
+  "type" bigint NOT NULL DEFAULT 2,
+  "applied" bigint NOT NULL DEFAULT 0,
+  "total" bigint NOT NULL DEFAULT 0,
+  "executed_at" timestamptz NOT NULL,
+  "execution_time" bigint NOT NULL,
+  "error" text NULL,
+  "error_stmt" text NULL,
+  "hash" character varying NOT NULL,
+  "partial_hashes" jsonb NULL,
+  "operator_version" character varying NOT NULL,
+  PRIMARY KEY ("version")
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create migration history table: %w", err)
+	}
+	return nil
+}
+
+const selectRevisions = `SELECT "version", "description", "type", "applied", "total", "executed_at", "execution_time",
+  COALESCE("error", ''), COALESCE("error_stmt", ''), "hash", COALESCE("partial_hashes", 'null'), "operator_version"
+FROM "` + revisionsSchema + `"."` + revisionsTable + `"`
+
+// ReadRevisions returns all revisions ordered by version. DB without the revision table has no revisions.
+func (r *revisions) ReadRevisions(ctx context.Context) ([]*migrate.Revision, error) {
+	rows, err := r.conn.QueryContext(ctx, `SELECT to_regclass('"`+revisionsSchema+`"."`+revisionsTable+`"') IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration history: %w", err)
+	}
+	var exists bool
+	if rows.Next() {
+		err = rows.Scan(&exists)
+	}
+	if err = errors.Join(err, rows.Close()); err != nil || !exists {
+		return nil, err
+	}
+	return r.query(ctx, selectRevisions+` ORDER BY "version"`)
+}
+
+// ReadRevision returns the revision of the version, or migrate.ErrRevisionNotExist.
+func (r *revisions) ReadRevision(ctx context.Context, version string) (*migrate.Revision, error) {
+	revs, err := r.query(ctx, selectRevisions+` WHERE "version" = $1`, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(revs) == 0 {
+		return nil, migrate.ErrRevisionNotExist
+	}
+	return revs[0], nil
+}
+
+// query reads revisions returned by the query.
+func (r *revisions) query(ctx context.Context, query string, args ...any) ([]*migrate.Revision, error) {
+	rows, err := r.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration history: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck // error is reported by rows.Err
+	var revs []*migrate.Revision
+	for rows.Next() {
+		rev := &migrate.Revision{}
+		var executionTime int64
+		var partialHashes []byte
+		if err = rows.Scan(&rev.Version, &rev.Description, &rev.Type, &rev.Applied, &rev.Total, &rev.ExecutedAt,
+			&executionTime, &rev.Error, &rev.ErrorStmt, &rev.Hash, &partialHashes, &rev.OperatorVersion); err != nil {
+			return nil, fmt.Errorf("failed to read migration history: %w", err)
+		}
+		rev.ExecutionTime = time.Duration(executionTime)
+		if err = json.Unmarshal(partialHashes, &rev.PartialHashes); err != nil {
+			return nil, fmt.Errorf("failed to read migration history: %w", err)
+		}
+		revs = append(revs, rev)
+	}
+	return revs, rows.Err()
+}
+
+// WriteRevision inserts the revision or updates the existing one.
+func (r *revisions) WriteRevision(ctx context.Context, rev *migrate.Revision) error {
+	partialHashes, err := json.Marshal(rev.PartialHashes)
+	if err != nil {
+		return err
+	}
+	_, err = r.conn.ExecContext(ctx, `INSERT INTO "`+revisionsSchema+`"."`+revisionsTable+`"
+  ("version", "description", "type", "applied", "total", "executed_at", "execution_time", "error", "error_stmt", "hash", "partial_hashes", "operator_version")
+VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10, $11, $12)
+ON CONFLICT ("version") DO UPDATE SET
+  "description" = EXCLUDED."description", "type" = EXCLUDED."type", "applied" = EXCLUDED."applied", "total" = EXCLUDED."total",
+  "executed_at" = EXCLUDED."executed_at", "execution_time" = EXCLUDED."execution_time", "error" = EXCLUDED."error",
+  "error_stmt" = EXCLUDED."error_stmt", "hash" = EXCLUDED."hash", "partial_hashes" = EXCLUDED."partial_hashes",
+  "operator_version" = EXCLUDED."operator_version"`,
+		rev.Version, rev.Description, int64(rev.Type), rev.Applied, rev.Total, rev.ExecutedAt, int64(rev.ExecutionTime),
+		rev.Error, rev.ErrorStmt, rev.Hash, string(partialHashes), rev.OperatorVersion)
+	if err != nil {
+		return fmt.Errorf("failed to write migration history: %w", err)
+	}
+	return nil
+}
+
+// DeleteRevision deletes the revision of the version.
+func (r *revisions) DeleteRevision(ctx context.Context, version string) error {
+	if _, err := r.conn.ExecContext(ctx, `DELETE FROM "`+revisionsSchema+`"."`+revisionsTable+`" WHERE "version" = $1`, version); err != nil {
+		return fmt.Errorf("failed to delete migration revision: %w", err)
+	}
+	return nil
+}
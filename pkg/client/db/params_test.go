@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataSourceName(t *testing.T) {
+	defer Configure(cfg)
+
+	c := DefaultConfig()
+	c.StatementTimeout = 5 * time.Second
+	Configure(c)
+	dsn, err := getDataSourceName()
+	require.NoError(t, err)
+	assert.Equal(t, "host=localhost port=5432 user=admin password=pass dbname=postgres sslmode=disable statement_timeout=5000", dsn)
+
+	// URL takes precedence over individual settings
+	c.URL = "postgres://user:secret@db:5433/reviews?sslmode=require"
+	Configure(c)
+	dsn, err = getDataSourceName()
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://user:secret@db:5433/reviews?sslmode=require&statement_timeout=5000", dsn)
+
+	c.StatementTimeout = 0
+	Configure(c)
+	dsn, err = getDataSourceName()
+	require.NoError(t, err)
+	assert.Equal(t, c.URL, dsn)
+}
+
+func TestConfigValidate(t *testing.T) {
+	c := DefaultConfig()
+	require.NoError(t, c.Validate())
+
+	c.URL = "mysql://localhost"
+	require.ErrorContains(t, c.Validate(), "url must be")
+
+	// individual settings are not used with URL
+	c.URL = "postgresql://localhost/reviews"
+	c.Host = ""
+	require.NoError(t, c.Validate())
+
+	c.MaxOpenConns = -1
+	c.ConnectTimeout = 0
+	err := c.Validate()
+	require.ErrorContains(t, err, "max_open_conns")
+	require.ErrorContains(t, err, "connect_timeout")
+
+	// Postgres settings are not used with SQLite
+	c = DefaultConfig()
+	c.Driver = DriverSQLite
+	c.URL = "mysql://localhost"
+	require.NoError(t, c.Validate())
+
+	// read replica is supported only with Postgres
+	c.ReplicaURL = "postgres://replica/reviews"
+	require.ErrorContains(t, c.Validate(), "replica_url is supported only with postgres driver")
+	c.Driver = DriverPostgres
+	c.URL = ""
+	require.NoError(t, c.Validate())
+	c.ReplicaURL = "replica:5432"
+	require.ErrorContains(t, c.Validate(), "replica_url must be")
+
+	// advisory locks and serializable transactions are supported only with Postgres
+	c = DefaultConfig()
+	c.LockingStrategy = LockingAdvisoryLock
+	require.NoError(t, c.Validate())
+	c.Driver = DriverSQLite
+	require.ErrorContains(t, c.Validate(), "locking_strategy advisory_lock is supported only with postgres driver")
+	c.LockingStrategy = LockingOptimistic
+	require.NoError(t, c.Validate())
+	c.LockingStrategy = "table_lock"
+	require.ErrorContains(t, c.Validate(), "unknown locking_strategy")
+	c.LockingStrategy = LockingRowLock
+	c.MaxTxRetries = -1
+	require.ErrorContains(t, c.Validate(), "max_tx_retries")
+
+	c.Driver = "mysql"
+	require.ErrorContains(t, c.Validate(), "unknown driver")
+}
+
+func TestStatementTimeout(t *testing.T) {
+	// without deadline, session timeout applies
+	_, ok, err := statementTimeout(context.Background(), time.Second)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	// configured timeout is shorter than the deadline
+	_, ok, err = statementTimeout(ctx, time.Second)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// deadline is shorter than the configured timeout, or the timeout is disabled
+	for _, configured := range []time.Duration{time.Hour, 0} {
+		timeout, ok, err := statementTimeout(ctx, configured)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.InDelta(t, time.Minute, timeout, float64(time.Second))
+	}
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	_, _, err = statementTimeout(expired, time.Second)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestOpenRetries(t *testing.T) {
+	defer Configure(cfg)
+
+	c := DefaultConfig()
+	c.Port = 1 // nothing listens there
+	c.ConnectTimeout = 600 * time.Millisecond
+	Configure(c)
+	start := time.Now()
+	_, err := open()
+	require.ErrorContains(t, err, "DB is not reachable after")
+	assert.GreaterOrEqual(t, time.Since(start), c.ConnectTimeout)
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	require.NoError(t, ValidateMigrations())
+
+	dir, err := migrationDir()
+	require.NoError(t, err)
+	files, err := dir.Files()
+	require.NoError(t, err)
+	assert.NotEmpty(t, files)
+}
+
+func TestSchemaStatusErr(t *testing.T) {
+	require.NoError(t, (&SchemaStatus{Unknown: []string{"20990101000000"}}).Err())
+
+	err := (&SchemaStatus{Pending: []string{"1_a.sql", "2_b.sql"}}).Err()
+	require.ErrorContains(t, err, "behind by 2 migration(s): 1_a.sql, 2_b.sql")
+
+	err = (&SchemaStatus{Drift: []string{"migration 1_a.sql was changed after it was applied"}}).Err()
+	require.ErrorContains(t, err, "drifted: migration 1_a.sql was changed")
+}
+
+func TestSQLiteDataSourceName(t *testing.T) {
+	defer Configure(cfg)
+
+	c := DefaultConfig()
+	c.Driver = DriverSQLite
+	Configure(c)
+	assert.Equal(t, "file::memory:?_fk=1&_txlock=immediate", getSQLiteDataSourceName())
+
+	c.SQLiteFile = "/tmp/reviews.db"
+	Configure(c)
+	assert.Equal(t, "file:/tmp/reviews.db?_fk=1&_txlock=immediate&_journal_mode=WAL&_busy_timeout=5000", getSQLiteDataSourceName())
+}
+
+func TestSerializedTransactions(t *testing.T) {
+	defer Configure(cfg)
+
+	c := DefaultConfig()
+	c.Driver = DriverSQLite
+	Configure(c)
+	drv, err := open()
+	require.NoError(t, err)
+	serialized := newSerializedDriver(drv)
+	t.Cleanup(func() {
+		assert.NoError(t, serialized.Close())
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	tx, err := serialized.Tx(ctx)
+	require.NoError(t, err)
+
+	// the next transaction waits until the running one finishes
+	waitCtx, cancelWait := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancelWait()
+	_, err = serialized.Tx(waitCtx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	holdingDuration := 100 * time.Millisecond
+	go func() {
+		time.Sleep(holdingDuration)
+		assert.NoError(t, tx.Commit())
+		// rollback after commit does not release the lock of another transaction
+		assert.Error(t, tx.Rollback())
+	}()
+	start := time.Now()
+	next, err := serialized.Tx(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), holdingDuration/2)
+	require.NoError(t, next.Rollback())
+
+	// failed transaction releases the lock as well
+	next, err = serialized.Tx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, next.Rollback())
+}
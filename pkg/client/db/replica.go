@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+
+	"github.com/eroshiva/cloudtalk/internal/ent"
+)
+
+// primaryKey is a context key, which pins queries of the context to the primary DB.
+type primaryKey struct{}
+
+// WithPrimary returns context, whose queries are served by the primary DB even if a read replica is configured,
+// e.g., so the client reads its own writes, which the replica may not have applied yet.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// ReadsFromPrimary reports whether queries of the context are pinned to the primary DB with WithPrimary.
+func ReadsFromPrimary(ctx context.Context) bool {
+	pinned, _ := ctx.Value(primaryKey{}).(bool)
+	return pinned
+}
+
+// OpenReadReplica opens connection to the read replica of the DB and waits until it accepts connections.
+// Returns nil client, if no replica is configured. Schema of the replica follows the primary DB, thus it is not verified.
+func OpenReadReplica() (*ent.Client, error) {
+	if cfg.ReplicaURL == "" {
+		return nil, nil
+	}
+	zlog.Info().Msg("Opening connection to the read replica of the DB...")
+	dsn, err := urlDataSourceName(cfg.ReplicaURL)
+	if err != nil {
+		return nil, err
+	}
+	drv, err := openPostgres(dsn)
+	if err != nil {
+		zlog.Error().Err(err).Msg("failed opening connection to the read replica")
+		return nil, err
+	}
+	return ent.NewClient(ent.Driver(newTracedDriver(newDeadlineDriver(drv)))), nil
+}
+
+// EntStoreOption customizes the Store created with NewEntStore.
+type EntStoreOption func(*entStore)
+
+// WithReadReplica routes all read-only queries of the Store (e.g., GetProductByID, ListProducts or GetReviewByID)
+// to the replica, unless their context is pinned to the primary DB with WithPrimary. Writes and their transactions
+// always go to the primary DB. The Store takes ownership
+// of the replica client, nil client is ignored.
+func WithReadReplica(replica *ent.Client) EntStoreOption {
+	return func(s *entStore) {
+		s.replica = replica
+	}
+}
+
+// HasReadReplica reports whether the Store routes read-only queries to a read replica.
+func HasReadReplica(store Store) bool {
+	s, ok := store.(*entStore)
+	return ok && s.replica != nil
+}
+
+// reader returns client, which serves read-only queries of the context.
+func (s *entStore) reader(ctx context.Context) *ent.Client {
+	if s.replica == nil || ReadsFromPrimary(ctx) {
+		return s.client
+	}
+	return s.replica
+}
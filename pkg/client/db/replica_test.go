@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadReplicaRouting(t *testing.T) {
+	defer Configure(cfg)
+
+	// each in-memory SQLite DB is separate, so the replica never receives writes of the primary
+	c := DefaultConfig()
+	c.Driver = DriverSQLite
+	Configure(c)
+	primary, err := RunSchemaMigration()
+	require.NoError(t, err)
+	replica, err := RunSchemaMigration()
+	require.NoError(t, err)
+	store := NewEntStore(primary, WithReadReplica(replica))
+	t.Cleanup(func() {
+		assert.NoError(t, store.Close())
+	})
+	require.True(t, HasReadReplica(store))
+	assert.False(t, HasReadReplica(NewMemoryStore()))
+
+	ctx := context.Background()
+	p, err := store.CreateProduct(ctx, "myAwesomeProduct", "Product description", "19.90")
+	require.NoError(t, err)
+	r, err := store.CreateReview(ctx, "John", "Doe", "Product is good!", 5, p.ID)
+	require.NoError(t, err)
+
+	// read-only queries are served by the replica
+	_, err = store.GetProductByID(ctx, p.ID)
+	require.True(t, IsNotFound(err))
+	results, err := store.GetProductsByIDs(ctx, []string{p.ID})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Product)
+	ps, err := store.ListProducts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, ps)
+	ps, err = store.ListTopProducts(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, ps)
+	count, err := store.CountProducts(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	rs, err := store.GetReviewsByProductID(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Empty(t, rs)
+	_, err = store.GetReviewByID(ctx, r.ID)
+	require.True(t, IsNotFound(err))
+	count, err = store.CountReviews(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	// unless they are pinned to the primary
+	pinned := WithPrimary(ctx)
+	assert.True(t, ReadsFromPrimary(pinned))
+	assert.False(t, ReadsFromPrimary(ctx))
+	got, err := store.GetProductByID(pinned, p.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 5.0, got.AverageRating, 0.001)
+	results, err = store.GetProductsByIDs(pinned, []string{p.ID})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	ps, err = store.ListProducts(pinned)
+	require.NoError(t, err)
+	assert.Len(t, ps, 1)
+	ps, err = store.ListTopProducts(pinned, 10)
+	require.NoError(t, err)
+	assert.Len(t, ps, 1)
+	count, err = store.CountProducts(pinned)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	rs, err = store.GetReviewsByProductID(pinned, p.ID)
+	require.NoError(t, err)
+	assert.Len(t, rs, 1)
+	_, err = store.GetReviewByID(pinned, r.ID)
+	require.NoError(t, err)
+	count, err = store.CountReviews(pinned)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.NoError(t, store.Ping(ctx))
+}
+
+func TestOpenReadReplicaNotConfigured(t *testing.T) {
+	replica, err := OpenReadReplica()
+	require.NoError(t, err)
+	assert.Nil(t, replica)
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/eroshiva/cloudtalk/internal/ent"
+	_ "github.com/mattn/go-sqlite3" // SQLite driver, used for local development and tests
+)
+
+// sqliteBusyTimeout is a time SQLite waits for a lock held by another connection to the same file.
+const sqliteBusyTimeout = 5 * time.Second
+
+// getSQLiteDataSourceName returns data source name of the SQLite DB. In-memory DB is used, when no file is configured.
+// Transactions take the write lock right away, so concurrent writers of the same file wait for each other
+// (for up to the busy timeout) instead of failing on lock upgrade.
+func getSQLiteDataSourceName() string {
+	if cfg.SQLiteFile == "" {
+		return "file::memory:?_fk=1&_txlock=immediate"
+	}
+	return "file:" + cfg.SQLiteFile + "?_fk=1&_txlock=immediate&_journal_mode=WAL&_busy_timeout=" +
+		strconv.FormatInt(sqliteBusyTimeout.Milliseconds(), 10)
+}
+
+// openSQLite opens connection pool to the SQLite DB.
+func openSQLite() (*entsql.Driver, error) {
+	drv, err := entsql.Open(dialect.SQLite, getSQLiteDataSourceName())
+	if err != nil {
+		return nil, err
+	}
+	pool := drv.DB()
+	if cfg.SQLiteFile == "" {
+		// each connection would open its own in-memory DB, thus the pool keeps exactly one connection forever
+		pool.SetMaxOpenConns(1)
+		pool.SetMaxIdleConns(1)
+		pool.SetConnMaxLifetime(0)
+	} else {
+		pool.SetMaxOpenConns(cfg.MaxOpenConns)
+		pool.SetMaxIdleConns(cfg.MaxIdleConns)
+		pool.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	if err = pool.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to open SQLite DB: %w", err)
+	}
+	return drv, nil
+}
+
+// ProductsForUpdate locks products selected by the query until the end of its transaction. SQLite does not support
+// row locking, the query only reads the products there, because transactions are serialized (see serializedDriver).
+func ProductsForUpdate(q *ent.ProductQuery) *ent.ProductQuery {
+	if cfg.Driver == DriverSQLite {
+		return q
+	}
+	return q.ForUpdate()
+}
+
+// serializedDriver runs transactions one at a time, which replaces row locking of Postgres on SQLite. Transaction
+// holds the lock from its start until it is committed or rolled back, thus reads within a transaction see the same
+// state as with "SELECT ... FOR UPDATE".
+type serializedDriver struct {
+	*entsql.Driver
+	// lock is held by the running transaction, channel is used, so waiting may be cancelled with the context
+	lock chan struct{}
+}
+
+// newSerializedDriver wraps the driver, so its transactions are serialized.
+func newSerializedDriver(drv *entsql.Driver) dialect.Driver {
+	return &serializedDriver{Driver: drv, lock: make(chan struct{}, 1)}
+}
+
+// Tx waits until the running transaction finishes and starts a new one.
+func (d *serializedDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	return d.BeginTx(ctx, nil)
+}
+
+// BeginTx waits until the running transaction finishes and starts a new one with the provided options.
+func (d *serializedDriver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	select {
+	case d.lock <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	tx, err := d.Driver.BeginTx(ctx, opts)
+	if err != nil {
+		<-d.lock
+		return nil, err
+	}
+	return &serializedTx{Tx: tx, lock: d.lock}, nil
+}
+
+// serializedTx releases the lock of the driver once it is committed or rolled back.
+type serializedTx struct {
+	dialect.Tx
+	lock     chan struct{}
+	finished bool
+}
+
+// Commit commits the transaction and lets the next one start.
+func (tx *serializedTx) Commit() error {
+	defer tx.release()
+	return tx.Tx.Commit()
+}
+
+// Rollback rolls back the transaction and lets the next one start.
+func (tx *serializedTx) Rollback() error {
+	defer tx.release()
+	return tx.Tx.Rollback()
+}
+
+// release releases the lock, transaction may be rolled back after it was committed.
+func (tx *serializedTx) release() {
+	if !tx.finished {
+		tx.finished = true
+		<-tx.lock
+	}
+}
@@ -0,0 +1,230 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/eroshiva/cloudtalk/internal/ent"
+)
+
+// ErrNotFound is reported (wrapped) by the stores, when the requested resource doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// IsNotFound reports whether the error means that the requested resource doesn't exist.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || ent.IsNotFound(err)
+}
+
+// ProductStore defines operations over Product resources. Returned Product resources carry their reviews,
+// unless stated otherwise. GetProductByID, ListProducts and GetReviewsByProductID may be served by a read replica,
+// which lags behind the writes, unless the context is pinned to the primary DB with WithPrimary.
+type ProductStore interface {
+	// CreateProduct creates Product resource. Name, description and price must be specified.
+	CreateProduct(ctx context.Context, name, description, price string) (*ent.Product, error)
+	// CreateProductIdempotent creates Product resource unless it was already created with the same idempotency key.
+	// In such case, originally created Product resource is returned and the returned flag is false.
+	CreateProductIdempotent(ctx context.Context, idempotencyKey, name, description, price string) (*ent.Product, bool, error)
+	// GetProductByID retrieves Product resource by its ID.
+	GetProductByID(ctx context.Context, id string) (*ent.Product, error)
+	// GetProductsByIDs retrieves multiple Product resources. Results are reported in the same order as IDs were provided.
+	GetProductsByIDs(ctx context.Context, ids []string) ([]ProductResult, error)
+	// ListProducts retrieves all Product resources.
+	ListProducts(ctx context.Context) ([]*ent.Product, error)
+	// ListTopProducts retrieves at most limit Product resources with the highest number of reviews.
+	ListTopProducts(ctx context.Context, limit int) ([]*ent.Product, error)
+	// EditProduct updates all provided non-empty fields of Product resource.
+	EditProduct(ctx context.Context, id, name, description, price string) (*ent.Product, error)
+	// DeleteProductByID removes Product resource. Its reviews are kept without the product.
+	DeleteProductByID(ctx context.Context, id string) error
+	// CountProducts returns number of stored Product resources.
+	CountProducts(ctx context.Context) (int, error)
+}
+
+// ReviewStore defines operations over Review resources. Each change of reviews recalculates average rating
+// of the affected products atomically with the change, i.e., concurrent changes of reviews of the same product
+// never lose an update. Review resources returned by the changes carry the product with updated average rating.
+type ReviewStore interface {
+	// CreateReview creates Review resource of the existing product.
+	CreateReview(ctx context.Context, name, lastName, text string, rating int32, productID string) (*ent.Review, error)
+	// CreateReviewIdempotent creates Review resource unless it was already created with the same idempotency key.
+	// In such case, originally created Review resource is returned and the returned flag is false.
+	CreateReviewIdempotent(ctx context.Context, idempotencyKey, name, lastName, text string, rating int32, productID string) (
+		*ent.Review, bool, error)
+	// CreateReviews creates multiple Review resources at once. Results are reported in the same order as Review
+	// resources were provided, invalid Review resources don't prevent others from being created.
+	CreateReviews(ctx context.Context, inputs []ReviewInput) ([]ReviewResult, error)
+	// GetReviewByID retrieves Review resource by its ID together with its product.
+	GetReviewByID(ctx context.Context, id string) (*ent.Review, error)
+	// GetReviewsByProductID retrieves all Review resources of the product. Reviews don't carry the product.
+	GetReviewsByProductID(ctx context.Context, productID string) ([]*ent.Review, error)
+	// EditReview updates all provided non-empty fields of Review resource, rating is updated only if it is in range.
+	EditReview(ctx context.Context, id, name, lastName, text string, rating int32) (*ent.Review, error)
+	// DeleteReviewByID removes Review resource of the product. Returns the product with updated average rating.
+	DeleteReviewByID(ctx context.Context, id, productID string) (*ent.Product, error)
+	// DeleteReviewsByIDs removes multiple Review resources at once. Results are reported in the same order as IDs
+	// were provided, each removed Review resource is returned.
+	DeleteReviewsByIDs(ctx context.Context, ids []string) ([]ReviewResult, error)
+	// CountReviews returns number of stored Review resources.
+	CountReviews(ctx context.Context) (int, error)
+}
+
+// Store provides Product and Review resources together with the state of the underlying storage.
+type Store interface {
+	ProductStore
+	ReviewStore
+	// Ping verifies that the storage is reachable and able to serve requests.
+	Ping(ctx context.Context) error
+	// MigrationStatus returns an error, unless schema of the storage is up to date.
+	MigrationStatus() error
+	// PurgeExpiredIdempotencyKeys removes expired idempotency keys, so they don't accumulate. Expired keys are ignored
+	// by the creations even before they are purged. Returns the number of removed keys.
+	PurgeExpiredIdempotencyKeys(ctx context.Context) (int, error)
+	// Close releases the storage.
+	Close() error
+}
+
+// entStore is a Store backed by ent client, i.e., by Postgres or SQLite.
+type entStore struct {
+	client *ent.Client
+	// replica serves read-only queries, unless they are pinned to the primary DB, nil means that there is no replica
+	replica *ent.Client
+}
+
+// NewEntStore creates a Store backed by the ent client, the Store takes ownership of the client.
+func NewEntStore(client *ent.Client, opts ...EntStoreOption) Store {
+	s := &entStore{client: client}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreateProduct creates Product resource.
+func (s *entStore) CreateProduct(ctx context.Context, name, description, price string) (*ent.Product, error) {
+	return CreateProduct(ctx, s.client, name, description, price)
+}
+
+// CreateProductIdempotent creates Product resource unless it was already created with the same idempotency key.
+func (s *entStore) CreateProductIdempotent(ctx context.Context, idempotencyKey, name, description, price string) (
+	*ent.Product, bool, error,
+) {
+	return CreateProductIdempotent(ctx, s.client, idempotencyKey, name, description, price)
+}
+
+// GetProductByID retrieves Product resource by its ID, read replica is preferred.
+func (s *entStore) GetProductByID(ctx context.Context, id string) (*ent.Product, error) {
+	return GetProductByID(ctx, s.reader(ctx), id)
+}
+
+// GetProductsByIDs retrieves multiple Product resources with a single query, read replica is preferred.
+func (s *entStore) GetProductsByIDs(ctx context.Context, ids []string) ([]ProductResult, error) {
+	return GetProductsByIDs(ctx, s.reader(ctx), ids)
+}
+
+// ListProducts retrieves all Product resources, read replica is preferred.
+func (s *entStore) ListProducts(ctx context.Context) ([]*ent.Product, error) {
+	return ListProducts(ctx, s.reader(ctx))
+}
+
+// ListTopProducts retrieves at most limit Product resources with the highest number of reviews, read replica is preferred.
+func (s *entStore) ListTopProducts(ctx context.Context, limit int) ([]*ent.Product, error) {
+	return ListTopProducts(ctx, s.reader(ctx), limit)
+}
+
+// EditProduct updates all provided non-empty fields of Product resource.
+func (s *entStore) EditProduct(ctx context.Context, id, name, description, price string) (*ent.Product, error) {
+	return EditProduct(ctx, s.client, id, name, description, price)
+}
+
+// DeleteProductByID removes Product resource.
+func (s *entStore) DeleteProductByID(ctx context.Context, id string) error {
+	return DeleteProductByID(ctx, s.client, id)
+}
+
+// CountProducts returns number of Product resources stored in the DB, read replica is preferred.
+func (s *entStore) CountProducts(ctx context.Context) (int, error) {
+	return s.reader(ctx).Product.Query().Count(ctx)
+}
+
+// CreateReview creates Review resource and recalculates average rating of its product within a transaction.
+func (s *entStore) CreateReview(ctx context.Context, name, lastName, text string, rating int32, productID string) (
+	*ent.Review, error,
+) {
+	return CreateReview(ctx, s.client, name, lastName, text, rating, productID)
+}
+
+// CreateReviewIdempotent creates Review resource unless it was already created with the same idempotency key.
+func (s *entStore) CreateReviewIdempotent(ctx context.Context, idempotencyKey, name, lastName, text string, rating int32,
+	productID string,
+) (*ent.Review, bool, error) {
+	return CreateReviewIdempotent(ctx, s.client, idempotencyKey, name, lastName, text, rating, productID)
+}
+
+// CreateReviews creates multiple Review resources within a single transaction.
+func (s *entStore) CreateReviews(ctx context.Context, inputs []ReviewInput) ([]ReviewResult, error) {
+	return CreateReviews(ctx, s.client, inputs)
+}
+
+// GetReviewByID retrieves Review resource by its ID, read replica is preferred.
+func (s *entStore) GetReviewByID(ctx context.Context, id string) (*ent.Review, error) {
+	return GetReviewByID(ctx, s.reader(ctx), id)
+}
+
+// GetReviewsByProductID retrieves all Review resources of the product, read replica is preferred.
+func (s *entStore) GetReviewsByProductID(ctx context.Context, productID string) ([]*ent.Review, error) {
+	return GetReviewsByProductID(ctx, s.reader(ctx), productID)
+}
+
+// EditReview updates Review resource and recalculates average rating of its product within a transaction.
+func (s *entStore) EditReview(ctx context.Context, id, name, lastName, text string, rating int32) (*ent.Review, error) {
+	return EditReview(ctx, s.client, id, name, lastName, text, rating)
+}
+
+// DeleteReviewByID removes Review resource and recalculates average rating of its product within a transaction.
+func (s *entStore) DeleteReviewByID(ctx context.Context, id, productID string) (*ent.Product, error) {
+	return DeleteReviewByID(ctx, s.client, id, productID)
+}
+
+// DeleteReviewsByIDs removes multiple Review resources within a single transaction.
+func (s *entStore) DeleteReviewsByIDs(ctx context.Context, ids []string) ([]ReviewResult, error) {
+	return DeleteReviewsByIDs(ctx, s.client, ids)
+}
+
+// CountReviews returns number of Review resources stored in the DB, read replica is preferred.
+func (s *entStore) CountReviews(ctx context.Context) (int, error) {
+	return s.reader(ctx).Review.Query().Count(ctx)
+}
+
+// Ping verifies that the DB and its read replica are reachable.
+func (s *entStore) Ping(ctx context.Context) error {
+	if err := Ping(ctx, s.client); err != nil {
+		return err
+	}
+	if s.replica != nil {
+		if err := Ping(ctx, s.replica); err != nil {
+			return fmt.Errorf("read replica: %w", err)
+		}
+	}
+	return nil
+}
+
+// MigrationStatus returns an error, unless the DB schema was successfully migrated at startup.
+func (s *entStore) MigrationStatus() error {
+	return MigrationStatus()
+}
+
+// PurgeExpiredIdempotencyKeys removes expired idempotency keys from the DB.
+func (s *entStore) PurgeExpiredIdempotencyKeys(ctx context.Context) (int, error) {
+	return PurgeExpiredIdempotencyKeys(ctx, s.client)
+}
+
+// Close gracefully closes connections with the DB and its read replica.
+func (s *entStore) Close() error {
+	var errs []error
+	if s.replica != nil {
+		errs = append(errs, GracefullyCloseDBClient(s.replica))
+	}
+	errs = append(errs, GracefullyCloseDBClient(s.client))
+	return errors.Join(errs...)
+}
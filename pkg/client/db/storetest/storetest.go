@@ -0,0 +1,434 @@
+// Package storetest implements conformance tests, which every implementation of db.Store must pass.
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eroshiva/cloudtalk/internal/ent"
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTimeout = 5 * time.Second
+
+	productName        = "myAwesomeProduct"
+	productDescription = "Product description"
+	productPrice       = "19.90"
+	reviewerName       = "John"
+	reviewerLastName   = "Doe"
+	reviewText         = "Product is good!"
+)
+
+// Run runs the conformance tests against the store. Store may contain other resources, tests remove only
+// the resources they create, and the store is not closed.
+func Run(t *testing.T, store db.Store) {
+	t.Run("ProductCRUD", func(t *testing.T) { testProductCRUD(t, store) })
+	t.Run("ProductValidation", func(t *testing.T) { testProductValidation(t, store) })
+	t.Run("ReviewCRUD", func(t *testing.T) { testReviewCRUD(t, store) })
+	t.Run("ReviewValidation", func(t *testing.T) { testReviewValidation(t, store) })
+	t.Run("DeletedProductKeepsReviews", func(t *testing.T) { testDeletedProductKeepsReviews(t, store) })
+	t.Run("IdempotentCreation", func(t *testing.T) { testIdempotentCreation(t, store) })
+	t.Run("BatchOperations", func(t *testing.T) { testBatchOperations(t, store) })
+	t.Run("TopProducts", func(t *testing.T) { testTopProducts(t, store) })
+	t.Run("ConcurrentReviewCreation", func(t *testing.T) { testConcurrentReviewCreation(t, store) })
+	t.Run("Health", func(t *testing.T) { testHealth(t, store) })
+}
+
+// newContext returns context bounded by the test timeout.
+func newContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// createProduct creates a product, which is removed once the test finishes.
+func createProduct(t *testing.T, store db.Store) *ent.Product {
+	p, err := store.CreateProduct(context.Background(), productName, productDescription, productPrice)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, store.DeleteProductByID(context.Background(), p.ID))
+	})
+	return p
+}
+
+// createReview creates a review of the product, which is removed once the test finishes.
+func createReview(t *testing.T, store db.Store, productID string, rating int32) *ent.Review {
+	r, err := store.CreateReview(context.Background(), reviewerName, reviewerLastName, reviewText, rating, productID)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := store.DeleteReviewsByIDs(context.Background(), []string{r.ID})
+		assert.NoError(t, err)
+	})
+	return r
+}
+
+// productIDs returns IDs of the products in the same order.
+func productIDs(ps []*ent.Product) []string {
+	ids := make([]string, 0, len(ps))
+	for _, p := range ps {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func testProductCRUD(t *testing.T, store db.Store) {
+	ctx := newContext(t)
+	count, err := store.CountProducts(ctx)
+	require.NoError(t, err)
+
+	p := createProduct(t, store)
+	assert.NotEmpty(t, p.ID)
+	assert.Equal(t, productName, p.Name)
+	assert.Equal(t, productDescription, p.Description)
+	assert.Equal(t, productPrice, p.Price)
+	assert.Zero(t, p.AverageRating)
+
+	newCount, err := store.CountProducts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, count+1, newCount)
+
+	retP, err := store.GetProductByID(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, p.ID, retP.ID)
+	assert.Equal(t, p.Name, retP.Name)
+	assert.Empty(t, retP.Edges.Reviews)
+
+	// empty fields are not updated
+	updP, err := store.EditProduct(ctx, p.ID, "", "New description", "")
+	require.NoError(t, err)
+	assert.Equal(t, productName, updP.Name)
+	assert.Equal(t, "New description", updP.Description)
+	assert.Equal(t, productPrice, updP.Price)
+	retP, err = store.GetProductByID(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "New description", retP.Description)
+
+	ps, err := store.ListProducts(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, productIDs(ps), p.ID)
+
+	require.NoError(t, store.DeleteProductByID(ctx, p.ID))
+	_, err = store.GetProductByID(ctx, p.ID)
+	require.Error(t, err)
+	assert.True(t, db.IsNotFound(err))
+	_, err = store.EditProduct(ctx, p.ID, "New name", "", "")
+	assert.True(t, db.IsNotFound(err))
+	// removing missing product is not an error
+	require.NoError(t, store.DeleteProductByID(ctx, p.ID))
+}
+
+func testProductValidation(t *testing.T, store db.Store) {
+	ctx := newContext(t)
+	for name, tc := range map[string]struct {
+		name, description, price string
+	}{
+		"name":        {description: productDescription, price: productPrice},
+		"description": {name: productName, price: productPrice},
+		"price":       {name: productName, description: productDescription},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := store.CreateProduct(ctx, tc.name, tc.description, tc.price)
+			assert.ErrorContains(t, err, name+" is not specified")
+		})
+	}
+}
+
+func testReviewCRUD(t *testing.T, store db.Store) {
+	ctx := newContext(t)
+	p := createProduct(t, store)
+
+	r1 := createReview(t, store, p.ID, 5)
+	assert.NotEmpty(t, r1.ID)
+	assert.Equal(t, reviewerName, r1.FirstName)
+	assert.Equal(t, reviewerLastName, r1.LastName)
+	assert.Equal(t, reviewText, r1.ReviewText)
+	assert.Equal(t, int32(5), r1.Rating)
+	require.NotNil(t, r1.Edges.Product)
+	assert.Equal(t, p.ID, r1.Edges.Product.ID)
+	assert.InDelta(t, 5.0, r1.Edges.Product.AverageRating, 0.001)
+
+	r2 := createReview(t, store, p.ID, 2)
+	require.NotNil(t, r2.Edges.Product)
+	assert.InDelta(t, 3.5, r2.Edges.Product.AverageRating, 0.001)
+
+	retP, err := store.GetProductByID(ctx, p.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 3.5, retP.AverageRating, 0.001)
+	assert.Len(t, retP.Edges.Reviews, 2)
+
+	retR, err := store.GetReviewByID(ctx, r1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, r1.ID, retR.ID)
+	require.NotNil(t, retR.Edges.Product)
+	assert.Equal(t, p.ID, retR.Edges.Product.ID)
+
+	rs, err := store.GetReviewsByProductID(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Len(t, rs, 2)
+
+	// rating out of range and empty fields are not updated
+	updR, err := store.EditReview(ctx, r2.ID, "Jane", "", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Jane", updR.FirstName)
+	assert.Equal(t, reviewerLastName, updR.LastName)
+	assert.Equal(t, int32(2), updR.Rating)
+	updR, err = store.EditReview(ctx, r2.ID, "", "", "Product is bad.", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Jane", updR.FirstName)
+	assert.Equal(t, "Product is bad.", updR.ReviewText)
+	assert.Equal(t, int32(1), updR.Rating)
+	require.NotNil(t, updR.Edges.Product)
+	assert.InDelta(t, 3.0, updR.Edges.Product.AverageRating, 0.001)
+
+	updP, err := store.DeleteReviewByID(ctx, r1.ID, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, p.ID, updP.ID)
+	assert.InDelta(t, 1.0, updP.AverageRating, 0.001)
+	_, err = store.GetReviewByID(ctx, r1.ID)
+	assert.True(t, db.IsNotFound(err))
+
+	updP, err = store.DeleteReviewByID(ctx, r2.ID, p.ID)
+	require.NoError(t, err)
+	assert.Zero(t, updP.AverageRating)
+	rs, err = store.GetReviewsByProductID(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Empty(t, rs)
+}
+
+func testReviewValidation(t *testing.T, store db.Store) {
+	ctx := newContext(t)
+	p := createProduct(t, store)
+
+	for name, tc := range map[string]struct {
+		name, lastName, text string
+		rating               int32
+		productID            string
+		err                  string
+	}{
+		"name":      {lastName: reviewerLastName, text: reviewText, rating: 3, productID: p.ID, err: "name is not specified"},
+		"last name": {name: reviewerName, text: reviewText, rating: 3, productID: p.ID, err: "last name is not specified"},
+		"text":      {name: reviewerName, lastName: reviewerLastName, rating: 3, productID: p.ID, err: "text of the review is not specified"},
+		"rating":    {name: reviewerName, lastName: reviewerLastName, text: reviewText, rating: 6, productID: p.ID, err: "out of range"},
+		"product":   {name: reviewerName, lastName: reviewerLastName, text: reviewText, rating: 3, err: "product is not specified"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := store.CreateReview(ctx, tc.name, tc.lastName, tc.text, tc.rating, tc.productID)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	_, err := store.CreateReview(ctx, reviewerName, reviewerLastName, reviewText, 3, "product-missing")
+	require.Error(t, err)
+	assert.True(t, db.IsNotFound(err))
+	_, err = store.GetReviewByID(ctx, "review-missing")
+	assert.True(t, db.IsNotFound(err))
+	_, err = store.EditReview(ctx, "review-missing", reviewerName, "", "", 0)
+	assert.True(t, db.IsNotFound(err))
+	_, err = store.DeleteReviewByID(ctx, "review-missing", "product-missing")
+	assert.True(t, db.IsNotFound(err))
+
+	// reviews of missing product are empty
+	rs, err := store.GetReviewsByProductID(ctx, "product-missing")
+	require.NoError(t, err)
+	assert.Empty(t, rs)
+}
+
+func testDeletedProductKeepsReviews(t *testing.T, store db.Store) {
+	ctx := newContext(t)
+	p := createProduct(t, store)
+	r := createReview(t, store, p.ID, 4)
+
+	require.NoError(t, store.DeleteProductByID(ctx, p.ID))
+	retR, err := store.GetReviewByID(ctx, r.ID)
+	require.NoError(t, err)
+	assert.Nil(t, retR.Edges.Product)
+}
+
+func testIdempotentCreation(t *testing.T, store db.Store) {
+	ctx := newContext(t)
+	productKey := fmt.Sprintf("product-key-%d", time.Now().UnixNano())
+	p, created, err := store.CreateProductIdempotent(ctx, productKey, productName, productDescription, productPrice)
+	require.NoError(t, err)
+	assert.True(t, created)
+	t.Cleanup(func() {
+		assert.NoError(t, store.DeleteProductByID(context.Background(), p.ID))
+	})
+
+	retried, created, err := store.CreateProductIdempotent(ctx, productKey, productName, productDescription, productPrice)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, p.ID, retried.ID)
+
+	// key reused with a different request is rejected
+	_, _, err = store.CreateProductIdempotent(ctx, productKey, productName, productDescription, "29.90")
+	require.ErrorIs(t, err, db.ErrIdempotencyKeyReused)
+
+	reviewKey := fmt.Sprintf("review-key-%d", time.Now().UnixNano())
+	r, created, err := store.CreateReviewIdempotent(ctx, reviewKey, reviewerName, reviewerLastName, reviewText, 4, p.ID)
+	require.NoError(t, err)
+	assert.True(t, created)
+	t.Cleanup(func() {
+		_, err := store.DeleteReviewsByIDs(context.Background(), []string{r.ID})
+		assert.NoError(t, err)
+	})
+
+	retriedR, created, err := store.CreateReviewIdempotent(ctx, reviewKey, reviewerName, reviewerLastName, reviewText, 4, p.ID)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, r.ID, retriedR.ID)
+	_, _, err = store.CreateReviewIdempotent(ctx, reviewKey, reviewerName, reviewerLastName, reviewText, 1, p.ID)
+	require.ErrorIs(t, err, db.ErrIdempotencyKeyReused)
+
+	// the same key of another operation doesn't collide
+	other, created, err := store.CreateProductIdempotent(ctx, reviewKey, productName, productDescription, productPrice)
+	require.NoError(t, err)
+	assert.True(t, created)
+	require.NoError(t, store.DeleteProductByID(ctx, other.ID))
+
+	// live keys are not purged
+	_, err = store.PurgeExpiredIdempotencyKeys(ctx)
+	require.NoError(t, err)
+	retried, created, err = store.CreateProductIdempotent(ctx, productKey, productName, productDescription, productPrice)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, p.ID, retried.ID)
+
+	// review was created only once
+	rs, err := store.GetReviewsByProductID(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Len(t, rs, 1)
+}
+
+func testBatchOperations(t *testing.T, store db.Store) {
+	ctx := newContext(t)
+	p1 := createProduct(t, store)
+	p2 := createProduct(t, store)
+
+	products, err := store.GetProductsByIDs(ctx, []string{p2.ID, "product-missing", p1.ID})
+	require.NoError(t, err)
+	require.Len(t, products, 3)
+	assert.Equal(t, p2.ID, products[0].Product.ID)
+	assert.ErrorContains(t, products[1].Err, "not found")
+	assert.Equal(t, p1.ID, products[2].Product.ID)
+
+	results, err := store.CreateReviews(ctx, []db.ReviewInput{
+		{FirstName: reviewerName, LastName: reviewerLastName, Text: reviewText, Rating: 5, ProductID: p1.ID},
+		{FirstName: reviewerName, LastName: reviewerLastName, Text: reviewText, Rating: 0, ProductID: p1.ID},
+		{FirstName: reviewerName, LastName: reviewerLastName, Text: reviewText, Rating: 3, ProductID: p1.ID},
+		{FirstName: reviewerName, LastName: reviewerLastName, Text: reviewText, Rating: 2, ProductID: "product-missing"},
+		{FirstName: reviewerName, LastName: reviewerLastName, Text: reviewText, Rating: 1, ProductID: p2.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	assert.ErrorContains(t, results[1].Err, "out of range")
+	assert.ErrorContains(t, results[3].Err, "not found")
+	for _, i := range []int{0, 2, 4} {
+		require.NoError(t, results[i].Err)
+		require.NotNil(t, results[i].Review.Edges.Product)
+	}
+	// average rating reflects the whole batch
+	assert.InDelta(t, 4.0, results[0].Review.Edges.Product.AverageRating, 0.001)
+	assert.InDelta(t, 4.0, results[2].Review.Edges.Product.AverageRating, 0.001)
+	assert.InDelta(t, 1.0, results[4].Review.Edges.Product.AverageRating, 0.001)
+
+	deleted, err := store.DeleteReviewsByIDs(ctx, []string{results[0].Review.ID, "review-missing", results[4].Review.ID,
+		results[0].Review.ID})
+	require.NoError(t, err)
+	require.Len(t, deleted, 4)
+	require.NoError(t, deleted[0].Err)
+	assert.Equal(t, results[0].Review.ID, deleted[0].Review.ID)
+	require.NotNil(t, deleted[0].Review.Edges.Product)
+	assert.InDelta(t, 3.0, deleted[0].Review.Edges.Product.AverageRating, 0.001)
+	assert.ErrorContains(t, deleted[1].Err, "not found")
+	require.NoError(t, deleted[2].Err)
+	require.NotNil(t, deleted[2].Review.Edges.Product)
+	assert.Zero(t, deleted[2].Review.Edges.Product.AverageRating)
+	assert.ErrorContains(t, deleted[3].Err, "more than once")
+
+	// cleaning up the remaining review
+	_, err = store.DeleteReviewByID(ctx, results[2].Review.ID, p1.ID)
+	require.NoError(t, err)
+}
+
+func testTopProducts(t *testing.T, store db.Store) {
+	ctx := newContext(t)
+	p1 := createProduct(t, store)
+	p2 := createProduct(t, store)
+	createReview(t, store, p1.ID, 3)
+	createReview(t, store, p2.ID, 4)
+	createReview(t, store, p2.ID, 5)
+
+	count, err := store.CountProducts(ctx)
+	require.NoError(t, err)
+	ps, err := store.ListTopProducts(ctx, count)
+	require.NoError(t, err)
+	ids := productIDs(ps)
+	require.Contains(t, ids, p1.ID)
+	require.Contains(t, ids, p2.ID)
+	for i, p := range ps {
+		if i > 0 {
+			assert.LessOrEqual(t, len(p.Edges.Reviews), len(ps[i-1].Edges.Reviews))
+		}
+	}
+
+	ps, err = store.ListTopProducts(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, ps, 1)
+}
+
+func testConcurrentReviewCreation(t *testing.T, store db.Store) {
+	ctx := newContext(t)
+	p := createProduct(t, store)
+
+	const reviews = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, reviews)
+	ids := make(chan string, reviews)
+	var totalRating int32
+	for i := range reviews {
+		rating := int32(i%5 + 1)
+		totalRating += rating
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := store.CreateReview(ctx, reviewerName, reviewerLastName, reviewText, rating, p.ID)
+			if err != nil {
+				errs <- err
+				return
+			}
+			ids <- r.ID
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	close(ids)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	t.Cleanup(func() {
+		var created []string
+		for id := range ids {
+			created = append(created, id)
+		}
+		_, err := store.DeleteReviewsByIDs(context.Background(), created)
+		assert.NoError(t, err)
+	})
+
+	// no update of the average rating is lost
+	retP, err := store.GetProductByID(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Len(t, retP.Edges.Reviews, reviews)
+	assert.InDelta(t, float64(totalRating)/reviews, retP.AverageRating, 0.001)
+}
+
+func testHealth(t *testing.T, store db.Store) {
+	ctx := newContext(t)
+	require.NoError(t, store.Ping(ctx))
+	require.NoError(t, store.MigrationStatus())
+}
@@ -0,0 +1,30 @@
+package storetest_test
+
+import (
+	"testing"
+
+	"github.com/eroshiva/cloudtalk/pkg/client/db"
+	"github.com/eroshiva/cloudtalk/pkg/client/db/storetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	storetest.Run(t, db.NewMemoryStore())
+}
+
+func TestSQLiteStore(t *testing.T) {
+	cfg := db.DefaultConfig()
+	cfg.Driver = db.DriverSQLite
+	db.Configure(cfg)
+	t.Cleanup(func() {
+		db.Configure(db.DefaultConfig())
+	})
+
+	client, err := db.RunSchemaMigration()
+	require.NoError(t, err)
+	store := db.NewEntStore(client)
+	t.Cleanup(func() {
+		require.NoError(t, store.Close())
+	})
+	storetest.Run(t, store)
+}
@@ -0,0 +1,191 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"entgo.io/ent/dialect"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/eroshiva/cloudtalk/pkg/client/db"
+
+// tracedDriver wraps ent driver and records a span for each query and transaction.
+type tracedDriver struct {
+	dialect.Driver
+	tracer trace.Tracer
+}
+
+// newTracedDriver returns driver, which traces all operations performed with the underlying driver.
+func newTracedDriver(drv dialect.Driver) dialect.Driver {
+	return &tracedDriver{Driver: drv, tracer: otel.Tracer(tracerName)}
+}
+
+// startSpan starts a client span of the DB operation. Only the statement is recorded, arguments are omitted.
+func startSpan(ctx context.Context, tracer trace.Tracer, name, query string) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(dbSystemName()),
+	}
+	if query != "" {
+		opts = append(opts, trace.WithAttributes(semconv.DBQueryText(query)))
+		name = operationName(query)
+	}
+	return tracer.Start(ctx, name, opts...)
+}
+
+// dbSystemName returns attribute identifying the configured DB.
+func dbSystemName() attribute.KeyValue {
+	if cfg.Driver == DriverSQLite {
+		return semconv.DBSystemNameSQLite
+	}
+	return semconv.DBSystemNamePostgreSQL
+}
+
+// operationName returns the SQL keyword the query starts with (e.g., SELECT), which serves as a span name.
+func operationName(query string) string {
+	op, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	return strings.ToUpper(op)
+}
+
+// endSpan records the error, if any, and ends the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Exec traces the statement and executes it with the underlying driver.
+func (d *tracedDriver) Exec(ctx context.Context, query string, args, v any) error {
+	ctx, span := startSpan(ctx, d.tracer, "", query)
+	err := d.Driver.Exec(ctx, query, args, v)
+	endSpan(span, err)
+	return err
+}
+
+// Query traces the query and executes it with the underlying driver.
+func (d *tracedDriver) Query(ctx context.Context, query string, args, v any) error {
+	ctx, span := startSpan(ctx, d.tracer, "", query)
+	err := d.Driver.Query(ctx, query, args, v)
+	endSpan(span, err)
+	return err
+}
+
+// ExecContext traces the statement and executes it with the underlying driver, if it is supported.
+func (d *tracedDriver) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	drv, ok := d.Driver.(interface {
+		ExecContext(context.Context, string, ...any) (sql.Result, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("underlying driver does not support ExecContext")
+	}
+	ctx, span := startSpan(ctx, d.tracer, "", query)
+	res, err := drv.ExecContext(ctx, query, args...)
+	endSpan(span, err)
+	return res, err
+}
+
+// QueryContext traces the query and executes it with the underlying driver, if it is supported.
+func (d *tracedDriver) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	drv, ok := d.Driver.(interface {
+		QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("underlying driver does not support QueryContext")
+	}
+	ctx, span := startSpan(ctx, d.tracer, "", query)
+	rows, err := drv.QueryContext(ctx, query, args...)
+	endSpan(span, err)
+	return rows, err
+}
+
+// Tx starts a transaction, which is traced until it is committed or rolled back.
+func (d *tracedDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	return d.BeginTx(ctx, nil)
+}
+
+// BeginTx starts a transaction with the provided options, which is traced until it is committed or rolled back.
+func (d *tracedDriver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	ctx, span := startSpan(ctx, d.tracer, "transaction", "")
+	drv, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	})
+	var tx dialect.Tx
+	var err error
+	if ok {
+		tx, err = drv.BeginTx(ctx, opts)
+	} else {
+		tx, err = d.Driver.Tx(ctx)
+	}
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	return &tracedTx{Tx: tx, ctx: ctx, span: span, tracer: d.tracer}, nil
+}
+
+// tracedTx wraps ent transaction and records a span for each statement, commit and rollback.
+type tracedTx struct {
+	dialect.Tx
+	// ctx carries the span of the whole transaction, statements are recorded as its children
+	ctx    context.Context
+	span   trace.Span
+	tracer trace.Tracer
+}
+
+// Exec traces the statement as a part of the transaction and executes it.
+func (tx *tracedTx) Exec(ctx context.Context, query string, args, v any) error {
+	ctx, span := startSpan(trace.ContextWithSpan(ctx, tx.span), tx.tracer, "", query)
+	err := tx.Tx.Exec(ctx, query, args, v)
+	endSpan(span, err)
+	return err
+}
+
+// Query traces the query as a part of the transaction and executes it.
+func (tx *tracedTx) Query(ctx context.Context, query string, args, v any) error {
+	ctx, span := startSpan(trace.ContextWithSpan(ctx, tx.span), tx.tracer, "", query)
+	err := tx.Tx.Query(ctx, query, args, v)
+	endSpan(span, err)
+	return err
+}
+
+// ExecContext traces the statement as a part of the transaction and executes it with the underlying transaction,
+// if it is supported, e.g., to call functions, which are not modelled by ent.
+func (tx *tracedTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ex, ok := tx.Tx.(interface {
+		ExecContext(context.Context, string, ...any) (sql.Result, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("underlying transaction does not support ExecContext")
+	}
+	ctx, span := startSpan(trace.ContextWithSpan(ctx, tx.span), tx.tracer, "", query)
+	res, err := ex.ExecContext(ctx, query, args...)
+	endSpan(span, err)
+	return res, err
+}
+
+// Commit traces commit of the transaction and ends the span of the whole transaction.
+func (tx *tracedTx) Commit() error {
+	_, span := startSpan(tx.ctx, tx.tracer, "COMMIT", "")
+	err := tx.Tx.Commit()
+	endSpan(span, err)
+	endSpan(tx.span, err)
+	return err
+}
+
+// Rollback traces rollback of the transaction and ends the span of the whole transaction.
+func (tx *tracedTx) Rollback() error {
+	_, span := startSpan(tx.ctx, tx.tracer, "ROLLBACK", "")
+	err := tx.Tx.Rollback()
+	endSpan(span, err)
+	endSpan(tx.span, err)
+	return err
+}
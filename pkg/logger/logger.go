@@ -3,8 +3,11 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -12,8 +15,6 @@ import (
 
 const (
 	component = "component"
-	// envLogLevel specifies name of the environmental variable that sets the log level
-	envLogLevel = "LOG_LEVEL"
 
 	// set of log levels for illustrational purposes:
 	logLevelDebug    = "DEBUG"
@@ -25,43 +26,183 @@ const (
 	logLevelNoLevel  = "NO_LEVEL"
 	logLevelDisabled = "DISABLED"
 	logLevelTrace    = "TRACE"
+
+	// supported output formats
+	FormatConsole = "console"
+	FormatJSON    = "json"
 )
 
-var level = zerolog.DebugLevel // default log level
+// Config holds configuration of all loggers.
+type Config struct {
+	// Level is a log level of all components, DEBUG by default.
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+	// Levels overrides the log level per component, e.g., "server=INFO,db-client=WARN".
+	Levels string `yaml:"levels" env:"LOG_LEVELS"`
+	// Format is an output format, either console or json.
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+// DefaultConfig returns default configuration of the loggers.
+func DefaultConfig() Config {
+	return Config{
+		Level:  logLevelDebug,
+		Format: FormatConsole,
+	}
+}
+
+// Validate reports invalid log levels and format.
+func (c Config) Validate() error {
+	_, err := c.settings()
+	return err
+}
+
+// settings holds parsed configuration, which is shared by all loggers.
+type settings struct {
+	level           zerolog.Level
+	componentLevels map[string]zerolog.Level
+	format          string
+}
+
+// settings parses the configuration.
+func (c Config) settings() (*settings, error) {
+	lvl, err := parseLevel(c.Level)
+	if err != nil {
+		return nil, err
+	}
+	componentLevels, err := parseComponentLevels(c.Levels)
+	if err != nil {
+		return nil, err
+	}
+	format := strings.ToLower(strings.TrimSpace(c.Format))
+	switch format {
+	case "":
+		format = FormatConsole
+	case FormatConsole, FormatJSON:
+	default:
+		return nil, fmt.Errorf("unknown log format %q, expected %q or %q", c.Format, FormatConsole, FormatJSON)
+	}
+	return &settings{level: lvl, componentLevels: componentLevels, format: format}, nil
+}
+
+// levelOf returns log level of the component.
+func (s *settings) levelOf(name string) zerolog.Level {
+	if lvl, ok := s.componentLevels[name]; ok {
+		return lvl
+	}
+	return s.level
+}
+
+// current holds settings of all loggers, they may be changed at any time with Configure.
+var current atomic.Pointer[settings]
 
 func init() {
-	switch os.Getenv(envLogLevel) {
-	case logLevelDebug:
-		level = zerolog.DebugLevel
+	current.Store(&settings{level: zerolog.DebugLevel, format: FormatConsole})
+}
+
+// Configure applies the configuration to all loggers, including the already created ones.
+func Configure(cfg Config) error {
+	s, err := cfg.settings()
+	if err != nil {
+		return err
+	}
+	current.Store(s)
+	return nil
+}
+
+// parseLevel converts name of the log level to the zerolog level. Empty name stands for DEBUG.
+func parseLevel(name string) (zerolog.Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case logLevelDebug, "":
+		return zerolog.DebugLevel, nil
 	case logLevelInfo:
-		level = zerolog.InfoLevel
+		return zerolog.InfoLevel, nil
 	case logLevelWarn:
-		level = zerolog.WarnLevel
+		return zerolog.WarnLevel, nil
 	case logLevelError:
-		level = zerolog.ErrorLevel
+		return zerolog.ErrorLevel, nil
 	case logLevelFatal:
-		level = zerolog.FatalLevel
+		return zerolog.FatalLevel, nil
 	case logLevelPanic:
-		level = zerolog.PanicLevel
+		return zerolog.PanicLevel, nil
 	case logLevelNoLevel:
-		level = zerolog.NoLevel
+		return zerolog.NoLevel, nil
 	case logLevelDisabled:
-		level = zerolog.Disabled
+		return zerolog.Disabled, nil
 	case logLevelTrace:
-		level = zerolog.TraceLevel
+		return zerolog.TraceLevel, nil
 	default:
-		// default log level is DEBUG
-		level = zerolog.DebugLevel
+		return zerolog.DebugLevel, fmt.Errorf("unknown log level %q", name)
+	}
+}
+
+// parseComponentLevels parses comma-separated list of component=LEVEL pairs.
+func parseComponentLevels(spec string) (map[string]zerolog.Level, error) {
+	levels := make(map[string]zerolog.Level)
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, lvl, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("malformed component log level %q, expected component=LEVEL", pair)
+		}
+		level, err := parseLevel(lvl)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", strings.TrimSpace(name), err)
+		}
+		levels[strings.TrimSpace(name)] = level
 	}
+	return levels, nil
 }
 
-// NewLogger returns a wrapper for new logger instance
+// NewLogger returns a wrapper for new logger instance.
+// Events carrying a context (see zerolog.Event.Ctx()) are annotated with the request ID stored in the context.
 func NewLogger(name string) zerolog.Logger {
-	return zerolog.New(zerolog.ConsoleWriter{
-		Out:        os.Stderr,
-		TimeFormat: time.RFC3339,
-		FormatCaller: func(i interface{}) string {
-			return filepath.Dir(fmt.Sprintf("%s/", i))
+	return newLogger(name, os.Stderr, &current)
+}
+
+// newLogger creates logger of the component, which writes to the provided writer. Level and format
+// of the logger follow the settings, thus all events are created and filtered once they are written.
+func newLogger(name string, w io.Writer, s *atomic.Pointer[settings]) zerolog.Logger {
+	fw := &formatWriter{
+		settings: s,
+		json:     w,
+		console: zerolog.ConsoleWriter{
+			Out:        w,
+			TimeFormat: time.RFC3339,
+			FormatCaller: func(i interface{}) string {
+				return filepath.Dir(fmt.Sprintf("%s/", i))
+			},
 		},
-	}).Level(level).With().Caller().Timestamp().Str(component, name).Logger()
+	}
+	return zerolog.New(fw).Level(zerolog.TraceLevel).Hook(levelHook{name: name, settings: s}).Hook(requestIDHook{}).
+		With().Caller().Timestamp().Str(component, name).Logger()
+}
+
+// levelHook discards events below the current log level of the component.
+type levelHook struct {
+	name     string
+	settings *atomic.Pointer[settings]
+}
+
+// Run discards the event, if its level is below the current log level of the component.
+func (h levelHook) Run(e *zerolog.Event, lvl zerolog.Level, _ string) {
+	if lvl < h.settings.Load().levelOf(h.name) {
+		e.Discard()
+	}
+}
+
+// formatWriter writes events in the current output format.
+type formatWriter struct {
+	settings *atomic.Pointer[settings]
+	json     io.Writer
+	console  zerolog.ConsoleWriter
+}
+
+// Write writes the event, which is encoded in JSON by zerolog, either as is or in a human-friendly format.
+func (w *formatWriter) Write(p []byte) (int, error) {
+	if w.settings.Load().format == FormatJSON {
+		return w.json.Write(p)
+	}
+	return w.console.Write(p)
 }
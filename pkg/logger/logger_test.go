@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSettings returns settings parsed from the configuration.
+func newTestSettings(t *testing.T, cfg Config) *atomic.Pointer[settings] {
+	t.Helper()
+	s, err := cfg.settings()
+	require.NoError(t, err)
+	p := &atomic.Pointer[settings]{}
+	p.Store(s)
+	return p
+}
+
+func TestParseComponentLevels(t *testing.T) {
+	levels, err := parseComponentLevels("server=INFO, db-client=warn,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]zerolog.Level{
+		"server":    zerolog.InfoLevel,
+		"db-client": zerolog.WarnLevel,
+	}, levels)
+
+	_, err = parseComponentLevels("server=INFO,malformed")
+	require.Error(t, err)
+	_, err = parseComponentLevels("=ERROR")
+	require.Error(t, err)
+	_, err = parseComponentLevels("server=LOUD")
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, DefaultConfig().Validate())
+	require.Error(t, Config{Level: "verbose"}.Validate())
+	require.Error(t, Config{Format: "xml"}.Validate())
+}
+
+func TestJSONLogWithRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	log := newLogger("server", &buf, newTestSettings(t, Config{Level: logLevelInfo, Format: FormatJSON}))
+
+	log.Debug().Msg("filtered out")
+	log.Info().Ctx(WithRequestID(context.Background(), "req-1")).Msg("with request ID")
+	log.Info().Msg("without request ID")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	entry := make(map[string]any)
+	require.NoError(t, json.Unmarshal(lines[0], &entry))
+	assert.Equal(t, "server", entry[component])
+	assert.Equal(t, "req-1", entry[requestIDField])
+	assert.Equal(t, "with request ID", entry[zerolog.MessageFieldName])
+
+	entry = make(map[string]any)
+	require.NoError(t, json.Unmarshal(lines[1], &entry))
+	assert.NotContains(t, entry, requestIDField)
+}
+
+func TestReconfigure(t *testing.T) {
+	var buf bytes.Buffer
+	s := newTestSettings(t, Config{Level: logLevelInfo, Format: FormatJSON})
+	log := newLogger("server", &buf, s)
+
+	log.Debug().Msg("filtered out")
+	assert.Empty(t, buf.String())
+
+	// already created logger follows the new settings
+	s.Store(newTestSettings(t, Config{Level: logLevelWarn, Levels: "server=DEBUG", Format: FormatConsole}).Load())
+	log.Debug().Msg("logged in console format")
+	assert.Contains(t, buf.String(), "logged in console format")
+	assert.NotContains(t, buf.String(), "{")
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// RequestIDHeader is a header (and gRPC metadata key), which carries ID of the request.
+	RequestIDHeader = "x-request-id"
+	// requestIDField is a name of the log field carrying ID of the request.
+	requestIDField = "request_id"
+)
+
+// requestIDKey is a context key of the request ID.
+type requestIDKey struct{}
+
+// WithRequestID returns context carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns request ID carried by the context, or an empty string if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDHook annotates log events with the request ID carried by their context.
+type requestIDHook struct{}
+
+// Run adds the request ID to the event, if the event carries a context with one.
+func (requestIDHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	if id := RequestIDFromContext(e.GetCtx()); id != "" {
+		e.Str(requestIDField, id)
+	}
+}
@@ -0,0 +1,245 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const brokerTestTimeout = 5 * time.Second
+
+// configureBroker configures topology unique to the test and returns connection to RabbitMQ, which is used
+// to tear the topology down. Test is skipped, when RabbitMQ is not available.
+func configureBroker(t *testing.T) *amqp.Connection {
+	t.Helper()
+	cfg := DefaultConfig()
+	if u := os.Getenv("AMQP_URL"); u != "" {
+		cfg.URL = u
+	}
+	cfg.Queue = fmt.Sprintf("test_events_%d", time.Now().UnixNano())
+	cfg.InvalidationExchange = cfg.Queue + ".invalidation"
+	cfg.MaxRetries = 1
+	cfg.RetryDelay = 100 * time.Millisecond
+	Configure(cfg)
+
+	conn, err := amqp.Dial(cfg.URL)
+	if err != nil {
+		Configure(DefaultConfig())
+		t.Skipf("RabbitMQ is not available: %v", err)
+	}
+	t.Cleanup(func() {
+		ch, err := conn.Channel()
+		require.NoError(t, err)
+		for _, q := range []string{queueName, retryQueueName(1), deadLetterQueueName(), migrationHoldingQueueName()} {
+			_, err = ch.QueueDelete(q, false, false, false)
+			assert.NoError(t, err)
+		}
+		for _, e := range []string{exchangeName, deadLetterExchangeName(), invalidationExchangeName()} {
+			assert.NoError(t, ch.ExchangeDelete(e, false, false))
+		}
+		CloseConnection(conn)
+		Configure(DefaultConfig())
+	})
+	return conn
+}
+
+// connectBroker configures topology unique to the test and connects to RabbitMQ.
+func connectBroker(t *testing.T) (*amqp.Connection, *amqp.Channel) {
+	t.Helper()
+	admin := configureBroker(t)
+	conn, ch, err := Connect()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		CloseChannel(ch)
+		CloseConnection(conn)
+	})
+	return admin, ch
+}
+
+// confirmChannel opens a channel in confirm mode, as dlq tool does.
+func confirmChannel(t *testing.T, conn *amqp.Connection) *amqp.Channel {
+	t.Helper()
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+	require.NoError(t, ch.Confirm(false))
+	t.Cleanup(func() { CloseChannel(ch) })
+	return ch
+}
+
+// receive waits for the next delivery passed to the handler.
+func receive(t *testing.T, handled <-chan amqp.Delivery) amqp.Delivery {
+	t.Helper()
+	select {
+	case d := <-handled:
+		return d
+	case <-time.After(brokerTestTimeout):
+		require.FailNow(t, "message was not delivered")
+		return amqp.Delivery{}
+	}
+}
+
+func TestRetryDeadLetterAndRedrive(t *testing.T) {
+	admin, ch := connectBroker(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*brokerTestTimeout)
+	t.Cleanup(cancel)
+
+	msgs, err := ch.Consume(queueName, "", false, false, false, false, nil)
+	require.NoError(t, err)
+	var failing atomic.Bool
+	failing.Store(true)
+	handled := make(chan amqp.Delivery, 10)
+	go func() {
+		for d := range msgs {
+			_ = HandleDelivery(ctx, ch, d, func(_ context.Context, d amqp.Delivery) error {
+				handled <- d
+				if failing.Load() {
+					return errors.New("processing failed")
+				}
+				return nil
+			})
+		}
+	}()
+
+	require.NoError(t, PublishMessage(ctx, ch, "review created"))
+	// message fails and is retried once after the delay
+	assert.Equal(t, 0, retryCount(receive(t, handled).Headers))
+	assert.Equal(t, 1, retryCount(receive(t, handled).Headers))
+
+	// message, which has exhausted its retries, is parked in the DLQ
+	dlqCh := confirmChannel(t, admin)
+	var parked []amqp.Delivery
+	require.Eventually(t, func() bool {
+		parked, err = InspectDeadLetters(dlqCh, 10)
+		return err == nil && len(parked) == 1
+	}, brokerTestTimeout, 50*time.Millisecond)
+	assert.Equal(t, "review created", string(parked[0].Body))
+	assert.Equal(t, 1, retryCount(parked[0].Headers))
+
+	// re-driven message is processed again with its retry counter reset
+	failing.Store(false)
+	n, err := RedriveDeadLetters(ctx, dlqCh, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	redriven := receive(t, handled)
+	assert.Equal(t, "review created", string(redriven.Body))
+	assert.Equal(t, 0, retryCount(redriven.Headers))
+
+	parked, err = InspectDeadLetters(dlqCh, 10)
+	require.NoError(t, err)
+	assert.Empty(t, parked)
+	select {
+	case d := <-handled:
+		assert.Failf(t, "processed message was delivered again", "body: %s", d.Body)
+	case <-time.After(3 * retryDelay):
+	}
+}
+
+func TestInspectDeadLettersKeepsMessages(t *testing.T) {
+	admin, ch := connectBroker(t)
+	ctx, cancel := context.WithTimeout(context.Background(), brokerTestTimeout)
+	t.Cleanup(cancel)
+
+	for _, body := range []string{"first", "second"} {
+		require.NoError(t, publishConfirmed(ctx, ch, publishKindEvent, deadLetterExchangeName(), queueName,
+			amqp.Publishing{DeliveryMode: amqp.Persistent, Body: []byte(body)}))
+	}
+
+	dlqCh := confirmChannel(t, admin)
+	msgs, err := InspectDeadLetters(dlqCh, 1)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	// inspected messages are returned to the DLQ, so they are listed again
+	msgs, err = InspectDeadLetters(dlqCh, 10)
+	require.NoError(t, err)
+	bodies := make([]string, 0, len(msgs))
+	for _, d := range msgs {
+		bodies = append(bodies, string(d.Body))
+	}
+	assert.ElementsMatch(t, []string{"first", "second"}, bodies)
+
+	q, err := dlqCh.QueueDeclarePassive(deadLetterQueueName(), true, false, false, false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, q.Messages)
+}
+
+func TestLegacyQueueMigration(t *testing.T) {
+	admin := configureBroker(t)
+	ctx, cancel := context.WithTimeout(context.Background(), brokerTestTimeout)
+	t.Cleanup(cancel)
+
+	// main queue as declared by versions preceding the retries, with a pending message
+	legacy, err := admin.Channel()
+	require.NoError(t, err)
+	_, err = legacy.QueueDeclare(queueName, false, false, false, false, nil)
+	require.NoError(t, err)
+	require.NoError(t, legacy.Confirm(false))
+	require.NoError(t, publishConfirmed(ctx, legacy, publishKindEvent, "", queueName, amqp.Publishing{Body: []byte("pending")}))
+	CloseChannel(legacy)
+
+	conn, ch, err := Connect()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		CloseChannel(ch)
+		CloseConnection(conn)
+	})
+
+	// queue is durable with dead-lettering now, thus its declaration is equivalent
+	require.NoError(t, withChannel(admin, declareMainQueueOn))
+	// pending message has been moved back to the main queue
+	d, ok, err := ch.Get(queueName, true)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "pending", string(d.Body))
+	// holding queue has been removed
+	err = withChannel(admin, func(ch *amqp.Channel) error {
+		_, err := ch.QueueDeclarePassive(migrationHoldingQueueName(), true, false, false, false, nil)
+		return err
+	})
+	assert.True(t, isAMQPError(err, amqp.NotFound))
+}
+
+func TestRetryQueueWithTTLMigration(t *testing.T) {
+	admin := configureBroker(t)
+	ctx, cancel := context.WithTimeout(context.Background(), brokerTestTimeout)
+	t.Cleanup(cancel)
+
+	// retry queue as declared by versions setting the delay with the queue's TTL, with a pending message
+	legacy, err := admin.Channel()
+	require.NoError(t, err)
+	_, err = legacy.QueueDeclare(retryQueueName(1), true, false, false, false, amqp.Table{
+		"x-message-ttl":         time.Hour.Milliseconds(),
+		argDeadLetterExchange:   exchangeName,
+		argDeadLetterRoutingKey: queueName,
+	})
+	require.NoError(t, err)
+	require.NoError(t, legacy.Confirm(false))
+	require.NoError(t, publishConfirmed(ctx, legacy, publishKindRetry, "", retryQueueName(1), amqp.Publishing{Body: []byte("pending")}))
+	CloseChannel(legacy)
+
+	conn, ch, err := Connect()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		CloseChannel(ch)
+		CloseConnection(conn)
+	})
+
+	// retry queue has no TTL now, thus its declaration is equivalent
+	require.NoError(t, withChannel(admin, func(ch *amqp.Channel) error {
+		_, err := ch.QueueDeclare(retryQueueName(1), true, false, false, false, retryQueueArgs())
+		return err
+	}))
+	// pending message has been returned to the main queue
+	d, ok, err := ch.Get(queueName, true)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "pending", string(d.Body))
+}
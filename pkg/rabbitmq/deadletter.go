@@ -0,0 +1,152 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// retryCountHeader is a header carrying the number of retries a message has already gone through.
+const retryCountHeader = "x-retry-count"
+
+// Handler processes a single message consumed from RabbitMQ. Returning an error schedules the message for a retry.
+type Handler func(ctx context.Context, d amqp.Delivery) error
+
+// retryCount returns the number of retries the message has already gone through.
+func retryCount(headers amqp.Table) int {
+	switch v := headers[retryCountHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
+
+// HandleDelivery runs the handler against the delivery and acknowledges it.
+// When the handler fails, the message is re-published to the next delayed retry queue and the delivery is acknowledged
+// only once the broker confirms the re-published copy, thus channel must be in confirm mode (channels opened by Connect are).
+// Once all retries are exhausted, the message is rejected and parked in the DLQ.
+// Handler runs within a consumer span, which continues the trace propagated in the message headers.
+// Context passed to the handler carries request ID propagated in the message headers, if any.
+func HandleDelivery(ctx context.Context, ch *amqp.Channel, d amqp.Delivery, handler Handler) error {
+	ctx, span := startProcessSpan(ExtractRequestID(ctx, d), d)
+	defer span.End()
+
+	handlerErr := handler(ctx, d)
+	if handlerErr == nil {
+		return d.Ack(false)
+	}
+
+	span.RecordError(handlerErr)
+	span.SetStatus(codes.Error, handlerErr.Error())
+	attempt := retryCount(d.Headers) + 1
+	if attempt > maxRetries {
+		zlog.Error().Ctx(ctx).Err(handlerErr).Msgf("Message has exhausted all %d retries, moving it to %s", maxRetries, deadLetterQueueName())
+		return d.Nack(false, false) // main queue dead-letters rejected messages to the DLQ
+	}
+
+	zlog.Warn().Ctx(ctx).Err(handlerErr).Msgf("Failed to process message, retrying in %s (attempt %d out of %d)",
+		retryDelayFor(attempt), attempt, maxRetries)
+	headers := amqp.Table{}
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	headers[retryCountHeader] = int32(attempt)
+	err := publishConfirmed(ctx, ch, publishKindRetry,
+		"",                      // default exchange routes directly to the queue
+		retryQueueName(attempt), // routing key
+		amqp.Publishing{
+			Headers:      headers,
+			ContentType:  d.ContentType,
+			DeliveryMode: amqp.Persistent,
+			Expiration:   retryExpiration(attempt), // retry queue dead-letters the message once it expires
+			Body:         d.Body,
+		})
+	if err != nil {
+		zlog.Error().Ctx(ctx).Err(err).Msgf("Failed to schedule a retry, returning message to the queue")
+		return d.Nack(false, true)
+	}
+	return d.Ack(false)
+}
+
+// InspectDeadLetters returns up to limit messages parked in the DLQ. Messages are left in the DLQ.
+func InspectDeadLetters(ch *amqp.Channel, limit int) ([]amqp.Delivery, error) {
+	zlog.Info().Msgf("Inspecting up to %d messages in %s", limit, deadLetterQueueName())
+	msgs := make([]amqp.Delivery, 0)
+	for len(msgs) < limit {
+		d, ok, err := ch.Get(deadLetterQueueName(), false)
+		if err != nil {
+			zlog.Error().Err(err).Msgf("Failed to get a message from %s", deadLetterQueueName())
+			return nil, err
+		}
+		if !ok {
+			// DLQ is empty
+			break
+		}
+		msgs = append(msgs, d)
+	}
+	if len(msgs) > 0 {
+		// returning all fetched messages back to the DLQ at once
+		err := ch.Nack(msgs[len(msgs)-1].DeliveryTag, true, true)
+		if err != nil {
+			zlog.Error().Err(err).Msgf("Failed to return messages back to %s", deadLetterQueueName())
+			return nil, err
+		}
+	}
+	return msgs, nil
+}
+
+// RedriveDeadLetters moves up to limit messages from the DLQ back to the main exchange and resets their retry counter.
+// Message is removed from the DLQ only once the broker confirms its re-published copy. Returns the number of re-driven messages.
+func RedriveDeadLetters(ctx context.Context, ch *amqp.Channel, limit int) (int, error) {
+	zlog.Info().Ctx(ctx).Msgf("Re-driving up to %d messages from %s to %s", limit, deadLetterQueueName(), exchangeName)
+	redriven := 0
+	for redriven < limit {
+		d, ok, err := ch.Get(deadLetterQueueName(), false)
+		if err != nil {
+			zlog.Error().Ctx(ctx).Err(err).Msgf("Failed to get a message from %s", deadLetterQueueName())
+			return redriven, err
+		}
+		if !ok {
+			// DLQ is empty
+			break
+		}
+
+		headers := amqp.Table{}
+		for k, v := range d.Headers {
+			headers[k] = v
+		}
+		delete(headers, retryCountHeader)
+		err = publishConfirmed(ctx, ch, publishKindRedrive, exchangeName, d.RoutingKey, amqp.Publishing{
+			Headers:      headers,
+			ContentType:  d.ContentType,
+			DeliveryMode: amqp.Persistent,
+			Body:         d.Body,
+		})
+		if err != nil {
+			zlog.Error().Ctx(ctx).Err(err).Msgf("Failed to re-drive a message, returning it to %s", deadLetterQueueName())
+			return redriven, nackAndJoin(d, err)
+		}
+		if err = d.Ack(false); err != nil {
+			zlog.Error().Ctx(ctx).Err(err).Msgf("Failed to acknowledge re-driven message")
+			return redriven, err
+		}
+		redriven++
+	}
+	return redriven, nil
+}
+
+// nackAndJoin returns the message back to its queue and wraps the given error with the nack error if occurred.
+func nackAndJoin(d amqp.Delivery, err error) error {
+	if nerr := d.Nack(false, true); nerr != nil {
+		zlog.Error().Err(nerr).Msgf("Failed to return message back to the queue")
+		return errors.Join(err, nerr)
+	}
+	return err
+}
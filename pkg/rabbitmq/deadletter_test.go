@@ -0,0 +1,51 @@
+package rabbitmq
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryCount(t *testing.T) {
+	assert.Equal(t, 0, retryCount(nil))
+	assert.Equal(t, 0, retryCount(amqp.Table{}))
+	assert.Equal(t, 2, retryCount(amqp.Table{retryCountHeader: int32(2)}))
+	assert.Equal(t, 3, retryCount(amqp.Table{retryCountHeader: int64(3)}))
+	assert.Equal(t, 0, retryCount(amqp.Table{retryCountHeader: "malformed"}))
+}
+
+func TestRetryTopologyNaming(t *testing.T) {
+	queueName = defaultQueueName
+	retryDelay = time.Second
+
+	assert.Equal(t, "review_events.retry.1", retryQueueName(1))
+	assert.Equal(t, "review_events.dlx", deadLetterExchangeName())
+	assert.Equal(t, "review_events.dlq", deadLetterQueueName())
+
+	// delay doubles with each attempt
+	assert.Equal(t, time.Second, retryDelayFor(1))
+	assert.Equal(t, 2*time.Second, retryDelayFor(2))
+	assert.Equal(t, 4*time.Second, retryDelayFor(3))
+	// delay is set on each retried message in milliseconds, retry queues have no TTL
+	assert.Equal(t, "2000", retryExpiration(2))
+	assert.NotContains(t, retryQueueArgs(), "x-message-ttl")
+}
+
+func TestMigrationNaming(t *testing.T) {
+	queueName = defaultQueueName
+
+	assert.Equal(t, "review_events.migration.lock", migrationLockQueueName())
+	assert.Equal(t, "review_events.migration", migrationHoldingQueueName())
+	assert.Equal(t, amqp.Table{argDeadLetterExchange: "review_events.dlx"}, mainQueueArgs())
+}
+
+func TestIsAMQPError(t *testing.T) {
+	err := fmt.Errorf("failed to declare: %w", &amqp.Error{Code: amqp.PreconditionFailed, Reason: "inequivalent arg 'durable'"})
+	assert.True(t, isAMQPError(err, amqp.PreconditionFailed))
+	assert.False(t, isAMQPError(err, amqp.ResourceLocked))
+	assert.False(t, isAMQPError(errors.New("connection refused"), amqp.PreconditionFailed))
+}
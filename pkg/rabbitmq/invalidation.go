@@ -0,0 +1,70 @@
+package rabbitmq
+
+import (
+	"context"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const defaultInvalidationExchange = "cache_invalidation"
+
+// invalidationExchangeName returns name of the fanout exchange, which broadcasts cache invalidations to all replicas.
+func invalidationExchangeName() string {
+	return invalidationExchange
+}
+
+// PublishInvalidation broadcasts cache invalidation message to all replicas.
+// Invalidations are transient, they are meaningless for replicas, which are not running at the moment.
+func PublishInvalidation(ctx context.Context, ch *amqp.Channel, body []byte) error {
+	zlog.Debug().Ctx(ctx).Msgf("Broadcasting cache invalidation: '%s'", body)
+	err := publish(ctx, ch, publishKindInvalidation,
+		invalidationExchangeName(), // exchange
+		"",                         // routing key, ignored by fanout exchange
+		amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Transient,
+			Body:         body,
+		})
+	if err != nil {
+		zlog.Error().Ctx(ctx).Err(err).Msg("Failed to broadcast cache invalidation")
+		return err
+	}
+	return nil
+}
+
+// ConsumeInvalidations subscribes to cache invalidations broadcast by all replicas (including this one).
+// Each subscriber gets its own exclusive queue, which is removed once the channel is closed.
+func ConsumeInvalidations(ch *amqp.Channel) (<-chan amqp.Delivery, error) {
+	q, err := ch.QueueDeclare(
+		"",    // name, generated by the server
+		false, // durable
+		true,  // delete when unused
+		true,  // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	if err != nil {
+		zlog.Error().Err(err).Msg("Failed to declare a queue for cache invalidations")
+		return nil, err
+	}
+	err = ch.QueueBind(q.Name, "", invalidationExchangeName(), false, nil)
+	if err != nil {
+		zlog.Error().Err(err).Msgf("Failed to bind queue %s to exchange %s", q.Name, invalidationExchangeName())
+		return nil, err
+	}
+
+	msgs, err := ch.Consume(
+		q.Name, // queue
+		"",     // consumer
+		true,   // auto-ack, lost invalidation is bounded by the TTL of the cached entry
+		true,   // exclusive
+		false,  // no-local
+		false,  // no-wait
+		nil,    // args
+	)
+	if err != nil {
+		zlog.Error().Err(err).Msgf("Failed to register a consumer for queue (%s)", q.Name)
+		return nil, err
+	}
+	return msgs, nil
+}
@@ -0,0 +1,34 @@
+package rabbitmq
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const (
+	// kinds of published messages
+	publishKindEvent        = "event"
+	publishKindInvalidation = "invalidation"
+	publishKindRetry        = "retry"
+	publishKindRedrive      = "redrive"
+
+	// publish outcomes
+	outcomeSuccess = "success"
+	outcomeFailure = "failure"
+)
+
+var publishedMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "rabbitmq_published_messages_total",
+	Help: "Number of messages published to RabbitMQ by their kind and outcome.",
+}, []string{"kind", "outcome"})
+
+// observePublish counts published message of the provided kind.
+func observePublish(kind string, err error) {
+	outcome := outcomeSuccess
+	if err != nil {
+		outcome = outcomeFailure
+	}
+	publishedMessages.WithLabelValues(kind, outcome).Inc()
+}
+
+// NewCollector returns Prometheus collector of RabbitMQ-related metrics.
+func NewCollector() prometheus.Collector {
+	return publishedMessages
+}
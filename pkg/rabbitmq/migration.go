@@ -0,0 +1,262 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	migrationLockSuffix    = ".migration.lock"
+	migrationHoldingSuffix = ".migration"
+
+	// how long the replica waits for another one, which is migrating the main queue
+	migrationLockAttempts = 30
+	migrationLockDelay    = time.Second
+	// how many times pending messages are moved, while previous versions keep publishing to the legacy queue
+	migrationMoveAttempts = 5
+)
+
+// migrationLockQueueName returns name of the exclusive queue, which is held by the replica migrating the main queue.
+func migrationLockQueueName() string {
+	return queueName + migrationLockSuffix
+}
+
+// migrationHoldingQueueName returns name of the queue, which holds pending messages while the main queue is migrated.
+func migrationHoldingQueueName() string {
+	return queueName + migrationHoldingSuffix
+}
+
+// mainQueueArgs returns arguments of the main queue, rejected messages are routed to the DLQ.
+func mainQueueArgs() amqp.Table {
+	return amqp.Table{
+		argDeadLetterExchange: deadLetterExchangeName(),
+	}
+}
+
+// isAMQPError reports whether the error was raised by the broker with the provided reply code.
+func isAMQPError(err error, code int) bool {
+	var amqpErr *amqp.Error
+	return errors.As(err, &amqpErr) && amqpErr.Code == code
+}
+
+// withChannel runs fn on a dedicated channel. Broker closes the channel on the failed declaration, thus the failure
+// doesn't affect the channels of the caller.
+func withChannel(conn *amqp.Connection, fn func(ch *amqp.Channel) error) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close() //nolint:errcheck // channel may be already closed by the broker
+	return fn(ch)
+}
+
+// declareMainQueue declares the main queue durable with dead-lettering to the DLQ. Main queue declared by versions
+// preceding the retries (i.e., non-durable and without arguments) can't be redeclared with different properties,
+// thus it is migrated: pending messages are moved to a holding queue, the queue is deleted, declared again and
+// messages are moved back. Messages held by a migration, which was interrupted, are moved back as well.
+func declareMainQueue(conn *amqp.Connection) error {
+	err := withChannel(conn, declareMainQueueOn)
+	if isAMQPError(err, amqp.PreconditionFailed) {
+		err = migrateLegacyQueue(conn)
+	}
+	if err != nil {
+		return err
+	}
+	return restoreHeldMessages(conn)
+}
+
+// declareMainQueueOn declares the main queue on the channel.
+func declareMainQueueOn(ch *amqp.Channel) error {
+	_, err := ch.QueueDeclare(
+		queueName,       // name
+		true,            // durable
+		false,           // delete when unused
+		false,           // exclusive
+		false,           // no-wait
+		mainQueueArgs(), // arguments
+	)
+	return err
+}
+
+// migrateLegacyQueue migrates the main queue declared by a previous version. Only one replica migrates at a time,
+// others wait until it is finished.
+func migrateLegacyQueue(conn *amqp.Connection) error {
+	zlog.Warn().Msgf("Queue %s was declared by a previous version, migrating it to a durable queue with dead-lettering", queueName)
+	return withMigrationLock(conn, queueName, func(ch *amqp.Channel) error {
+		return migrateLegacyQueueLocked(conn, ch)
+	})
+}
+
+// withMigrationLock runs fn on the channel holding the migration lock. Only one replica migrates the queue at a time,
+// others wait until the lock is released.
+func withMigrationLock(conn *amqp.Connection, queue string, fn func(ch *amqp.Channel) error) error {
+	for attempt := 1; ; attempt++ {
+		ch, err := conn.Channel()
+		if err != nil {
+			return err
+		}
+		// exclusive queue is a lock, it is removed once its channel is closed
+		_, err = ch.QueueDeclare(migrationLockQueueName(), false, true, true, false, nil)
+		if err == nil {
+			err = fn(ch)
+			_ = ch.Close()
+			return err
+		}
+		_ = ch.Close()
+		if !isAMQPError(err, amqp.ResourceLocked) || attempt >= migrationLockAttempts {
+			return fmt.Errorf("failed to lock migration of queue %s: %w", queue, err)
+		}
+		zlog.Info().Msgf("Queue %s is being migrated by another replica, waiting", queue)
+		time.Sleep(migrationLockDelay)
+	}
+}
+
+// migrateLegacyQueueLocked migrates the main queue, while the migration lock is held.
+func migrateLegacyQueueLocked(conn *amqp.Connection, ch *amqp.Channel) error {
+	// another replica may have migrated the queue, while this one was waiting for the lock
+	err := withChannel(conn, declareMainQueueOn)
+	if !isAMQPError(err, amqp.PreconditionFailed) {
+		return err
+	}
+
+	if err = ch.Confirm(false); err != nil {
+		return err
+	}
+	if _, err = ch.QueueDeclare(migrationHoldingQueueName(), true, false, false, false, nil); err != nil {
+		return err
+	}
+	// previous versions may keep publishing to the legacy queue, thus it is deleted only once it is empty
+	if err = drainAndDelete(conn, ch, queueName, "", migrationHoldingQueueName()); err != nil {
+		return err
+	}
+	if err = declareMainQueueOn(ch); err != nil {
+		return err
+	}
+	zlog.Info().Msgf("Queue %s is migrated", queueName)
+	return nil
+}
+
+// drainAndDelete moves pending messages from the queue to the exchange with the routing key and deletes the queue
+// once it is empty. Messages may keep coming to the queue, thus they are moved repeatedly. Channel must be in
+// confirm mode.
+func drainAndDelete(conn *amqp.Connection, ch *amqp.Channel, queue, exchange, routingKey string) error {
+	for attempt := 1; ; attempt++ {
+		moved, err := moveMessages(ch, queue, exchange, routingKey)
+		if err != nil {
+			return err
+		}
+		zlog.Info().Msgf("Moved %d pending message(s) from queue %s to %s", moved, queue, routingKey)
+		err = withChannel(conn, func(dch *amqp.Channel) error {
+			_, err := dch.QueueDelete(queue, false, true, false)
+			return err
+		})
+		if err == nil {
+			return nil
+		}
+		if !isAMQPError(err, amqp.PreconditionFailed) || attempt >= migrationMoveAttempts {
+			return fmt.Errorf("failed to delete queue %s: %w", queue, err)
+		}
+	}
+}
+
+// declareRetryQueue declares the retry queue for the attempt. Delay is set on each retried message, thus the retry
+// queue has no TTL and changing the delay doesn't change its properties. Retry queue declared with a TTL by
+// a previous version can't be redeclared, thus its pending messages are returned to the main queue right away and
+// the queue is declared again.
+func declareRetryQueue(conn *amqp.Connection, attempt int) error {
+	declare := func(ch *amqp.Channel) error {
+		_, err := ch.QueueDeclare(retryQueueName(attempt), true, false, false, false, retryQueueArgs())
+		return err
+	}
+	err := withChannel(conn, declare)
+	if !isAMQPError(err, amqp.PreconditionFailed) {
+		return err
+	}
+
+	zlog.Warn().Msgf("Queue %s was declared by a previous version, migrating it to a queue without TTL", retryQueueName(attempt))
+	return withMigrationLock(conn, retryQueueName(attempt), func(ch *amqp.Channel) error {
+		// another replica may have migrated the queue, while this one was waiting for the lock
+		err := withChannel(conn, declare)
+		if !isAMQPError(err, amqp.PreconditionFailed) {
+			return err
+		}
+		if err = ch.Confirm(false); err != nil {
+			return err
+		}
+		// retried messages are delivered back to the main queue earlier than scheduled
+		if err = drainAndDelete(conn, ch, retryQueueName(attempt), exchangeName, queueName); err != nil {
+			return err
+		}
+		if err = declare(ch); err != nil {
+			return err
+		}
+		zlog.Info().Msgf("Queue %s is migrated", retryQueueName(attempt))
+		return nil
+	})
+}
+
+// restoreHeldMessages moves messages held by the migration back to the main queue and removes the holding queue.
+func restoreHeldMessages(conn *amqp.Connection) error {
+	err := withChannel(conn, func(ch *amqp.Channel) error {
+		_, err := ch.QueueDeclarePassive(migrationHoldingQueueName(), true, false, false, false, nil)
+		return err
+	})
+	if isAMQPError(err, amqp.NotFound) {
+		// nothing is held
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return withChannel(conn, func(ch *amqp.Channel) error {
+		if err := ch.Confirm(false); err != nil {
+			return err
+		}
+		// main queue has to be bound before messages are moved through the main exchange
+		if err := ch.QueueBind(queueName, queueName, exchangeName, false, nil); err != nil {
+			return err
+		}
+		moved, err := moveMessages(ch, migrationHoldingQueueName(), exchangeName, queueName)
+		if err != nil {
+			return err
+		}
+		zlog.Info().Msgf("Moved %d held message(s) back to queue %s", moved, queueName)
+		_, err = ch.QueueDelete(migrationHoldingQueueName(), false, true, false)
+		return err
+	})
+}
+
+// moveMessages moves all messages from the queue to the exchange with the routing key. Each message is removed from
+// the source queue only once the broker confirms its copy. Channel must be in confirm mode.
+func moveMessages(ch *amqp.Channel, queue, exchange, routingKey string) (int, error) {
+	moved := 0
+	for {
+		d, ok, err := ch.Get(queue, false)
+		if err != nil || !ok {
+			return moved, err
+		}
+		dc, err := ch.PublishWithDeferredConfirmWithContext(context.Background(), exchange, routingKey, false, false,
+			amqp.Publishing{
+				Headers:      d.Headers,
+				ContentType:  d.ContentType,
+				DeliveryMode: amqp.Persistent,
+				Timestamp:    d.Timestamp,
+				Body:         d.Body,
+			})
+		if err == nil {
+			err = waitConfirm(context.Background(), dc)
+		}
+		if err != nil {
+			return moved, nackAndJoin(d, err)
+		}
+		if err = d.Ack(false); err != nil {
+			return moved, err
+		}
+		moved++
+	}
+}
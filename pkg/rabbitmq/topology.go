@@ -0,0 +1,127 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	retryQueueSuffix      = ".retry."
+	deadLetterSuffix      = ".dlx"
+	deadLetterQueueSuffix = ".dlq"
+
+	// RabbitMQ's arguments for queue declaration
+	argDeadLetterExchange   = "x-dead-letter-exchange"
+	argDeadLetterRoutingKey = "x-dead-letter-routing-key"
+)
+
+// retryQueueName returns name of the retry queue for the specified attempt (starting from 1).
+func retryQueueName(attempt int) string {
+	return fmt.Sprintf("%s%s%d", queueName, retryQueueSuffix, attempt)
+}
+
+// deadLetterExchangeName returns name of the exchange, where exhausted messages are routed to.
+func deadLetterExchangeName() string {
+	return queueName + deadLetterSuffix
+}
+
+// deadLetterQueueName returns name of the queue, where exhausted messages are parked.
+func deadLetterQueueName() string {
+	return queueName + deadLetterQueueSuffix
+}
+
+// retryQueueArgs returns arguments of the retry queues, expired messages are dead-lettered back to the main exchange.
+func retryQueueArgs() amqp.Table {
+	return amqp.Table{
+		argDeadLetterExchange:   exchangeName,
+		argDeadLetterRoutingKey: queueName,
+	}
+}
+
+// retryExpiration returns expiration of the message retried in the specified attempt (starting from 1), in milliseconds.
+func retryExpiration(attempt int) string {
+	return strconv.FormatInt(retryDelayFor(attempt).Milliseconds(), 10)
+}
+
+// retryDelayFor returns delay for the specified attempt (starting from 1). Delay is doubled with each attempt.
+func retryDelayFor(attempt int) time.Duration {
+	return retryDelay * time.Duration(1<<(attempt-1))
+}
+
+// declareTopology declares main exchange and main queue, set of delayed retry queues and a dead-letter queue.
+// Topology looks as follows:
+//   - main exchange routes messages to the main queue,
+//   - messages, which failed to be processed, are re-published to the retry queue matching their attempt.
+//     Each retried message expires after the delay of its attempt and the retry queue dead-letters it back to
+//     the main exchange,
+//   - messages, which exhausted all retries, are rejected and main queue dead-letters them to the DLQ.
+//
+// Main queue and retry queues declared by a previous version are migrated (see declareMainQueue and declareRetryQueue),
+// the other declarations run on the channel.
+// Additionally, a fanout exchange for broadcasting cache invalidations between replicas is declared.
+func declareTopology(conn *amqp.Connection, ch *amqp.Channel) error {
+	// declaring main exchange
+	err := ch.ExchangeDeclare(
+		exchangeName,        // name
+		amqp.ExchangeDirect, // kind
+		true,                // durable
+		false,               // auto-deleted
+		false,               // internal
+		false,               // no-wait
+		nil,                 // arguments
+	)
+	if err != nil {
+		zlog.Error().Err(err).Msgf("Failed to declare an exchange %s", exchangeName)
+		return err
+	}
+
+	// declaring dead-letter exchange and DLQ
+	err = ch.ExchangeDeclare(deadLetterExchangeName(), amqp.ExchangeFanout, true, false, false, false, nil)
+	if err != nil {
+		zlog.Error().Err(err).Msgf("Failed to declare an exchange %s", deadLetterExchangeName())
+		return err
+	}
+	_, err = ch.QueueDeclare(deadLetterQueueName(), true, false, false, false, nil)
+	if err != nil {
+		zlog.Error().Err(err).Msgf("Failed to declare a queue %s", deadLetterQueueName())
+		return err
+	}
+	err = ch.QueueBind(deadLetterQueueName(), "", deadLetterExchangeName(), false, nil)
+	if err != nil {
+		zlog.Error().Err(err).Msgf("Failed to bind queue %s", deadLetterQueueName())
+		return err
+	}
+
+	// declaring/fetching the main queue, rejected messages are routed to the DLQ
+	err = declareMainQueue(conn)
+	if err != nil {
+		zlog.Error().Err(err).Msgf("Failed to declare a queue %s", queueName)
+		return err
+	}
+	err = ch.QueueBind(queueName, queueName, exchangeName, false, nil)
+	if err != nil {
+		zlog.Error().Err(err).Msgf("Failed to bind queue %s to exchange %s", queueName, exchangeName)
+		return err
+	}
+
+	// declaring cache invalidation exchange, so invalidations can be published even before any replica subscribes
+	err = ch.ExchangeDeclare(invalidationExchangeName(), amqp.ExchangeFanout, true, false, false, false, nil)
+	if err != nil {
+		zlog.Error().Err(err).Msgf("Failed to declare an exchange %s", invalidationExchangeName())
+		return err
+	}
+
+	// declaring retry queues, each of them returns expired messages back to the main exchange
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		err = declareRetryQueue(conn, attempt)
+		if err != nil {
+			zlog.Error().Err(err).Msgf("Failed to declare a queue %s", retryQueueName(attempt))
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/eroshiva/cloudtalk/pkg/logger"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/eroshiva/cloudtalk/pkg/rabbitmq"
+
+var (
+	errNoConfirmMode = errors.New("channel is not in confirm mode")
+	errNotConfirmed  = errors.New("message was not confirmed by the broker")
+)
+
+// headerCarrier adapts AMQP headers to the OpenTelemetry propagation.TextMapCarrier.
+type headerCarrier amqp.Table
+
+// Get returns the value stored under the key, or an empty string if there is none.
+func (c headerCarrier) Get(key string) string {
+	v, ok := c[key].(string)
+	if !ok {
+		return ""
+	}
+	return v
+}
+
+// Set stores the value under the key.
+func (c headerCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Keys lists all keys stored in the carrier.
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// publish sends out the message within a producer span. Trace context and request ID are injected into the message headers,
+// so consumers can continue the trace.
+func publish(ctx context.Context, ch *amqp.Channel, kind, exchange, routingKey string, msg amqp.Publishing) error {
+	return sendMessage(ctx, ch, kind, exchange, routingKey, msg, false)
+}
+
+// publishConfirmed sends out the message as publish does and waits until the broker confirms, that it has taken
+// responsibility for the message. Channel must be in confirm mode (channels opened by Connect are).
+func publishConfirmed(ctx context.Context, ch *amqp.Channel, kind, exchange, routingKey string, msg amqp.Publishing) error {
+	return sendMessage(ctx, ch, kind, exchange, routingKey, msg, true)
+}
+
+// sendMessage sends out the message within a producer span and optionally waits for the publisher confirm.
+func sendMessage(ctx context.Context, ch *amqp.Channel, kind, exchange, routingKey string, msg amqp.Publishing, confirm bool) error {
+	destination := exchange
+	if destination == "" {
+		// default exchange routes directly to the queue named by the routing key
+		destination = routingKey
+	}
+	ctx, span := otel.Tracer(tracerName).Start(ctx, fmt.Sprintf("publish %s", destination),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitMQ,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(exchange),
+			semconv.MessagingRabbitMQDestinationRoutingKey(routingKey),
+			attribute.String("messaging.message.kind", kind),
+		))
+	defer span.End()
+
+	headers := amqp.Table{}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+	if id := logger.RequestIDFromContext(ctx); id != "" {
+		// correlating the event with the request, which caused it
+		headers[logger.RequestIDHeader] = id
+	}
+	msg.Headers = headers
+
+	dc, err := ch.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, false, false, msg)
+	if err == nil && confirm {
+		err = waitConfirm(ctx, dc)
+	}
+	observePublish(kind, err)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// waitConfirm waits for the publisher confirm of the message. Message, which was nacked by the broker or whose
+// channel was closed before it was confirmed, may be lost and has to be published again.
+func waitConfirm(ctx context.Context, dc *amqp.DeferredConfirmation) error {
+	if dc == nil {
+		return errNoConfirmMode
+	}
+	acked, err := dc.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for publisher confirm: %w", err)
+	}
+	if !acked {
+		return errNotConfirmed
+	}
+	return nil
+}
+
+// startProcessSpan starts a consumer span of the delivery, which continues the trace propagated in its headers.
+func startProcessSpan(ctx context.Context, d amqp.Delivery) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ExtractTraceContext(ctx, d), fmt.Sprintf("process %s", d.RoutingKey),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitMQ,
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(d.Exchange),
+			semconv.MessagingRabbitMQDestinationRoutingKey(d.RoutingKey),
+		))
+}
+
+// ExtractTraceContext returns context carrying trace context propagated in the headers of the delivery.
+func ExtractTraceContext(ctx context.Context, d amqp.Delivery) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, headerCarrier(d.Headers))
+}
+
+// ExtractRequestID returns context carrying ID of the request, which caused the event, if it is present in the headers of the delivery.
+func ExtractRequestID(ctx context.Context, d amqp.Delivery) context.Context {
+	if id := headerCarrier(d.Headers).Get(logger.RequestIDHeader); id != "" {
+		return logger.WithRequestID(ctx, id)
+	}
+	return ctx
+}
@@ -0,0 +1,34 @@
+package rabbitmq
+
+import (
+	"context"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceContextPropagation(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "publish")
+	defer span.End()
+
+	// injecting trace context into the headers of the message, as it is done on publish
+	headers := amqp.Table{retryCountHeader: int32(1)}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+	assert.Contains(t, headers, "traceparent")
+	assert.Equal(t, int32(1), headers[retryCountHeader]) // other headers are preserved
+
+	// consumer continues the same trace
+	extracted := trace.SpanContextFromContext(ExtractTraceContext(context.Background(), amqp.Delivery{Headers: headers}))
+	assert.Equal(t, span.SpanContext().TraceID(), extracted.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), extracted.SpanID())
+
+	// message without trace context starts a new trace
+	assert.False(t, trace.SpanContextFromContext(ExtractTraceContext(context.Background(), amqp.Delivery{})).IsValid())
+}
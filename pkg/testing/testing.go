@@ -2,15 +2,16 @@
 package testing
 
 import (
-	"sync"
+	"context"
+	"errors"
+	"flag"
 	"time"
 
 	apiv1 "github.com/eroshiva/cloudtalk/api/v1"
+	"github.com/eroshiva/cloudtalk/internal/config"
 	"github.com/eroshiva/cloudtalk/internal/ent"
 	"github.com/eroshiva/cloudtalk/internal/server"
 	"github.com/eroshiva/cloudtalk/pkg/client/db"
-	"github.com/eroshiva/cloudtalk/pkg/rabbitmq"
-	amqp "github.com/rabbitmq/amqp091-go"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -24,11 +25,46 @@ const (
 
 // Setup function sets up testing environment. Currently, only uploading schema to the DB.
 func Setup() (*ent.Client, error) {
+	if _, err := loadConfig(); err != nil {
+		return nil, err
+	}
 	return db.RunSchemaMigration()
 }
 
+// loadConfig loads configuration from environment variables and applies it to the DB and RabbitMQ clients.
+// Testing DB is migrated to the latest schema.
+func loadConfig() (config.Config, error) {
+	cfg, err := config.NewLoader(flag.NewFlagSet("testing", flag.ContinueOnError)).Load()
+	if err != nil {
+		return config.Config{}, err
+	}
+	cfg.DB.Migrations = db.MigrationsApply
+	return cfg, cfg.Apply()
+}
+
+// NewMessaging creates messaging with the backend selected by configuration, e.g., for an embedded server.
+func NewMessaging() (server.Messaging, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return server.NewMessaging(cfg.Server.MessagingBackend)
+}
+
+// Environment holds clients of the service started for testing.
+type Environment struct {
+	DBClient *ent.Client
+	// Messaging is owned by the started service, it is closed once the service is stopped.
+	Messaging server.Messaging
+	// Client is a gRPC client of the started service.
+	Client apiv1.ProductReviewsServiceClient
+
+	cancel context.CancelFunc
+	done   chan error
+}
+
 // SetupFull function sets up testing environment.It uploads schema to the DB and starts gRPC and HTTP reverse proxy servers.
-func SetupFull(grpcServerAddress, httpServerAddress string) (*ent.Client, *amqp.Connection, *amqp.Channel, apiv1.ProductReviewsServiceClient, *sync.WaitGroup, chan bool, chan bool, error) {
+func SetupFull(grpcServerAddress, httpServerAddress string) (*Environment, error) {
 	if grpcServerAddress == "" {
 		grpcServerAddress = defaultGRPCTestServerAddress
 	}
@@ -37,50 +73,57 @@ func SetupFull(grpcServerAddress, httpServerAddress string) (*ent.Client, *amqp.
 		httpServerAddress = defaultHTTPTestServerAddress
 	}
 
-	client, err := db.RunSchemaMigration()
+	serviceConfig, err := loadConfig()
 	if err != nil {
-		return nil, nil, nil, nil, nil, nil, nil, err
+		return nil, err
 	}
 
-	rabbitMQConn, rabbitMQCh, err := rabbitmq.Connect()
+	client, err := db.RunSchemaMigration()
 	if err != nil {
-		return nil, nil, nil, nil, nil, nil, nil, err
+		return nil, err
 	}
 
-	wg := &sync.WaitGroup{}
-	termChan := make(chan bool, 1)
-	readyChan := make(chan bool, 1)
-	reverseProxyReadyChan := make(chan bool, 1)
-	reverseProxyTermChan := make(chan bool, 1)
+	messaging, err := server.NewMessaging(serviceConfig.Server.MessagingBackend)
+	if err != nil {
+		return nil, err
+	}
 
-	wg.Go(func() {
-		server.StartServer(grpcServerAddress, httpServerAddress, client, rabbitMQCh, wg, termChan, readyChan, reverseProxyReadyChan, reverseProxyTermChan)
-	})
-	// Waiting until both servers are up and running
-	<-readyChan
-	<-reverseProxyReadyChan
+	cfg := serviceConfig.Server
+	cfg.GRPCAddress = grpcServerAddress
+	cfg.HTTPAddress = httpServerAddress
+	cfg.Store = db.NewEntStore(client)
+	cfg.Messaging = messaging
+	// listeners are bound once the server is created, thus clients may connect right away
+	srv, err := server.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Run(ctx)
+	}()
 
 	// creating gRPC testing client
 	conn, err := grpc.NewClient(grpcServerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
-		return nil, nil, nil, nil, nil, nil, nil, err
+		cancel()
+		return nil, errors.Join(err, <-done)
 	}
 
-	grpcClient := apiv1.NewProductReviewsServiceClient(conn)
-
-	return client, rabbitMQConn, rabbitMQCh, grpcClient, wg, termChan, reverseProxyTermChan, nil
+	return &Environment{
+		DBClient:  client,
+		Messaging: messaging,
+		Client:    apiv1.NewProductReviewsServiceClient(conn),
+		cancel:    cancel,
+		done:      done,
+	}, nil
 }
 
-// TeardownFull function tears down testing suite including DB connection, gRPC and HTTP reverse proxy servers.
-func TeardownFull(client *ent.Client, rabbitMQConn *amqp.Connection, rabbitMQChan *amqp.Channel, wg *sync.WaitGroup, termChan, reverseProxyTermChan chan bool) {
-	close(termChan)
-	close(reverseProxyTermChan)
-	err := db.GracefullyCloseDBClient(client)
-	if err != nil {
+// TeardownFull function tears down testing suite. Servers are gracefully stopped and they close DB and RabbitMQ connections.
+func TeardownFull(env *Environment) {
+	env.cancel()
+	if err := <-env.done; err != nil {
 		panic(err)
 	}
-	rabbitmq.CloseConnection(rabbitMQConn)
-	rabbitmq.CloseChannel(rabbitMQChan)
-
-	wg.Wait()
 }
@@ -0,0 +1,252 @@
+// Package tlsconfig provides TLS configuration of the servers and clients, whose certificates are reloaded from disk.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/eroshiva/cloudtalk/pkg/logger"
+)
+
+// DefaultReloadInterval is a default period, in which certificate files are checked for changes.
+const DefaultReloadInterval = 30 * time.Second
+
+var zlog = logger.NewLogger("tls")
+
+// Config holds paths to the certificate files. TLS is disabled, unless the certificate is set.
+type Config struct {
+	// CertFile and KeyFile hold certificate of the gRPC server and the HTTP gateway and its private key (PEM).
+	CertFile string `yaml:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile  string `yaml:"key_file" env:"TLS_KEY_FILE"`
+	// ClientCAFile is a CA bundle verifying client certificates, it enables mutual TLS.
+	ClientCAFile string `yaml:"client_ca_file" env:"TLS_CLIENT_CA_FILE"`
+	// ServerCAFile is a CA bundle verifying certificate of the gRPC server by the gateway, system roots are used when empty.
+	ServerCAFile string `yaml:"server_ca_file" env:"TLS_SERVER_CA_FILE"`
+	// ServerName overrides the name verified in the certificate of the server, by default it is derived from its address.
+	ServerName     string        `yaml:"server_name" env:"TLS_SERVER_NAME"`
+	ReloadInterval time.Duration `yaml:"reload_interval" env:"TLS_RELOAD_INTERVAL"`
+}
+
+// DefaultConfig returns default configuration, which disables TLS.
+func DefaultConfig() Config {
+	return Config{ReloadInterval: DefaultReloadInterval}
+}
+
+// Validate reports inconsistent configuration.
+func (c Config) Validate() error {
+	var errs []error
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		errs = append(errs, fmt.Errorf("both cert_file and key_file must be set to enable TLS"))
+	}
+	if !c.Enabled() && c.ClientCAFile != "" {
+		errs = append(errs, fmt.Errorf("client_ca_file requires TLS to be enabled with cert_file and key_file"))
+	}
+	if c.ReloadInterval <= 0 {
+		errs = append(errs, fmt.Errorf("reload_interval must be positive, got %s", c.ReloadInterval))
+	}
+	return errors.Join(errs...)
+}
+
+// Enabled reports whether TLS is configured.
+func (c Config) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+// MutualTLS reports whether client certificates are required and verified.
+func (c Config) MutualTLS() bool {
+	return c.Enabled() && c.ClientCAFile != ""
+}
+
+// fileState identifies content of the file without reading it.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Reloader holds certificates loaded from disk and reloads them once the files change.
+// TLS configurations returned by the Reloader always use the most recently loaded certificates.
+type Reloader struct {
+	cfg Config
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	serverCAs *x509.CertPool // nil means system roots
+	states    map[string]fileState
+}
+
+// NewReloader loads certificates from disk. Failure to load any of them is returned as an error.
+func NewReloader(cfg Config) (*Reloader, error) {
+	r := &Reloader{cfg: cfg}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// files returns all configured certificate files.
+func (r *Reloader) files() []string {
+	files := make([]string, 0, 4)
+	for _, f := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile, r.cfg.ServerCAFile} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// Reload loads all certificates from disk. On failure, previously loaded certificates are kept in use.
+func (r *Reloader) Reload() error {
+	states := make(map[string]fileState)
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", f, err)
+		}
+		states[f] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate %s: %w", r.cfg.CertFile, err)
+	}
+	clientCAs, err := loadCertPool(r.cfg.ClientCAFile)
+	if err != nil {
+		return err
+	}
+	serverCAs, err := loadCertPool(r.cfg.ServerCAFile)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.serverCAs = serverCAs
+	r.states = states
+	return nil
+}
+
+// loadCertPool loads CA bundle from the file. Returns nil pool, if no file is specified.
+func loadCertPool(file string) (*x509.CertPool, error) {
+	if file == "" {
+		return nil, nil
+	}
+	pem, err := os.ReadFile(file) //nolint:gosec // path is set by the operator
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle %s: %w", file, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", file)
+	}
+	return pool, nil
+}
+
+// changed reports whether any of the certificate files has changed since it was loaded.
+func (r *Reloader) changed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for f, state := range r.states {
+		info, err := os.Stat(f)
+		if err != nil {
+			// file may be temporarily missing while being replaced
+			continue
+		}
+		if !info.ModTime().Equal(state.modTime) || info.Size() != state.size {
+			return true
+		}
+	}
+	return false
+}
+
+// Run checks certificate files for changes periodically and reloads them until stop is closed.
+func (r *Reloader) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(r.cfg.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if !r.changed() {
+				continue
+			}
+			if err := r.Reload(); err != nil {
+				zlog.Error().Err(err).Msg("Failed to reload certificates, keeping the previous ones")
+				continue
+			}
+			zlog.Info().Msgf("Reloaded certificate %s", r.cfg.CertFile)
+		}
+	}
+}
+
+// certificate returns the most recently loaded certificate.
+func (r *Reloader) certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+// ServerConfig returns TLS configuration of a server. Client certificates are required and verified,
+// if the client CA bundle is configured.
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+			}
+			if r.clientCAs != nil {
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+				cfg.ClientCAs = r.clientCAs
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// ClientConfig returns TLS configuration of a client connecting to the server with the provided name.
+// Certificate of the Reloader is presented to the server, when it is requested (i.e., for mutual TLS).
+// Server certificate is verified against the server CA bundle, or against system roots, if the bundle is not configured.
+func (r *Reloader) ClientConfig(serverName string) *tls.Config {
+	if r.cfg.ServerName != "" {
+		serverName = r.cfg.ServerName
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.certificate(), nil
+		},
+		// CA bundle may be reloaded, thus server certificate is verified against the current bundle on each handshake
+		InsecureSkipVerify: true, //nolint:gosec // certificate is verified in VerifyConnection
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			r.mu.RLock()
+			roots := r.serverCAs
+			r.mu.RUnlock()
+			if len(cs.PeerCertificates) == 0 {
+				return fmt.Errorf("server has not presented any certificate")
+			}
+			intermediates := x509.NewCertPool()
+			for _, c := range cs.PeerCertificates[1:] {
+				intermediates.AddCert(c)
+			}
+			_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+				DNSName:       serverName,
+				Roots:         roots,
+				Intermediates: intermediates,
+			})
+			return err
+		},
+	}
+}
@@ -0,0 +1,191 @@
+package tlsconfig_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eroshiva/cloudtalk/pkg/tlsconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// authority issues certificates for the tests.
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newAuthority(t *testing.T) *authority {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &authority{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue writes certificate for localhost with the provided serial number and its key to the files.
+func (a *authority) issue(t *testing.T, serial int64, certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+}
+
+// setup issues certificate of the service and returns configuration enabling mutual TLS.
+func setup(t *testing.T) (*authority, tlsconfig.Config) {
+	t.Helper()
+	dir := t.TempDir()
+	ca := newAuthority(t)
+	cfg := tlsconfig.Config{
+		CertFile:       filepath.Join(dir, "tls.crt"),
+		KeyFile:        filepath.Join(dir, "tls.key"),
+		ClientCAFile:   filepath.Join(dir, "ca.crt"),
+		ServerCAFile:   filepath.Join(dir, "ca.crt"),
+		ReloadInterval: tlsconfig.DefaultReloadInterval,
+	}
+	require.NoError(t, os.WriteFile(cfg.ClientCAFile, ca.pem, 0o600))
+	ca.issue(t, 2, cfg.CertFile, cfg.KeyFile)
+	return ca, cfg
+}
+
+// handshake connects client to the server, returns serial number of the server certificate and the error
+// observed by the server.
+func handshake(t *testing.T, server, client *tls.Config) (*big.Int, error) {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "localhost:0", server)
+	require.NoError(t, err)
+	defer lis.Close() //nolint:errcheck // test listener
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close() //nolint:errcheck // test connection
+		serverErr <- conn.(*tls.Conn).Handshake()
+	}()
+
+	conn, err := tls.Dial("tcp", lis.Addr().String(), client)
+	var serial *big.Int
+	if err == nil {
+		serial = conn.ConnectionState().PeerCertificates[0].SerialNumber
+		// with TLS 1.3, rejection of the client certificate is reported once the client reads
+		_, _ = conn.Read(make([]byte, 1))
+		_ = conn.Close()
+	}
+	return serial, <-serverErr
+}
+
+func TestMutualTLS(t *testing.T) {
+	_, cfg := setup(t)
+	certs, err := tlsconfig.NewReloader(cfg)
+	require.NoError(t, err)
+
+	serial, err := handshake(t, certs.ServerConfig(), certs.ClientConfig("localhost"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), serial.Int64())
+
+	// client without certificate is rejected
+	_, err = handshake(t, certs.ServerConfig(), &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: true}) //nolint:gosec // test client
+	require.Error(t, err)
+
+	// server certificate not matching the name is rejected by the client
+	_, err = handshake(t, certs.ServerConfig(), certs.ClientConfig("product-reviews"))
+	require.Error(t, err)
+}
+
+func TestReload(t *testing.T) {
+	ca, cfg := setup(t)
+	certs, err := tlsconfig.NewReloader(cfg)
+	require.NoError(t, err)
+
+	// rotated certificate is served without recreating the TLS configuration
+	server := certs.ServerConfig()
+	ca.issue(t, 3, cfg.CertFile, cfg.KeyFile)
+	require.NoError(t, certs.Reload())
+	serial, err := handshake(t, server, certs.ClientConfig("localhost"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), serial.Int64())
+
+	// broken certificate is not loaded, the previous one is kept in use
+	require.NoError(t, os.WriteFile(cfg.CertFile, []byte("broken"), 0o600))
+	require.Error(t, certs.Reload())
+	serial, err = handshake(t, server, certs.ClientConfig("localhost"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), serial.Int64())
+}
+
+func TestReloadOnChange(t *testing.T) {
+	ca, cfg := setup(t)
+	cfg.ReloadInterval = 10 * time.Millisecond
+	certs, err := tlsconfig.NewReloader(cfg)
+	require.NoError(t, err)
+	stop := make(chan struct{})
+	defer close(stop)
+	go certs.Run(stop)
+
+	ca.issue(t, 4, cfg.CertFile, cfg.KeyFile)
+	assert.Eventually(t, func() bool {
+		serial, err := handshake(t, certs.ServerConfig(), certs.ClientConfig("localhost"))
+		return err == nil && serial.Int64() == 4
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := tlsconfig.DefaultConfig()
+	require.NoError(t, cfg.Validate())
+	assert.False(t, cfg.Enabled())
+
+	cfg.CertFile = "tls.crt"
+	require.Error(t, cfg.Validate())
+
+	cfg.KeyFile = "tls.key"
+	cfg.ClientCAFile = "ca.crt"
+	require.NoError(t, cfg.Validate())
+	assert.True(t, cfg.MutualTLS())
+
+	cfg.ReloadInterval = -time.Second
+	require.Error(t, cfg.Validate())
+
+	// mutual TLS requires TLS to be enabled
+	require.Error(t, tlsconfig.Config{ClientCAFile: "ca.crt", ReloadInterval: time.Second}.Validate())
+}
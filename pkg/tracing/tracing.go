@@ -0,0 +1,148 @@
+// Package tracing configures OpenTelemetry tracing of the service.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/eroshiva/cloudtalk/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+const (
+	// supported exporters
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+
+	defaultExporter    = ExporterNone
+	defaultFile        = "traces.json"
+	defaultSampleRatio = 1.0
+	defaultServiceName = "product-reviews-service"
+)
+
+var zlog = logger.NewLogger("tracing")
+
+// Config holds configuration of the tracing.
+type Config struct {
+	// Exporter is one of none, otlp, stdout or file.
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+	// File is a path to the file, where spans are written by the file exporter.
+	File string `yaml:"file" env:"TRACING_FILE"`
+	// SampleRatio is a ratio of sampled traces, e.g., 0.1.
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+// DefaultConfig returns default configuration, which disables tracing.
+func DefaultConfig() Config {
+	return Config{
+		Exporter:    defaultExporter,
+		File:        defaultFile,
+		SampleRatio: defaultSampleRatio,
+	}
+}
+
+// Validate reports invalid values of the configuration.
+func (c Config) Validate() error {
+	var errs []error
+	switch c.Exporter {
+	case ExporterNone, ExporterOTLP, ExporterStdout:
+	case ExporterFile:
+		if c.File == "" {
+			errs = append(errs, fmt.Errorf("file must be set for %s exporter", ExporterFile))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown exporter %q, expected one of %q, %q, %q or %q",
+			c.Exporter, ExporterNone, ExporterOTLP, ExporterStdout, ExporterFile))
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("sample_ratio must be between 0 and 1, got %v", c.SampleRatio))
+	}
+	return errors.Join(errs...)
+}
+
+// ShutdownFunc flushes all pending spans and releases resources held by the exporter.
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup configures global tracer provider and propagator.
+// OTLP exporter is further configured with standard OTEL_EXPORTER_OTLP_* variables, e.g., OTEL_EXPORTER_OTLP_ENDPOINT.
+// Service name defaults to product-reviews-service and can be overridden with OTEL_SERVICE_NAME.
+func Setup(ctx context.Context, cfg Config) (ShutdownFunc, error) {
+	// trace context is propagated even if tracing is disabled, so traces are not broken by this service
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Exporter == ExporterNone {
+		zlog.Info().Msg("Tracing is disabled")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closer, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(defaultServiceName)),
+		resource.WithFromEnv(), // OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES take precedence
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	zlog.Info().Msgf("Tracing is enabled with %s exporter, sampling ratio is %v", cfg.Exporter, cfg.SampleRatio)
+
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if closer != nil {
+			if cerr := closer.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}, nil
+}
+
+// newExporter creates span exporter selected by the configuration. Returned closer, if any, must be closed after the exporter is shut down.
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		exporter, err := otlptracegrpc.New(ctx)
+		return exporter, nil, err
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		return exporter, nil, err
+	case ExporterFile:
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644) //nolint:gosec // path is set by the operator
+		if err != nil {
+			return nil, nil, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w (closing file: %w)", err, f.Close())
+		}
+		return exporter, f, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+}
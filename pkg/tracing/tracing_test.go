@@ -0,0 +1,44 @@
+package tracing_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eroshiva/cloudtalk/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.json")
+	cfg := tracing.DefaultConfig()
+	cfg.Exporter = tracing.ExporterFile
+	cfg.File = path
+
+	shutdown, err := tracing.Setup(context.Background(), cfg)
+	require.NoError(t, err)
+	_, span := otel.Tracer("test").Start(context.Background(), "CreateReview")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	// spans are flushed to the file on shutdown
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "CreateReview")
+	assert.Contains(t, string(data), "product-reviews-service")
+}
+
+func TestInvalidConfiguration(t *testing.T) {
+	cfg := tracing.DefaultConfig()
+	cfg.Exporter = "unknown"
+	_, err := tracing.Setup(context.Background(), cfg)
+	require.Error(t, err)
+
+	cfg.Exporter = tracing.ExporterStdout
+	cfg.SampleRatio = 2
+	_, err = tracing.Setup(context.Background(), cfg)
+	require.Error(t, err)
+}